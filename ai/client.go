@@ -8,7 +8,10 @@ import (
 	"encoding/json"
 	"fmt"
 	"io"
+	"log/slog"
 	"net/http"
+	"otom-ai/untrusted"
+	"strings"
 	"time"
 )
 
@@ -68,19 +71,16 @@ type chatResponse struct {
 	} `json:"error,omitempty"`
 }
 
-// SearchArgs contient les arguments parsés de l'outil search_internet.
-type SearchArgs struct {
-	Query string `json:"query"`
-}
-
 // ---------- Client ----------
 
 // Client encapsule la connexion à l'API DeepSeek.
 type Client struct {
-	apiKey     string
-	baseURL    string
-	model      string
-	httpClient *http.Client
+	apiKey      string
+	baseURL     string
+	model       string
+	temperature float64
+	httpClient  *http.Client
+	logger      *slog.Logger
 }
 
 // NewClient crée un nouveau client DeepSeek avec les paramètres donnés.
@@ -89,98 +89,145 @@ func NewClient(apiKey, baseURL, model string) *Client {
 		apiKey:  apiKey,
 		baseURL: baseURL,
 		model:   model,
+		// Entre 0.0 et 1.5, plus c'est élevé, plus les réponses sont créatives (et potentiellement incohérentes)
+		temperature: 0.2,
 		httpClient: &http.Client{
 			Timeout: 60 * time.Second, // Timeout généreux pour les réponses LLM
 		},
+		logger: slog.New(slog.DiscardHandler),
 	}
 }
 
-// SearchToolDef retourne la définition de l'outil de recherche web
-// au format OpenAI function calling.
-func SearchToolDef() ToolDef {
-	params := json.RawMessage(`{
-		"type": "object",
-		"properties": {
-			"query": {
-				"type": "string",
-				"description": "La requête de recherche web à effectuer pour trouver des informations récentes sur Dofus 3 Unity ou tout autre sujet."
-			}
-		},
-		"required": ["query"],
-		"additionalProperties": false
-	}`)
-
-	return ToolDef{
-		Type: "function",
-		Function: FunctionSchema{
-			Name:        "search_internet",
-			Description: "Recherche des informations récentes sur internet. Utilise cet outil quand tu as besoin d'informations actualisées, de news, ou de données que tu ne possèdes pas.",
-			Strict:      true,
-			Parameters:  params,
-		},
-	}
+// SetLogger définit le logger utilisé pour tracer les appels au LLM (niveau Debug).
+func (c *Client) SetLogger(logger *slog.Logger) {
+	c.logger = logger
 }
 
+// SetTemperature modifie la température utilisée pour les complétions.
+func (c *Client) SetTemperature(t float64) {
+	c.temperature = t
+}
+
+// SetTimeout modifie le timeout HTTP d'un appel au LLM.
+func (c *Client) SetTimeout(d time.Duration) {
+	c.httpClient.Timeout = d
+}
+
+// Model retourne le modèle utilisé par le client.
+func (c *Client) Model() string {
+	return c.model
+}
+
+// SetTransport remplace le transport HTTP du client (ex: enregistrement ou rejeu de cassettes).
+func (c *Client) SetTransport(rt http.RoundTripper) {
+	c.httpClient.Transport = rt
+}
+
+// maxToolRounds borne le nombre d'allers-retours outil ↔ LLM pour une même réponse.
+const maxToolRounds = 4
+
 // CompletionResult contient le résultat d'une complétion LLM avec métadonnées.
 type CompletionResult struct {
-	Reply          string // Réponse textuelle du LLM
-	WebSearchUsed  bool   // true si le LLM a déclenché une recherche web
-	WebSearchError error  // non-nil si la recherche web a échoué
-	WebSearchQuery string // Requête de recherche utilisée (si applicable)
+	Reply    string    // Réponse textuelle du LLM
+	ToolUses []ToolUse // Outils appelés par le LLM, dans l'ordre
+}
+
+// ToolUse décrit un appel d'outil effectué pendant une complétion.
+type ToolUse struct {
+	Name      string // Nom de l'outil
+	Arguments string // Arguments JSON transmis par le LLM
+	Error     error  // non-nil si l'outil a échoué
+}
+
+// Used indique si l'outil donné a été appelé au moins une fois.
+func (r *CompletionResult) Used(name string) bool {
+	for _, u := range r.ToolUses {
+		if u.Name == name {
+			return true
+		}
+	}
+	return false
 }
 
 // Complete envoie une requête de complétion au LLM et retourne le résultat avec métadonnées.
-// Si le LLM demande un outil, la fonction searchFn est appelée et un second appel est fait.
-func (c *Client) Complete(ctx context.Context, messages []Message, tools []ToolDef, searchFn func(ctx context.Context, query string) (string, error)) (*CompletionResult, error) {
+// Tant que le LLM demande des outils, ils sont exécutés et leurs résultats lui sont renvoyés
+// (au plus maxToolRounds fois, le dernier appel se faisant sans outils pour forcer une réponse).
+func (c *Client) Complete(ctx context.Context, messages []Message, tools []Tool) (*CompletionResult, error) {
 	result := &CompletionResult{}
 
-	// --- Premier appel ---
-	resp, err := c.call(ctx, messages, tools)
-	if err != nil {
-		return nil, err
+	defs := make([]ToolDef, 0, len(tools))
+	byName := make(map[string]Tool, len(tools))
+	for _, t := range tools {
+		defs = append(defs, t.Def)
+		byName[t.Def.Function.Name] = t
 	}
 
-	if len(resp.Choices) == 0 {
-		return nil, fmt.Errorf("réponse vide du LLM")
-	}
-	msg := resp.Choices[0].Message
-
-	// --- Détection du tool calling ---
-	if len(msg.ToolCalls) > 0 && searchFn != nil {
-		tc := msg.ToolCalls[0]
-		if tc.Function.Name == "search_internet" {
-			result.WebSearchUsed = true
-
-			var args SearchArgs
-			if err := json.Unmarshal([]byte(tc.Function.Arguments), &args); err != nil {
-				return nil, fmt.Errorf("arguments outil invalides: %w", err)
-			}
-			result.WebSearchQuery = args.Query
-
-			// Exécution de la recherche
-			searchResult, searchErr := searchFn(ctx, args.Query)
-			result.WebSearchError = searchErr
-
-			// Ajout du contexte outil dans l'historique (même en cas d'erreur, le fallback est passé)
-			messages = append(messages,
-				Message{Role: "assistant", ToolCalls: msg.ToolCalls},
-				Message{Role: "tool", ToolCallID: tc.ID, Content: searchResult},
-			)
+	for round := 0; ; round++ {
+		// Au-delà du nombre de tours autorisé, on retire les outils pour obtenir une réponse
+		roundDefs := defs
+		if round >= maxToolRounds {
+			roundDefs = nil
+		}
+
+		resp, err := c.call(ctx, messages, roundDefs)
+		if err != nil {
+			return nil, err
+		}
+		if len(resp.Choices) == 0 {
+			return nil, fmt.Errorf("réponse vide du LLM (appel %d)", round+1)
+		}
+		msg := resp.Choices[0].Message
+
+		// --- Pas d'outil demandé : réponse finale ---
+		if len(msg.ToolCalls) == 0 || len(roundDefs) == 0 {
+			result.Reply = msg.Content
+			return result, nil
+		}
 
-			// --- Second appel avec les résultats de recherche (sans outils) ---
-			resp, err = c.call(ctx, messages, nil)
-			if err != nil {
-				return nil, err
-			}
-			if len(resp.Choices) == 0 {
-				return nil, fmt.Errorf("réponse vide du LLM (second appel)")
-			}
-			msg = resp.Choices[0].Message
+		// --- Exécution des outils demandés ---
+		messages = append(messages, Message{Role: "assistant", Content: msg.Content, ToolCalls: msg.ToolCalls})
+		for _, tc := range msg.ToolCalls {
+			output, err := c.runTool(ctx, byName, tc)
+			result.ToolUses = append(result.ToolUses, ToolUse{
+				Name:      tc.Function.Name,
+				Arguments: tc.Function.Arguments,
+				Error:     err,
+			})
+			// Le résultat (ou le message de secours) est toujours renvoyé au LLM
+			messages = append(messages, Message{Role: "tool", ToolCallID: tc.ID, Content: output})
 		}
 	}
+}
 
-	result.Reply = msg.Content
-	return result, nil
+// runTool exécute un appel d'outil et retourne le texte à transmettre au LLM.
+// Le résultat d'un outil non fiable est placé dans un bloc délimité (sauf message de secours).
+func (c *Client) runTool(ctx context.Context, tools map[string]Tool, tc ToolCall) (string, error) {
+	tool, ok := tools[tc.Function.Name]
+	if !ok {
+		err := fmt.Errorf("outil inconnu: %s", tc.Function.Name)
+		return "ERREUR_OUTIL: " + err.Error(), err
+	}
+
+	start := time.Now()
+	output, err := tool.Handler(ctx, tc.Function.Arguments)
+	c.logger.Debug("Outil exécuté",
+		slog.String("tool", tc.Function.Name),
+		slog.String("arguments", tc.Function.Arguments),
+		slog.Duration("duration", time.Since(start)),
+	)
+	if err != nil && output == "" {
+		output = "ERREUR_OUTIL: " + err.Error()
+	}
+	if tool.Untrusted && err == nil {
+		if found := untrusted.Scan(output); len(found) > 0 {
+			c.logger.Warn("Consignes suspectes dans le résultat d'un outil",
+				slog.String("tool", tc.Function.Name),
+				slog.String("matches", strings.Join(found, " | ")),
+			)
+		}
+		output = untrusted.Wrap("résultat de "+tc.Function.Name, output)
+	}
+	return output, err
 }
 
 // call effectue un appel HTTP brut à l'API DeepSeek.
@@ -189,7 +236,7 @@ func (c *Client) call(ctx context.Context, messages []Message, tools []ToolDef)
 		Model:       c.model,
 		Messages:    messages,
 		Tools:       tools,
-		Temperature: 0.2, // Entre 0.0 et 1.5, plus c'est élevé, plus les réponses sont créatives (et potentiellement incohérentes)
+		Temperature: c.temperature,
 	}
 
 	body, err := json.Marshal(reqBody)
@@ -204,6 +251,7 @@ func (c *Client) call(ctx context.Context, messages []Message, tools []ToolDef)
 	req.Header.Set("Content-Type", "application/json")
 	req.Header.Set("Authorization", "Bearer "+c.apiKey)
 
+	start := time.Now()
 	resp, err := c.httpClient.Do(req)
 	if err != nil {
 		return nil, fmt.Errorf("erreur réseau: %w", err)
@@ -215,6 +263,14 @@ func (c *Client) call(ctx context.Context, messages []Message, tools []ToolDef)
 		return nil, fmt.Errorf("erreur de lecture: %w", err)
 	}
 
+	c.logger.Debug("Appel LLM",
+		slog.String("model", c.model),
+		slog.Int("messages", len(messages)),
+		slog.Int("tools", len(tools)),
+		slog.Int("status", resp.StatusCode),
+		slog.Duration("duration", time.Since(start)),
+	)
+
 	// Détection des erreurs HTTP avec messages personnalisés par code
 	if resp.StatusCode != http.StatusOK {
 		bodyStr := string(respBody)
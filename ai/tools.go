@@ -0,0 +1,649 @@
+package ai
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// ToolHandler exécute un outil à partir de ses arguments JSON (stringifiés par le LLM)
+// et retourne le texte renvoyé au LLM. En cas d'erreur, un texte non vide est tout de
+// même transmis au LLM (message de secours), sinon l'erreur elle-même lui est indiquée.
+type ToolHandler func(ctx context.Context, arguments string) (string, error)
+
+// Tool associe la définition d'un outil à son implémentation.
+type Tool struct {
+	Def     ToolDef
+	Handler ToolHandler
+	// Untrusted signale un résultat venu de l'extérieur (web, documents) : il est transmis
+	// au LLM dans un bloc de données non fiables (voir le package untrusted).
+	Untrusted bool
+}
+
+// SearchArgs contient les arguments parsés de l'outil search_internet.
+type SearchArgs struct {
+	Query string `json:"query"`
+}
+
+// LookupItemArgs contient les arguments parsés de l'outil lookup_item.
+type LookupItemArgs struct {
+	Name string `json:"name"`
+}
+
+// AlmanaxArgs contient les arguments parsés de l'outil get_almanax.
+type AlmanaxArgs struct {
+	Date string `json:"date"`
+}
+
+// KnowledgeBaseArgs contient les arguments parsés de l'outil search_knowledge_base.
+type KnowledgeBaseArgs struct {
+	Query string `json:"query"`
+}
+
+// FetchURLArgs contient les arguments parsés de l'outil fetch_url.
+type FetchURLArgs struct {
+	URL string `json:"url"`
+}
+
+// CraftCostArgs contient les arguments parsés de l'outil craft_cost.
+type CraftCostArgs struct {
+	Item     string `json:"item"`
+	Quantity int    `json:"quantity"`
+	Server   string `json:"server"`
+}
+
+// PlayerProfileArgs contient les arguments parsés de l'outil get_player_profile.
+type PlayerProfileArgs struct {
+	Player string `json:"player"`
+}
+
+// DamageArgs contient les arguments parsés de l'outil damage_calculator.
+type DamageArgs struct {
+	BaseMin       int `json:"base_min"`
+	BaseMax       int `json:"base_max"`
+	CritMin       int `json:"crit_min"`
+	CritMax       int `json:"crit_max"`
+	Stat          int `json:"stat"`
+	Power         int `json:"power"`
+	FixedDamage   int `json:"fixed_damage"`
+	CritDamage    int `json:"crit_damage"`
+	FinalPercent  int `json:"final_damage_percent"`
+	ResistPercent int `json:"resist_percent"`
+	ResistFixed   int `json:"resist_fixed"`
+	CritResist    int `json:"crit_resist"`
+	CritChance    int `json:"crit_chance"`
+}
+
+// StatPointsArgs contient les arguments parsés de l'outil stat_points_calculator.
+type StatPointsArgs struct {
+	Characteristic string `json:"characteristic"`
+	Current        int    `json:"current"`
+	Target         int    `json:"target"`
+	Scrolled       int    `json:"scrolled"`
+	Level          int    `json:"level"`
+}
+
+// XPArgs contient les arguments parsés de l'outil xp_calculator.
+type XPArgs struct {
+	Level       int   `json:"level"`
+	CurrentXP   int64 `json:"current_xp"`
+	TargetLevel int   `json:"target_level"`
+}
+
+// ReminderArgs contient les arguments parsés de l'outil set_reminder.
+type ReminderArgs struct {
+	When    string `json:"when"`
+	Text    string `json:"text"`
+	DM      bool   `json:"dm"`
+	Channel bool   `json:"channel"`
+}
+
+// DungeonRunArgs contient les arguments parsés de l'outil create_dungeon_run.
+type DungeonRunArgs struct {
+	Dungeon string `json:"dungeon"`
+	Start   string `json:"start"`
+	Tanks   int    `json:"tanks"`
+	Heals   int    `json:"heals"`
+	DPS     int    `json:"dps"`
+	Classes string `json:"classes"`
+	Note    string `json:"note"`
+}
+
+// SearchToolDef retourne la définition de l'outil de recherche web
+// au format OpenAI function calling.
+func SearchToolDef() ToolDef {
+	params := json.RawMessage(`{
+		"type": "object",
+		"properties": {
+			"query": {
+				"type": "string",
+				"description": "La requête de recherche web à effectuer pour trouver des informations récentes sur Dofus 3 Unity ou tout autre sujet."
+			}
+		},
+		"required": ["query"],
+		"additionalProperties": false
+	}`)
+
+	return ToolDef{
+		Type: "function",
+		Function: FunctionSchema{
+			Name:        "search_internet",
+			Description: "Recherche des informations récentes sur internet. Utilise cet outil quand tu as besoin d'informations actualisées, de news, ou de données que tu ne possèdes pas.",
+			Strict:      true,
+			Parameters:  params,
+		},
+	}
+}
+
+// SearchTool construit l'outil search_internet à partir d'une fonction de recherche.
+func SearchTool(search func(ctx context.Context, query string) (string, error)) Tool {
+	return Tool{
+		Def:       SearchToolDef(),
+		Untrusted: true,
+		Handler: func(ctx context.Context, arguments string) (string, error) {
+			var args SearchArgs
+			if err := json.Unmarshal([]byte(arguments), &args); err != nil {
+				return "", fmt.Errorf("arguments outil invalides: %w", err)
+			}
+			return search(ctx, args.Query)
+		},
+	}
+}
+
+// FetchURLToolDef retourne la définition de l'outil de lecture d'une page web.
+func FetchURLToolDef() ToolDef {
+	params := json.RawMessage(`{
+		"type": "object",
+		"properties": {
+			"url": {
+				"type": "string",
+				"description": "L'URL complète (https://...) de la page à lire, généralement issue d'un résultat de search_internet."
+			}
+		},
+		"required": ["url"],
+		"additionalProperties": false
+	}`)
+
+	return ToolDef{
+		Type: "function",
+		Function: FunctionSchema{
+			Name:        "fetch_url",
+			Description: "Télécharge une page web (notes de patch, guide, article) et retourne son contenu principal en Markdown. Utilise cet outil quand les extraits de search_internet ne suffisent pas pour répondre précisément. Seuls certains sites Dofus sont autorisés.",
+			Strict:      true,
+			Parameters:  params,
+		},
+	}
+}
+
+// FetchURLTool construit l'outil fetch_url à partir d'une fonction de téléchargement.
+func FetchURLTool(fetch func(ctx context.Context, url string) (string, error)) Tool {
+	return Tool{
+		Def:       FetchURLToolDef(),
+		Untrusted: true,
+		Handler: func(ctx context.Context, arguments string) (string, error) {
+			var args FetchURLArgs
+			if err := json.Unmarshal([]byte(arguments), &args); err != nil {
+				return "", fmt.Errorf("arguments outil invalides: %w", err)
+			}
+			return fetch(ctx, args.URL)
+		},
+	}
+}
+
+// LookupItemToolDef retourne la définition de l'outil de consultation de la base
+// locale d'objets, d'équipements, de panoplies et de ressources Dofus.
+func LookupItemToolDef() ToolDef {
+	params := json.RawMessage(`{
+		"type": "object",
+		"properties": {
+			"name": {
+				"type": "string",
+				"description": "Nom (même approximatif) de l'objet, de l'équipement, de la panoplie ou de la ressource Dofus recherché."
+			}
+		},
+		"required": ["name"],
+		"additionalProperties": false
+	}`)
+
+	return ToolDef{
+		Type: "function",
+		Function: FunctionSchema{
+			Name:        "lookup_item",
+			Description: "Consulte la base de données officielle locale des objets Dofus (statistiques, niveau, panoplie, recette). Utilise TOUJOURS cet outil en premier pour toute question sur un objet, un équipement, une panoplie ou une ressource. N'utilise search_internet que si l'objet est inconnu de la base.",
+			Strict:      true,
+			Parameters:  params,
+		},
+	}
+}
+
+// LookupItemTool construit l'outil lookup_item à partir d'une fonction de consultation de la base.
+func LookupItemTool(lookup func(name string) string) Tool {
+	return Tool{
+		Def: LookupItemToolDef(),
+		Handler: func(_ context.Context, arguments string) (string, error) {
+			var args LookupItemArgs
+			if err := json.Unmarshal([]byte(arguments), &args); err != nil {
+				return "", fmt.Errorf("arguments outil invalides: %w", err)
+			}
+			return lookup(args.Name), nil
+		},
+	}
+}
+
+// AlmanaxToolDef retourne la définition de l'outil donnant l'Almanax d'une date.
+func AlmanaxToolDef() ToolDef {
+	params := json.RawMessage(`{
+		"type": "object",
+		"properties": {
+			"date": {
+				"type": "string",
+				"description": "Date de l'Almanax demandé au format AAAA-MM-JJ. Calcule-la à partir de la date du jour (ex: \"vendredi\" = le prochain vendredi)."
+			}
+		},
+		"required": ["date"],
+		"additionalProperties": false
+	}`)
+
+	return ToolDef{
+		Type: "function",
+		Function: FunctionSchema{
+			Name:        "get_almanax",
+			Description: "Donne l'Almanax Dofus d'une date : offrande à apporter et bonus du jour (et Méryde quand il est connu). Utilise cet outil pour toute question sur l'Almanax.",
+			Strict:      true,
+			Parameters:  params,
+		},
+	}
+}
+
+// AlmanaxTool construit l'outil get_almanax. Les dates sont interprétées dans le fuseau loc.
+func AlmanaxTool(describe func(t time.Time) string, loc *time.Location) Tool {
+	return Tool{
+		Def: AlmanaxToolDef(),
+		Handler: func(_ context.Context, arguments string) (string, error) {
+			var args AlmanaxArgs
+			if err := json.Unmarshal([]byte(arguments), &args); err != nil {
+				return "", fmt.Errorf("arguments outil invalides: %w", err)
+			}
+			date, err := time.ParseInLocation("2006-01-02", args.Date, loc)
+			if err != nil {
+				return "", fmt.Errorf("date invalide %q (format attendu AAAA-MM-JJ)", args.Date)
+			}
+			return describe(date), nil
+		},
+	}
+}
+
+// KnowledgeBaseToolDef retourne la définition de l'outil de recherche dans la base de
+// connaissances locale (guides et FAQ indexés par les administrateurs).
+func KnowledgeBaseToolDef() ToolDef {
+	params := json.RawMessage(`{
+		"type": "object",
+		"properties": {
+			"query": {
+				"type": "string",
+				"description": "La question ou les mots-clés à rechercher dans les guides (ex: \"étapes de la quête du Dofus Ocre\")."
+			}
+		},
+		"required": ["query"],
+		"additionalProperties": false
+	}`)
+
+	return ToolDef{
+		Type: "function",
+		Function: FunctionSchema{
+			Name:        "search_knowledge_base",
+			Description: "Recherche dans la base de connaissances locale (guides de quêtes, de donjons, de métiers et FAQ de la guilde) et retourne les passages pertinents avec leur source. Utilise cet outil avant search_internet pour les questions de gameplay ; cite la source des passages utilisés.",
+			Strict:      true,
+			Parameters:  params,
+		},
+	}
+}
+
+// KnowledgeBaseTool construit l'outil search_knowledge_base à partir d'une fonction de recherche.
+func KnowledgeBaseTool(search func(ctx context.Context, query string) (string, error)) Tool {
+	return Tool{
+		Def:       KnowledgeBaseToolDef(),
+		Untrusted: true,
+		Handler: func(ctx context.Context, arguments string) (string, error) {
+			var args KnowledgeBaseArgs
+			if err := json.Unmarshal([]byte(arguments), &args); err != nil {
+				return "", fmt.Errorf("arguments outil invalides: %w", err)
+			}
+			return search(ctx, args.Query)
+		},
+	}
+}
+
+// CraftCostToolDef retourne la définition de l'outil de calcul du coût de fabrication.
+func CraftCostToolDef() ToolDef {
+	params := json.RawMessage(`{
+		"type": "object",
+		"properties": {
+			"item": {
+				"type": "string",
+				"description": "Nom de l'objet à fabriquer ou acheter."
+			},
+			"quantity": {
+				"type": "integer",
+				"description": "Nombre d'exemplaires (1 par défaut)."
+			},
+			"server": {
+				"type": "string",
+				"description": "Serveur de jeu Dofus (ex: Draconiros). Chaîne vide pour le serveur par défaut de la guilde."
+			}
+		},
+		"required": ["item", "quantity", "server"],
+		"additionalProperties": false
+	}`)
+
+	return ToolDef{
+		Type: "function",
+		Function: FunctionSchema{
+			Name:        "craft_cost",
+			Description: "Calcule le coût d'un objet à partir des prix HDV relevés par les joueurs : prix d'achat, coût de fabrication (recette complète, récursive) et option la moins chère, avec l'ancienneté des prix. Utilise cet outil pour toute question \"craft ou achat ?\" ou de budget en kamas, et n'invente jamais de prix.",
+			Strict:      true,
+			Parameters:  params,
+		},
+	}
+}
+
+// CraftCostTool construit l'outil craft_cost à partir d'une fonction de calcul.
+func CraftCostTool(cost func(item string, quantity int, server string) (string, error)) Tool {
+	return Tool{
+		Def: CraftCostToolDef(),
+		Handler: func(_ context.Context, arguments string) (string, error) {
+			var args CraftCostArgs
+			if err := json.Unmarshal([]byte(arguments), &args); err != nil {
+				return "", fmt.Errorf("arguments outil invalides: %w", err)
+			}
+			if args.Quantity < 1 {
+				args.Quantity = 1
+			}
+			return cost(args.Item, args.Quantity, args.Server)
+		},
+	}
+}
+
+// DungeonRunToolDef retourne la définition de l'outil d'organisation d'une sortie de groupe.
+func DungeonRunToolDef() ToolDef {
+	params := json.RawMessage(`{
+		"type": "object",
+		"properties": {
+			"dungeon": {
+				"type": "string",
+				"description": "Donjon ou boss visé (ex: Comte Harebourg)."
+			},
+			"start": {
+				"type": "string",
+				"description": "Date et heure de la sortie, telles que formulées par l'utilisateur (ex: samedi 21h, demain 20h30, 24/10 21h)."
+			},
+			"tanks": {
+				"type": "integer",
+				"description": "Places de tank. Mettre 0 aux trois rôles pour la composition par défaut (1 tank, 1 soin, 2 DPS)."
+			},
+			"heals": {
+				"type": "integer",
+				"description": "Places de soigneur."
+			},
+			"dps": {
+				"type": "integer",
+				"description": "Places de DPS."
+			},
+			"classes": {
+				"type": "string",
+				"description": "Classes souhaitées, chaîne vide si aucune."
+			},
+			"note": {
+				"type": "string",
+				"description": "Informations complémentaires (succès visés, niveau minimum...), chaîne vide si aucune."
+			}
+		},
+		"required": ["dungeon", "start", "tanks", "heals", "dps", "classes", "note"],
+		"additionalProperties": false
+	}`)
+
+	return ToolDef{
+		Type: "function",
+		Function: FunctionSchema{
+			Name:        "create_dungeon_run",
+			Description: "Organise une sortie donjon ou boss dans le salon courant : publie un message d'inscription par rôle (tank, soin, DPS) et programme un rappel aux inscrits. Utilise cet outil quand on te demande d'organiser, planifier ou monter un groupe pour un donjon.",
+			Strict:      true,
+			Parameters:  params,
+		},
+	}
+}
+
+// DungeonRunTool construit l'outil create_dungeon_run à partir d'une fonction de création.
+// Sans aucune place demandée, la composition par défaut est utilisée (1 tank, 1 soin, 2 DPS).
+func DungeonRunTool(create func(args DungeonRunArgs) (string, error)) Tool {
+	return Tool{
+		Def: DungeonRunToolDef(),
+		Handler: func(_ context.Context, arguments string) (string, error) {
+			var args DungeonRunArgs
+			if err := json.Unmarshal([]byte(arguments), &args); err != nil {
+				return "", fmt.Errorf("arguments outil invalides: %w", err)
+			}
+			if args.Tanks <= 0 && args.Heals <= 0 && args.DPS <= 0 {
+				args.Tanks, args.Heals, args.DPS = 1, 1, 2
+			}
+			return create(args)
+		},
+	}
+}
+
+// PlayerProfileToolDef retourne la définition de l'outil de consultation des profils de joueurs.
+func PlayerProfileToolDef() ToolDef {
+	params := json.RawMessage(`{
+		"type": "object",
+		"properties": {
+			"player": {
+				"type": "string",
+				"description": "Pseudo Discord, mention (<@id>) ou nom de personnage du joueur. Chaîne vide pour lister les profils de tout le serveur."
+			}
+		},
+		"required": ["player"],
+		"additionalProperties": false
+	}`)
+
+	return ToolDef{
+		Type: "function",
+		Function: FunctionSchema{
+			Name:        "get_player_profile",
+			Description: "Retourne les personnages enregistrés par les membres du serveur (classe, niveau, serveur de jeu, lien de build). Utilise cet outil pour les questions sur un autre joueur (\"quel niveau a Kiwi ?\", \"qui joue Eniripsa ?\").",
+			Strict:      true,
+			Parameters:  params,
+		},
+	}
+}
+
+// PlayerProfileTool construit l'outil get_player_profile à partir d'une fonction de recherche.
+func PlayerProfileTool(lookup func(player string) (string, error)) Tool {
+	return Tool{
+		Def: PlayerProfileToolDef(),
+		Handler: func(_ context.Context, arguments string) (string, error) {
+			var args PlayerProfileArgs
+			if err := json.Unmarshal([]byte(arguments), &args); err != nil {
+				return "", fmt.Errorf("arguments outil invalides: %w", err)
+			}
+			return lookup(args.Player)
+		},
+	}
+}
+
+// DamageToolDef retourne la définition de l'outil de calcul de dégâts.
+func DamageToolDef() ToolDef {
+	params := json.RawMessage(`{
+		"type": "object",
+		"properties": {
+			"base_min": {"type": "integer", "description": "Dégâts de base minimum du sort ou de l'arme."},
+			"base_max": {"type": "integer", "description": "Dégâts de base maximum."},
+			"crit_min": {"type": "integer", "description": "Dégâts de base minimum en coup critique (0 si identiques)."},
+			"crit_max": {"type": "integer", "description": "Dégâts de base maximum en coup critique (0 si identiques)."},
+			"stat": {"type": "integer", "description": "Caractéristique de l'élément du sort (Force pour terre et neutre, Intelligence pour feu, Chance pour eau, Agilité pour air)."},
+			"power": {"type": "integer", "description": "Puissance."},
+			"fixed_damage": {"type": "integer", "description": "Dommages fixes + dommages de l'élément du sort."},
+			"crit_damage": {"type": "integer", "description": "Dommages critiques (0 si aucun)."},
+			"final_damage_percent": {"type": "integer", "description": "Bonus cumulé de dommages finaux en % (dommages aux sorts, mêlée ou distance), 0 si aucun."},
+			"resist_percent": {"type": "integer", "description": "Résistance de la cible en % dans l'élément (négative pour une faiblesse), 0 si inconnue."},
+			"resist_fixed": {"type": "integer", "description": "Résistance fixe de la cible dans l'élément, 0 si inconnue."},
+			"crit_resist": {"type": "integer", "description": "Résistance critique de la cible, 0 si inconnue."},
+			"crit_chance": {"type": "integer", "description": "Probabilité de coup critique en % (0 à 100)."}
+		},
+		"required": ["base_min", "base_max", "crit_min", "crit_max", "stat", "power", "fixed_damage", "crit_damage", "final_damage_percent", "resist_percent", "resist_fixed", "crit_resist", "crit_chance"],
+		"additionalProperties": false
+	}`)
+
+	return ToolDef{
+		Type: "function",
+		Function: FunctionSchema{
+			Name:        "damage_calculator",
+			Description: "Calcule les dégâts d'un sort ou d'une arme (normaux, critiques, moyenne) à partir des caractéristiques du lanceur et des résistances de la cible. Utilise toujours cet outil plutôt que de calculer des dégâts de tête.",
+			Strict:      true,
+			Parameters:  params,
+		},
+	}
+}
+
+// DamageTool construit l'outil damage_calculator à partir d'une fonction de calcul.
+func DamageTool(compute func(args DamageArgs) (string, error)) Tool {
+	return Tool{
+		Def: DamageToolDef(),
+		Handler: func(_ context.Context, arguments string) (string, error) {
+			var args DamageArgs
+			if err := json.Unmarshal([]byte(arguments), &args); err != nil {
+				return "", fmt.Errorf("arguments outil invalides: %w", err)
+			}
+			return compute(args)
+		},
+	}
+}
+
+// StatPointsToolDef retourne la définition de l'outil de calcul des points de caractéristique.
+func StatPointsToolDef() ToolDef {
+	params := json.RawMessage(`{
+		"type": "object",
+		"properties": {
+			"characteristic": {"type": "string", "enum": ["Vitalité", "Sagesse", "Force", "Intelligence", "Chance", "Agilité"], "description": "Caractéristique concernée."},
+			"current": {"type": "integer", "minimum": 0, "maximum": 995, "description": "Points de capital déjà investis dans la caractéristique (hors parchemins), 0 si aucun."},
+			"target": {"type": "integer", "minimum": 0, "maximum": 995, "description": "Points investis visés (hors parchemins). 0 pour calculer le maximum atteignable au niveau donné."},
+			"scrolled": {"type": "integer", "minimum": 0, "maximum": 100, "description": "Points de parchemin déjà appris dans la caractéristique (0 à 100)."},
+			"level": {"type": "integer", "minimum": 0, "maximum": 200, "description": "Niveau du personnage (1 à 200), 0 si inconnu."}
+		},
+		"required": ["characteristic", "current", "target", "scrolled", "level"],
+		"additionalProperties": false
+	}`)
+
+	return ToolDef{
+		Type: "function",
+		Function: FunctionSchema{
+			Name:        "stat_points_calculator",
+			Description: "Calcule le coût en points de capital d'une répartition de caractéristiques (paliers de coût), le niveau nécessaire, le maximum atteignable à un niveau et les points de parchemin restants. Utilise cet outil pour toute question de répartition de points ou de parchemins.",
+			Strict:      true,
+			Parameters:  params,
+		},
+	}
+}
+
+// StatPointsTool construit l'outil stat_points_calculator à partir d'une fonction de calcul.
+func StatPointsTool(compute func(args StatPointsArgs) (string, error)) Tool {
+	return Tool{
+		Def: StatPointsToolDef(),
+		Handler: func(_ context.Context, arguments string) (string, error) {
+			var args StatPointsArgs
+			if err := json.Unmarshal([]byte(arguments), &args); err != nil {
+				return "", fmt.Errorf("arguments outil invalides: %w", err)
+			}
+			return compute(args)
+		},
+	}
+}
+
+// XPToolDef retourne la définition de l'outil de calcul d'expérience.
+func XPToolDef() ToolDef {
+	params := json.RawMessage(`{
+		"type": "object",
+		"properties": {
+			"level": {"type": "integer", "description": "Niveau actuel du personnage."},
+			"current_xp": {"type": "integer", "description": "Expérience totale actuelle affichée en jeu, 0 si inconnue (début du niveau)."},
+			"target_level": {"type": "integer", "description": "Niveau visé, 0 pour le niveau suivant."}
+		},
+		"required": ["level", "current_xp", "target_level"],
+		"additionalProperties": false
+	}`)
+
+	return ToolDef{
+		Type: "function",
+		Function: FunctionSchema{
+			Name:        "xp_calculator",
+			Description: "Calcule l'expérience manquante pour atteindre un niveau à partir de la table d'expérience officielle. Utilise cet outil plutôt que de citer des chiffres d'expérience de mémoire.",
+			Strict:      true,
+			Parameters:  params,
+		},
+	}
+}
+
+// XPTool construit l'outil xp_calculator à partir d'une fonction de calcul.
+func XPTool(compute func(level int, currentXP int64, target int) (string, error)) Tool {
+	return Tool{
+		Def: XPToolDef(),
+		Handler: func(_ context.Context, arguments string) (string, error) {
+			var args XPArgs
+			if err := json.Unmarshal([]byte(arguments), &args); err != nil {
+				return "", fmt.Errorf("arguments outil invalides: %w", err)
+			}
+			return compute(args.Level, args.CurrentXP, args.TargetLevel)
+		},
+	}
+}
+
+// ReminderToolDef retourne la définition de l'outil de programmation d'un rappel.
+func ReminderToolDef() ToolDef {
+	params := json.RawMessage(`{
+		"type": "object",
+		"properties": {
+			"when": {
+				"type": "string",
+				"description": "Date et heure du rappel, telles que formulées par l'utilisateur (ex: samedi 20h, demain 8h, dans 2h, 24/10 21h)."
+			},
+			"text": {
+				"type": "string",
+				"description": "Ce qu'il faut rappeler, formulé pour l'utilisateur (ex: Faire le donjon Bworker avec la clé)."
+			},
+			"dm": {
+				"type": "boolean",
+				"description": "true pour envoyer le rappel en message privé, false pour le publier dans le salon courant en mentionnant l'utilisateur."
+			},
+			"channel": {
+				"type": "boolean",
+				"description": "true pour prévenir tout le salon (@here) plutôt que l'utilisateur seul, uniquement s'il le demande explicitement (événement de guilde...). Ignoré en message privé."
+			}
+		},
+		"required": ["when", "text", "dm", "channel"],
+		"additionalProperties": false
+	}`)
+
+	return ToolDef{
+		Type: "function",
+		Function: FunctionSchema{
+			Name:        "set_reminder",
+			Description: "Programme un rappel pour l'utilisateur qui te parle (clés de donjon, Almanax, événement...). Le rappel survit aux redémarrages ; l'utilisateur peut lister et annuler ses rappels avec /rappels.",
+			Strict:      true,
+			Parameters:  params,
+		},
+	}
+}
+
+// ReminderTool construit l'outil set_reminder à partir d'une fonction de programmation.
+func ReminderTool(set func(args ReminderArgs) (string, error)) Tool {
+	return Tool{
+		Def: ReminderToolDef(),
+		Handler: func(_ context.Context, arguments string) (string, error) {
+			var args ReminderArgs
+			if err := json.Unmarshal([]byte(arguments), &args); err != nil {
+				return "", fmt.Errorf("arguments outil invalides: %w", err)
+			}
+			return set(args)
+		},
+	}
+}
@@ -0,0 +1,95 @@
+// Package almanax donne l'Almanax du jour (offrande, bonus, méridia) à partir d'un jeu
+// de données local. L'Almanax se répète d'une année sur l'autre : une entrée peut donc
+// être datée précisément ("2026-10-18") ou valoir pour tous les ans ("10-18").
+package almanax
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"otom-ai/frtime"
+	"strings"
+	"time"
+)
+
+// Day est l'Almanax d'une journée.
+type Day struct {
+	Date      string `json:"date"`       // "YYYY-MM-DD" ou "MM-DD" (annuel)
+	Meridia   string `json:"meridia"`    // Nom du Méryde du jour (saisi à la main, absent de l'import)
+	BonusType string `json:"bonus_type"` // Catégorie du bonus (ex: "Récolte")
+	Bonus     string `json:"bonus"`      // Description du bonus
+	Offering  string `json:"offering"`   // Ressource à offrir
+	Quantity  int    `json:"quantity"`   // Quantité à offrir
+}
+
+// Dataset est le format JSON du jeu de données.
+type Dataset struct {
+	Days []Day `json:"days"`
+}
+
+// Calendar indexe l'Almanax par date.
+type Calendar struct {
+	days map[string]Day
+}
+
+// Load charge le calendrier depuis un fichier JSON (voir Import et Fetch). Aucune donnée
+// n'est embarquée : le calendrier est vide si path est vide.
+func Load(path string) (*Calendar, error) {
+	if path == "" {
+		return &Calendar{days: map[string]Day{}}, nil
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("lecture de l'Almanax: %w", err)
+	}
+
+	var ds Dataset
+	if err := json.Unmarshal(data, &ds); err != nil {
+		return nil, fmt.Errorf("décodage de l'Almanax: %w", err)
+	}
+
+	c := &Calendar{days: make(map[string]Day, len(ds.Days))}
+	for _, d := range ds.Days {
+		c.days[d.Date] = d
+	}
+	return c, nil
+}
+
+// Len retourne le nombre de jours connus.
+func (c *Calendar) Len() int {
+	return len(c.days)
+}
+
+// On retourne l'Almanax d'une date : l'entrée datée exacte en priorité, sinon l'entrée annuelle.
+func (c *Calendar) On(t time.Time) (Day, bool) {
+	if d, ok := c.days[t.Format("2006-01-02")]; ok {
+		return d, true
+	}
+	d, ok := c.days[t.Format("01-02")]
+	return d, ok
+}
+
+// Describe retourne l'Almanax d'une date sous forme de texte (pour le LLM et les annonces).
+func (c *Calendar) Describe(t time.Time) string {
+	d, ok := c.On(t)
+	if !ok {
+		return fmt.Sprintf("INCONNU: pas de données d'Almanax pour le %s dans la base locale.", frtime.Date(t))
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "Almanax du %s\n", frtime.Date(t))
+	if d.Meridia != "" {
+		fmt.Fprintf(&b, "Méryde : %s\n", d.Meridia)
+	}
+	if d.Bonus != "" {
+		if d.BonusType != "" {
+			fmt.Fprintf(&b, "Bonus (%s) : %s\n", d.BonusType, d.Bonus)
+		} else {
+			fmt.Fprintf(&b, "Bonus : %s\n", d.Bonus)
+		}
+	}
+	if d.Offering != "" {
+		fmt.Fprintf(&b, "Offrande : %d x %s\n", max(d.Quantity, 1), d.Offering)
+	}
+	return b.String()
+}
@@ -0,0 +1,111 @@
+package almanax
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"otom-ai/fetch"
+	"strconv"
+	"time"
+)
+
+// DefaultSource est l'API communautaire interrogée par Fetch (dofusdude, données du jeu en français).
+const DefaultSource = "https://api.dofusdu.de/dofus3/v1/fr/almanax"
+
+// fetchChunk est le nombre de jours demandés par requête à l'API.
+const fetchChunk = 31
+
+// rawDay est un jour d'Almanax au format de l'API dofusdude.
+type rawDay struct {
+	Date  string `json:"date"`
+	Bonus struct {
+		Description string `json:"description"`
+		Type        struct {
+			Name string `json:"name"`
+		} `json:"type"`
+	} `json:"bonus"`
+	Tribute struct {
+		Item struct {
+			Name string `json:"name"`
+		} `json:"item"`
+		Quantity int `json:"quantity"`
+	} `json:"tribute"`
+}
+
+// convert convertit un jour brut au format du calendrier (entrée datée). L'API ne donne pas
+// le Méryde : Meridia reste vide.
+func (r rawDay) convert() Day {
+	return Day{
+		Date:      r.Date,
+		BonusType: r.Bonus.Type.Name,
+		Bonus:     r.Bonus.Description,
+		Offering:  r.Tribute.Item.Name,
+		Quantity:  r.Tribute.Quantity,
+	}
+}
+
+// Import convertit une réponse de l'API dofusdude (tableau de jours) au format du calendrier.
+// Les jours sans date valide sont ignorés.
+func Import(r io.Reader) (Dataset, error) {
+	var raw []rawDay
+	if err := json.NewDecoder(r).Decode(&raw); err != nil {
+		return Dataset{}, fmt.Errorf("format d'Almanax non reconnu: %w", err)
+	}
+	var ds Dataset
+	for _, d := range raw {
+		if _, err := time.Parse("2006-01-02", d.Date); err != nil {
+			continue
+		}
+		ds.Days = append(ds.Days, d.convert())
+	}
+	return ds, nil
+}
+
+// Fetch télécharge l'Almanax de days jours à partir de from depuis l'API source
+// (DefaultSource si vide), par tranches de fetchChunk jours.
+func Fetch(ctx context.Context, client *http.Client, source string, from time.Time, days int) (Dataset, error) {
+	if source == "" {
+		source = DefaultSource
+	}
+	var ds Dataset
+	for done := 0; done < days; done += fetchChunk {
+		chunk, err := fetchRange(ctx, client, source, from.AddDate(0, 0, done), min(fetchChunk, days-done))
+		if err != nil {
+			return Dataset{}, err
+		}
+		ds.Days = append(ds.Days, chunk.Days...)
+	}
+	return ds, nil
+}
+
+// fetchRange télécharge une tranche de l'Almanax.
+func fetchRange(ctx context.Context, client *http.Client, source string, from time.Time, size int) (Dataset, error) {
+	u, err := url.Parse(source)
+	if err != nil {
+		return Dataset{}, fmt.Errorf("adresse de l'Almanax invalide: %w", err)
+	}
+	q := u.Query()
+	q.Set("range[from]", from.Format("2006-01-02"))
+	q.Set("range[size]", strconv.Itoa(size))
+	u.RawQuery = q.Encode()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u.String(), nil)
+	if err != nil {
+		return Dataset{}, fmt.Errorf("requête de l'Almanax: %w", err)
+	}
+	req.Header.Set("User-Agent", fetch.UserAgent)
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return Dataset{}, fmt.Errorf("téléchargement de l'Almanax: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return Dataset{}, fmt.Errorf("téléchargement de l'Almanax du %s: statut %d", from.Format("2006-01-02"), resp.StatusCode)
+	}
+	return Import(resp.Body)
+}
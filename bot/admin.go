@@ -0,0 +1,145 @@
+package bot
+
+import (
+	"fmt"
+	"log/slog"
+	"otom-ai/logging"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+// adminPermission restreint l'affichage des commandes d'administration aux administrateurs.
+var adminPermission int64 = discordgo.PermissionAdministrator
+
+// maxLogBoost borne la durée d'une élévation temporaire du niveau de log.
+const maxLogBoost = 24 * time.Hour
+
+// adminCommand définit la commande /admin (réservée aux administrateurs du serveur).
+func (b *Bot) adminCommand() command {
+	levelChoices := []*discordgo.ApplicationCommandOptionChoice{
+		{Name: "debug", Value: "debug"},
+		{Name: "info", Value: "info"},
+		{Name: "warn", Value: "warn"},
+		{Name: "error", Value: "error"},
+	}
+	subsystemChoices := []*discordgo.ApplicationCommandOptionChoice{
+		{Name: "bot", Value: "bot"},
+		{Name: "ai", Value: "ai"},
+		{Name: "search", Value: "search"},
+		{Name: "fetch", Value: "fetch"},
+		{Name: "rag", Value: "rag"},
+		{Name: "moderation", Value: "moderation"},
+	}
+
+	return command{
+		def: &discordgo.ApplicationCommand{
+			Name:                     "admin",
+			Description:              "Commandes d'administration d'Otom-AI",
+			DefaultMemberPermissions: &adminPermission,
+			Options: []*discordgo.ApplicationCommandOption{
+				{
+					Type:        discordgo.ApplicationCommandOptionSubCommand,
+					Name:        "logs",
+					Description: "Change temporairement le niveau de log (opérateurs du bot, retour automatique)",
+					Options: []*discordgo.ApplicationCommandOption{
+						{Type: discordgo.ApplicationCommandOptionString, Name: "niveau", Description: "Niveau de log", Required: true, Choices: levelChoices},
+						{Type: discordgo.ApplicationCommandOptionString, Name: "duree", Description: "Durée avant retour au niveau configuré (ex: 15m, 1h). Défaut : 15m"},
+						{Type: discordgo.ApplicationCommandOptionString, Name: "sous-systeme", Description: "Limiter à un sous-système (défaut : tous)", Choices: subsystemChoices},
+					},
+				},
+				{
+					Type:        discordgo.ApplicationCommandOptionSubCommand,
+					Name:        "reindex",
+					Description: "Reconstruit l'index de la base de connaissances à partir des documents",
+					Options: []*discordgo.ApplicationCommandOption{
+						{Type: discordgo.ApplicationCommandOptionString, Name: "dossier", Description: "Sous-dossier du dossier des documents à indexer (défaut : tout)"},
+					},
+				},
+				{
+					Type:        discordgo.ApplicationCommandOptionSubCommand,
+					Name:        "avis",
+					Description: "Bilan des avis 👍/👎 sur les réponses du bot dans ce serveur",
+				},
+			},
+		},
+		handler: b.handleAdmin,
+	}
+}
+
+// handleAdmin traite la commande /admin.
+func (b *Bot) handleAdmin(s *discordgo.Session, i *discordgo.InteractionCreate) {
+	// Double vérification côté bot : les permissions par défaut sont modifiables par le serveur
+	if !isAdmin(i) {
+		b.respond(s, i, "🛡️ Halte-là ! Seuls les administrateurs de la guilde peuvent toucher à mes rouages.", true)
+		return
+	}
+
+	data := i.ApplicationCommandData()
+	if len(data.Options) == 0 {
+		return
+	}
+	sub := data.Options[0]
+
+	switch sub.Name {
+	case "logs":
+		b.handleAdminLogs(s, i, optionMap(sub.Options))
+	case "reindex":
+		b.handleAdminReindex(s, i, optionMap(sub.Options))
+	case "avis":
+		b.handleAdminFeedback(s, i)
+	}
+}
+
+// handleAdminLogs élève temporairement le niveau de log. Les niveaux valent pour tout le
+// processus (tous les serveurs) : la commande est réservée aux opérateurs du bot.
+func (b *Bot) handleAdminLogs(s *discordgo.Session, i *discordgo.InteractionCreate, opts map[string]*discordgo.ApplicationCommandInteractionDataOption) {
+	if !b.services.Load().cfg.IsOperator(interactionUser(i).ID) {
+		b.respond(s, i, "🛡️ Les niveaux de log valent pour tous les serveurs : seuls les opérateurs du bot (`discord.operators`) peuvent les changer.", true)
+		return
+	}
+
+	level, err := logging.ParseLevel(opts["niveau"].StringValue())
+	if err != nil {
+		b.respond(s, i, "❌ "+err.Error(), true)
+		return
+	}
+
+	duration := 15 * time.Minute
+	if o, ok := opts["duree"]; ok {
+		duration, err = time.ParseDuration(o.StringValue())
+		if err != nil || duration <= 0 || duration > maxLogBoost {
+			b.respond(s, i, fmt.Sprintf("❌ Durée invalide %q (ex: 15m, 1h, max %s).", o.StringValue(), maxLogBoost), true)
+			return
+		}
+	}
+
+	subsystem := ""
+	if o, ok := opts["sous-systeme"]; ok {
+		subsystem = o.StringValue()
+	}
+
+	b.logLevels.Boost(subsystem, level, duration, func() {
+		b.logger.Info("Niveau de log temporaire expiré, retour à la configuration",
+			slog.String("target", subsystemLabel(subsystem)),
+		)
+	})
+
+	b.logger.Warn("Niveau de log modifié temporairement",
+		slog.String("admin", interactionUser(i).Username),
+		slog.String("level", level.String()),
+		slog.String("target", subsystemLabel(subsystem)),
+		slog.Duration("duration", duration),
+	)
+	b.auditAdmin(s, i, "Niveau de log modifié", fmt.Sprintf("**%s** pour **%s** pendant %s", level.String(), subsystemLabel(subsystem), duration))
+	b.respond(s, i, fmt.Sprintf("🔧 Logs en **%s** pour **%s** pendant %s, retour automatique ensuite.",
+		level.String(), subsystemLabel(subsystem), duration), true)
+}
+
+// subsystemLabel retourne un libellé lisible pour un sous-système ("" = tous).
+func subsystemLabel(subsystem string) string {
+	if subsystem == "" {
+		return "tous les sous-systèmes"
+	}
+	return subsystem
+}
@@ -0,0 +1,95 @@
+package bot
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"otom-ai/frtime"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+// almanaxJobPrefix préfixe les tâches planifiées d'annonce de l'Almanax.
+const almanaxJobPrefix = "almanax:"
+
+// scheduleAlmanax (re)programme l'annonce quotidienne de l'Almanax pour chaque serveur configuré.
+func (b *Bot) scheduleAlmanax(svc *services) {
+	b.scheduler.CancelPrefix(almanaxJobPrefix)
+
+	if svc.almanax.Len() == 0 {
+		for guildID, g := range svc.cfg.Guilds {
+			if g.Almanax.Channel != "" {
+				b.logger.Warn("Annonce de l'Almanax non planifiée : aucune donnée (voir almanax import)", slog.String("guild", guildID))
+			}
+		}
+		return
+	}
+
+	loc := svc.cfg.Location()
+	for guildID, g := range svc.cfg.Guilds {
+		channelID := g.Almanax.Channel
+		if channelID == "" {
+			continue
+		}
+		err := b.scheduler.Daily(almanaxJobPrefix+guildID, g.Almanax.Time, loc, func(context.Context) {
+			b.announceAlmanax(channelID)
+		})
+		if err != nil {
+			b.logger.Error("Annonce de l'Almanax non planifiée",
+				slog.String("guild", guildID),
+				slog.String("error", err.Error()),
+			)
+		}
+	}
+}
+
+// announceAlmanax publie l'Almanax du jour dans le channel donné.
+func (b *Bot) announceAlmanax(channelID string) {
+	svc := b.services.Load()
+	today := time.Now().In(svc.cfg.Location())
+
+	day, ok := svc.almanax.On(today)
+	if !ok {
+		b.logger.Warn("Pas de données d'Almanax pour aujourd'hui",
+			slog.String("date", today.Format("2006-01-02")),
+		)
+		return
+	}
+
+	embed := &discordgo.MessageEmbed{
+		Title: "📅 Almanax du " + frtime.Date(today),
+		Color: 0xE8B923,
+		Footer: &discordgo.MessageEmbedFooter{
+			Text: "Pense à ton offrande avant de partir farmer !",
+		},
+	}
+	if day.Offering != "" {
+		embed.Fields = append(embed.Fields, &discordgo.MessageEmbedField{
+			Name:  "🎁 Offrande",
+			Value: formatOffering(day.Quantity, day.Offering),
+		})
+	}
+	if day.Bonus != "" {
+		name := "✨ Bonus"
+		if day.BonusType != "" {
+			name += " — " + day.BonusType
+		}
+		embed.Fields = append(embed.Fields, &discordgo.MessageEmbedField{Name: name, Value: day.Bonus})
+	}
+	if day.Meridia != "" {
+		embed.Fields = append(embed.Fields, &discordgo.MessageEmbedField{Name: "🙏 Méryde", Value: day.Meridia})
+	}
+
+	if _, err := b.session.ChannelMessageSendEmbed(channelID, embed); err != nil {
+		b.logger.Error("Impossible de publier l'Almanax",
+			slog.String("channel", channelID),
+			slog.String("error", err.Error()),
+		)
+	}
+}
+
+// formatOffering formate une offrande : "12 x Laine de Bouftou".
+func formatOffering(quantity int, offering string) string {
+	return fmt.Sprintf("%d x %s", max(quantity, 1), offering)
+}
@@ -0,0 +1,427 @@
+package bot
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"otom-ai/config"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+const (
+	// auditJob nomme la purge quotidienne des anciens événements des channels d'audit.
+	auditJob = "audit:retention"
+	// auditFooter signe les événements du journal d'audit (seuls ceux-ci sont purgés).
+	auditFooter = "Otom-AI · journal d'audit"
+	// auditOptedOut remplace le contenu d'un message dont l'auteur a refusé la lecture (/privacy optout).
+	auditOptedOut = "*(contenu non conservé : l'auteur a refusé la lecture de ses messages)*"
+	// bulkDeleteMaxAge est l'âge maximal d'un message supprimable en lot (14 jours pour Discord, avec une marge).
+	bulkDeleteMaxAge = 14*24*time.Hour - time.Hour
+	// discordEpoch est l'origine des horodatages des identifiants Discord (snowflakes), en millisecondes.
+	discordEpoch = 1420070400000
+)
+
+// Couleurs des événements du journal d'audit.
+const (
+	auditColorDeletion   = 0xE74C3C
+	auditColorEdit       = 0x3498DB
+	auditColorRateLimit  = 0xF1C40F
+	auditColorModeration = 0xE67E22
+	auditColorAdmin      = 0x9B59B6
+)
+
+// cachedMessage est un message d'un serveur journalisé, gardé en mémoire pour le journal d'audit.
+type cachedMessage struct {
+	guildID     string
+	channelID   string
+	authorID    string
+	author      string
+	content     string   // Vide si audit.content est désactivé ou si l'auteur a refusé la lecture de ses messages
+	attachments []string // Noms des pièces jointes
+	edited      time.Time
+	at          time.Time
+}
+
+// messageCache conserve en mémoire les derniers messages des serveurs journalisés, pour montrer
+// aux modérateurs le contenu d'un message supprimé ou modifié. Rien n'est écrit sur disque.
+type messageCache struct {
+	mu      sync.Mutex
+	entries map[string]cachedMessage
+	order   []string // IDs dans l'ordre d'arrivée, pour oublier les plus anciens
+}
+
+// put mémorise un message, en oubliant ceux plus anciens que ttl et les plus anciens au-delà de size.
+func (c *messageCache) put(id string, m cachedMessage, ttl time.Duration, size int) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.entries == nil {
+		c.entries = map[string]cachedMessage{}
+	}
+	c.entries[id] = m
+	c.order = append(c.order, id)
+
+	for len(c.order) > 0 {
+		oldest, ok := c.entries[c.order[0]]
+		if ok && len(c.entries) <= size && m.at.Sub(oldest.at) <= ttl {
+			break
+		}
+		delete(c.entries, c.order[0])
+		c.order = c.order[1:]
+	}
+}
+
+// get retourne un message encore en mémoire.
+func (c *messageCache) get(id string, ttl time.Duration, now time.Time) (cachedMessage, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	m, ok := c.entries[id]
+	if !ok || now.Sub(m.at) > ttl {
+		return cachedMessage{}, false
+	}
+	return m, true
+}
+
+// update remplace le contenu d'un message modifié.
+func (c *messageCache) update(id, content string, edited time.Time) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if m, ok := c.entries[id]; ok {
+		m.content = content
+		m.edited = edited
+		c.entries[id] = m
+	}
+}
+
+// take retourne un message encore en mémoire et l'oublie.
+func (c *messageCache) take(id string, ttl time.Duration, now time.Time) (cachedMessage, bool) {
+	m, ok := c.get(id, ttl, now)
+	c.mu.Lock()
+	delete(c.entries, id)
+	c.mu.Unlock()
+	return m, ok
+}
+
+// forgetUser oublie les messages d'un utilisateur.
+func (c *messageCache) forgetUser(userID string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for id, m := range c.entries {
+		if m.authorID == userID {
+			delete(c.entries, id)
+		}
+	}
+}
+
+// audit publie un événement dans le channel d'audit du serveur, s'il y est attendu.
+func (b *Bot) audit(s *discordgo.Session, svc *services, guildID, event string, embed *discordgo.MessageEmbed) {
+	channelID := svc.cfg.AuditChannel(guildID, event)
+	if channelID == "" {
+		return
+	}
+	embed.Footer = &discordgo.MessageEmbedFooter{Text: auditFooter}
+	embed.Timestamp = time.Now().Format(time.RFC3339)
+	if _, err := s.ChannelMessageSendEmbed(channelID, embed); err != nil {
+		b.logger.Error("Événement non publié dans le journal d'audit",
+			slog.String("event", event),
+			slog.String("channel", channelID),
+			slog.String("error", err.Error()),
+		)
+	}
+}
+
+// auditExcluded indique si un channel (ou le channel parent d'un fil) est exclu du journal d'audit.
+func (b *Bot) auditExcluded(s *discordgo.Session, svc *services, guildID, channelID string) bool {
+	excluded := svc.cfg.Guilds[guildID].Audit.Excluded
+	if len(excluded) == 0 {
+		return false
+	}
+	if slices.Contains(excluded, channelID) {
+		return true
+	}
+	ch := b.channel(s, channelID)
+	return ch != nil && ch.IsThread() && slices.Contains(excluded, ch.ParentID)
+}
+
+// trackMessage garde en mémoire un message d'un serveur dont les suppressions ou modifications
+// sont journalisées. Sans audit.content, ou pour un utilisateur ayant refusé la lecture de ses
+// messages (/privacy optout), seuls l'auteur et le channel sont conservés.
+func (b *Bot) trackMessage(s *discordgo.Session, svc *services, m *discordgo.Message) {
+	if svc.cfg.AuditChannel(m.GuildID, config.AuditDeletions) == "" && svc.cfg.AuditChannel(m.GuildID, config.AuditEdits) == "" {
+		return
+	}
+	if b.auditExcluded(s, svc, m.GuildID, m.ChannelID) {
+		return
+	}
+	cached := cachedMessage{
+		guildID:   m.GuildID,
+		channelID: m.ChannelID,
+		authorID:  m.Author.ID,
+		author:    m.Author.Username,
+		at:        m.Timestamp,
+	}
+	if svc.cfg.Audit.Content && !b.optedOut(m.Author.ID) {
+		cached.content = m.Content
+		for _, a := range m.Attachments {
+			cached.attachments = append(cached.attachments, a.Filename)
+		}
+	}
+	b.messages.put(m.ID, cached, svc.cfg.Audit.CacheTTL, svc.cfg.Audit.CacheSize)
+}
+
+// onMessageDelete publie un message supprimé dans le journal d'audit du serveur.
+// Seuls les messages encore en mémoire (voir trackMessage) peuvent être décrits.
+func (b *Bot) onMessageDelete(s *discordgo.Session, m *discordgo.MessageDelete) {
+	if m.GuildID == "" {
+		return
+	}
+	svc := b.services.Load()
+	cached, ok := b.messages.take(m.ID, svc.cfg.Audit.CacheTTL, time.Now())
+	if !ok {
+		return
+	}
+	b.logger.Info("Message supprimé",
+		slog.String("author", cached.author),
+		slog.String("channel", cached.channelID),
+	)
+
+	embed := &discordgo.MessageEmbed{
+		Title: "🗑️ Message supprimé",
+		Color: auditColorDeletion,
+		Fields: []*discordgo.MessageEmbedField{
+			{Name: "Auteur", Value: "<@" + cached.authorID + ">", Inline: true},
+			{Name: "Channel", Value: "<#" + cached.channelID + ">", Inline: true},
+			{Name: "Publié", Value: fmt.Sprintf("<t:%d:R>", cached.at.Unix()), Inline: true},
+		},
+	}
+	switch {
+	case b.optedOut(cached.authorID):
+		embed.Description = auditOptedOut
+	case svc.cfg.Audit.Content:
+		embed.Description = auditQuote(cached.content)
+		if len(cached.attachments) > 0 {
+			embed.Fields = append(embed.Fields, &discordgo.MessageEmbedField{
+				Name: "Pièces jointes", Value: truncate(strings.Join(cached.attachments, ", "), 1000),
+			})
+		}
+	}
+	b.audit(s, svc, m.GuildID, config.AuditDeletions, embed)
+}
+
+// onMessageUpdate publie un message modifié dans le journal d'audit du serveur, avec son contenu
+// avant et après modification. Les mises à jour sans modification (aperçus de liens) sont ignorées.
+func (b *Bot) onMessageUpdate(s *discordgo.Session, m *discordgo.MessageUpdate) {
+	if m.GuildID == "" || m.EditedTimestamp == nil {
+		return
+	}
+	svc := b.services.Load()
+	cached, ok := b.messages.get(m.ID, svc.cfg.Audit.CacheTTL, time.Now())
+	if !ok || m.EditedTimestamp.Equal(cached.edited) {
+		return
+	}
+	content := ""
+	if svc.cfg.Audit.Content && !b.optedOut(cached.authorID) {
+		content = m.Content
+	}
+	b.messages.update(m.ID, content, *m.EditedTimestamp)
+
+	embed := &discordgo.MessageEmbed{
+		Title: "✏️ Message modifié",
+		Color: auditColorEdit,
+		Fields: []*discordgo.MessageEmbedField{
+			{Name: "Auteur", Value: "<@" + cached.authorID + ">", Inline: true},
+			{Name: "Channel", Value: "<#" + cached.channelID + ">", Inline: true},
+			{Name: "Message", Value: messageLink(m.GuildID, m.ChannelID, m.ID), Inline: true},
+		},
+	}
+	switch {
+	case b.optedOut(cached.authorID):
+		embed.Description = auditOptedOut
+	case svc.cfg.Audit.Content:
+		embed.Fields = append(embed.Fields,
+			&discordgo.MessageEmbedField{Name: "Avant", Value: auditQuote(truncate(cached.content, 1000))},
+			&discordgo.MessageEmbedField{Name: "Après", Value: auditQuote(truncate(content, 1000))},
+		)
+	}
+	b.audit(s, svc, m.GuildID, config.AuditEdits, embed)
+}
+
+// auditRateLimit signale un utilisateur bloqué par le rate limit, au plus une fois par audit.abuse_cooldown.
+func (b *Bot) auditRateLimit(s *discordgo.Session, svc *services, guildID, channelID string, user *discordgo.User) {
+	if guildID == "" || svc.cfg.AuditChannel(guildID, config.AuditRateLimit) == "" || b.auditExcluded(s, svc, guildID, channelID) {
+		return
+	}
+	if allowed, _ := b.abuseReports.Allow(guildID + ":" + user.ID); !allowed {
+		return
+	}
+	limit := svc.cfg.RateLimit
+	b.audit(s, svc, guildID, config.AuditRateLimit, &discordgo.MessageEmbed{
+		Title:       "⏳ Rate limit atteint",
+		Description: fmt.Sprintf("<@%s> dépasse la limite de %d demandes par %s.", user.ID, limit.Requests, limit.Window),
+		Color:       auditColorRateLimit,
+		Fields: []*discordgo.MessageEmbedField{
+			{Name: "Utilisateur", Value: user.Username, Inline: true},
+			{Name: "Channel", Value: "<#" + channelID + ">", Inline: true},
+			{Name: "Prochain signalement", Value: "dans " + svc.cfg.Audit.AbuseCooldown.String() + " au plus tôt", Inline: true},
+		},
+	})
+}
+
+// auditAdmin publie une modification des réglages par un administrateur.
+func (b *Bot) auditAdmin(s *discordgo.Session, i *discordgo.InteractionCreate, action, details string) {
+	b.audit(s, b.services.Load(), i.GuildID, config.AuditAdmin, &discordgo.MessageEmbed{
+		Title:       "🔧 " + action,
+		Description: truncate(details, 2000),
+		Color:       auditColorAdmin,
+		Fields: []*discordgo.MessageEmbedField{
+			{Name: "Administrateur", Value: "<@" + interactionUser(i).ID + ">", Inline: true},
+			{Name: "Channel", Value: "<#" + i.ChannelID + ">", Inline: true},
+		},
+	})
+}
+
+// auditReload publie, sur chaque serveur concerné, les sections de la configuration modifiées
+// par un rechargement (noms seulement : les valeurs peuvent contenir des secrets).
+func (b *Bot) auditReload(old, cfg *config.Config) {
+	global := changedSections(old, cfg)
+	for guildID := range cfg.Guilds {
+		sections := slices.Clone(global)
+		if !reflect.DeepEqual(old.Guilds[guildID], cfg.Guilds[guildID]) {
+			sections = append(sections, "guilds."+guildID)
+		}
+		if len(sections) == 0 {
+			continue
+		}
+		b.audit(b.session, b.services.Load(), guildID, config.AuditAdmin, &discordgo.MessageEmbed{
+			Title:       "🔧 Configuration rechargée",
+			Description: "Sections modifiées : " + strings.Join(sections, ", "),
+			Color:       auditColorAdmin,
+		})
+	}
+}
+
+// changedSections retourne les sections de premier niveau de la configuration (hors guilds) qui diffèrent.
+func changedSections(old, cfg *config.Config) []string {
+	var sections []string
+	before, after := reflect.ValueOf(*old), reflect.ValueOf(*cfg)
+	for n := range before.NumField() {
+		f := before.Type().Field(n)
+		name, _, _ := strings.Cut(f.Tag.Get("yaml"), ",")
+		if !f.IsExported() || name == "guilds" {
+			continue
+		}
+		if !reflect.DeepEqual(before.Field(n).Interface(), after.Field(n).Interface()) {
+			sections = append(sections, name)
+		}
+	}
+	return sections
+}
+
+// scheduleAuditRetention (re)programme la purge quotidienne des événements trop anciens des channels d'audit.
+func (b *Bot) scheduleAuditRetention(svc *services) {
+	b.scheduler.Cancel(auditJob)
+	if svc.cfg.Audit.Retention <= 0 {
+		return
+	}
+	if err := b.scheduler.Daily(auditJob, "04:00", svc.cfg.Location(), b.purgeAudit); err != nil {
+		b.logger.Error("Purge du journal d'audit non planifiée", slog.String("error", err.Error()))
+	}
+}
+
+// purgeAudit efface des channels d'audit les événements publiés avant audit.retention.
+func (b *Bot) purgeAudit(ctx context.Context) {
+	cfg := b.services.Load().cfg
+	cutoff := time.Now().Add(-cfg.Audit.Retention)
+	channels := map[string]bool{}
+	for _, g := range cfg.Guilds {
+		for _, id := range []string{g.Audit.Channel, g.Moderation.LogChannel} {
+			if id != "" {
+				channels[id] = true
+			}
+		}
+	}
+	for channelID := range channels {
+		deleted, err := b.purgeAuditChannel(ctx, channelID, cutoff)
+		if err != nil {
+			b.logger.Warn("Purge du journal d'audit interrompue",
+				slog.String("channel", channelID),
+				slog.Int("deleted", deleted),
+				slog.String("error", err.Error()),
+			)
+			continue
+		}
+		if deleted > 0 {
+			b.logger.Info("Journal d'audit purgé", slog.String("channel", channelID), slog.Int("deleted", deleted))
+		}
+	}
+}
+
+// purgeAuditChannel efface d'un channel les événements du journal d'audit antérieurs à cutoff.
+// Les messages sont parcourus du plus récent au plus ancien à partir de cutoff (les plus récents
+// ne sont pas relus) ; ceux de moins de 14 jours sont supprimés en lot, les autres un par un.
+func (b *Bot) purgeAuditChannel(ctx context.Context, channelID string, cutoff time.Time) (int, error) {
+	deleted, before := 0, snowflakeAt(cutoff)
+	for {
+		msgs, err := b.session.ChannelMessages(channelID, 100, before, "", "", discordgo.WithContext(ctx))
+		if err != nil {
+			return deleted, err
+		}
+		if len(msgs) == 0 {
+			return deleted, nil
+		}
+
+		var bulk []string
+		bulkLimit := time.Now().Add(-bulkDeleteMaxAge)
+		for _, msg := range msgs {
+			if !b.isAuditEvent(msg) {
+				continue
+			}
+			if msg.Timestamp.After(bulkLimit) {
+				bulk = append(bulk, msg.ID)
+				continue
+			}
+			if err := b.session.ChannelMessageDelete(channelID, msg.ID, discordgo.WithContext(ctx)); err != nil {
+				return deleted, err
+			}
+			deleted++
+		}
+		if err := b.session.ChannelMessagesBulkDelete(channelID, bulk, discordgo.WithContext(ctx)); err != nil {
+			return deleted, err
+		}
+		deleted += len(bulk)
+		before = msgs[len(msgs)-1].ID
+	}
+}
+
+// snowflakeAt retourne le plus petit identifiant Discord horodaté à t, pour paginer par date.
+func snowflakeAt(t time.Time) string {
+	return strconv.FormatInt((t.UnixMilli()-discordEpoch)<<22, 10)
+}
+
+// isAuditEvent indique si un message est un événement du journal d'audit publié par le bot.
+func (b *Bot) isAuditEvent(msg *discordgo.Message) bool {
+	if msg.Author == nil || msg.Author.ID != b.session.State.User.ID || len(msg.Embeds) == 0 {
+		return false
+	}
+	footer := msg.Embeds[0].Footer
+	return footer != nil && footer.Text == auditFooter
+}
+
+// auditQuote présente un contenu cité dans un événement du journal d'audit.
+func auditQuote(content string) string {
+	if strings.TrimSpace(content) == "" {
+		return "*(aucun texte)*"
+	}
+	return "```\n" + truncate(strings.ReplaceAll(content, "```", "'''"), 1000) + "\n```"
+}
+
+// messageLink retourne le lien vers un message Discord.
+func messageLink(guildID, channelID, messageID string) string {
+	return fmt.Sprintf("https://discord.com/channels/%s/%s/%s", guildID, channelID, messageID)
+}
@@ -5,74 +5,361 @@ import (
 	"errors"
 	"fmt"
 	"log/slog"
+	"net/http"
 	"otom-ai/ai"
+	"otom-ai/almanax"
+	"otom-ai/calc"
+	"otom-ai/cassette"
 	"otom-ai/config"
+	"otom-ai/conversations"
+	"otom-ai/fetch"
+	"otom-ai/frtime"
+	"otom-ai/guildkb"
+	"otom-ai/items"
+	"otom-ai/lfg"
+	"otom-ai/logging"
+	"otom-ai/moderation"
+	"otom-ai/prices"
+	"otom-ai/privacy"
+	"otom-ai/profiles"
+	"otom-ai/rag"
+	"otom-ai/reminders"
+	"otom-ai/scheduler"
 	"otom-ai/search"
+	"otom-ai/untrusted"
+	"slices"
 	"strings"
+	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/bwmarrin/discordgo"
 )
 
-// systemPrompt définit le persona du bot
-const systemPrompt = `Tu es un bot Discord et un vétéran très chill du MMORPG Dofus 3 Unity. Tu agis comme un vrai pote de guilde avec qui on discute tranquillement au Zaap d'Astrub.
-Ton ton est décalé, amical, drôle et parfois un peu sarcastique, mais toujours bienveillant pour aider les joueurs.
-
-Règles de comportement :
-- Utilise le tutoiement systématiquement avec tous les utilisateurs.
-- Sois concis : tes réponses doivent être percutantes et adaptées à un chat Discord.
-- Si tu ne connais pas la réponse à une question, avoue-le avec humour (ex: "Mec, j'ai tellement farmé que j'ai le cerveau en compote, aucune idée").
-- Agis parfois comme si tu étais en train de jouer en même temps (ex: "Attends je finis mon tour...").
-
-Vocabulaire Dofus obligatoire (à utiliser naturellement) :
-- Kamas, HDV (Hôtel de Vente), farm, stuff, tryhard, PL, monocompte, faire les succès.
-- N'hésite pas à faire quelques vannes sur la "méta" du jeu, comme les joueurs de Crâ qui farment de loin, ou les Pandawas qui portent tout le monde.`
-
 // Bot orchestre toutes les dépendances du bot Discord.
 type Bot struct {
-	session      *discordgo.Session
+	session       *discordgo.Session
+	services      atomic.Pointer[services] // Remplacé à chaud au rechargement de la configuration
+	rateLimiter   *RateLimiter
+	dmLimiter     *RateLimiter // Rate limit des messages privés
+	dmQuota       *RateLimiter // Quota de messages privés sur 24h glissantes
+	botLoops      *RateLimiter // Échanges avec les autres bots, par channel (anti ping-pong)
+	abuseReports  *RateLimiter // Signalements de rate limit au journal d'audit, par serveur et utilisateur
+	logger        *slog.Logger
+	rootLogger    *slog.Logger    // Logger sans sous-système, dérivé pour les clients ai/search
+	logLevels     *logging.Levels // Niveaux de log modifiables à chaud (commande admin)
+	scheduler     *scheduler.Scheduler
+	reindexing    atomic.Bool // Une seule indexation de la base de connaissances à la fois
+	lurk          lurkState   // Dernières interventions spontanées par channel
+	prices        *prices.Store
+	lfg           *lfg.Store
+	profiles      *profiles.Store
+	reminders     *reminders.Store
+	guildKB       *guildkb.Store
+	conversations *conversations.Store // Mémoire des conversations en message privé
+	feedback      *conversations.FeedbackStore
+	privacy       *privacy.Store // Utilisateurs ayant refusé la lecture de leurs messages
+	members       memberCache    // Utilisateurs partageant un serveur avec le bot
+	replies       replyCache     // Contexte des dernières réponses, pour les boutons
+	messages      messageCache   // Derniers messages des serveurs journalisés (audit)
+	cassettes     cassetteState  // Transport d'enregistrement ou de rejeu, conservé au rechargement
+}
+
+// services regroupe les dépendances construites à partir de la configuration.
+// Elles sont reconstruites à chaque rechargement, sans couper la connexion Discord.
+type services struct {
+	cfg          *config.Config
 	aiClient     *ai.Client
 	searchClient *search.Client
-	rateLimiter  *RateLimiter
-	logger       *slog.Logger
+	fetchClient  *fetch.Client // nil si aucun domaine n'est autorisé
+	items        *items.Database
+	almanax      *almanax.Calendar
+	xp           *calc.XPTable
+	knowledge    *rag.KnowledgeBase            // nil si la base de connaissances n'est pas configurée
+	filters      map[string]*moderation.Filter // Listes de modération par serveur ("" : listes globales)
+	classifier   moderation.Classifier         // nil si aucun classifieur n'est configuré
+	embedder     *rag.Embedder
 }
 
 // Nouvelle instance du bot avec toutes ses dépendances
-func New(cfg *config.Config, logger *slog.Logger) (*Bot, error) {
+func New(cfg *config.Config, logger *slog.Logger, levels *logging.Levels) (*Bot, error) {
 	// Création de la session Discord
-	session, err := discordgo.New("Bot " + cfg.DiscordToken)
+	session, err := discordgo.New("Bot " + cfg.Discord.Token.Value())
 	if err != nil {
 		return nil, fmt.Errorf("impossible de créer la session Discord: %w", err)
 	}
 
 	// Configuration des intents (équivalent de discord.Intents.default() + message_content)
+	// Les messages privés sont toujours reçus : leur traitement dépend de dm.enabled (rechargeable à chaud)
 	session.Identify.Intents = discordgo.IntentsGuilds |
 		discordgo.IntentsGuildMessages |
+		discordgo.IntentsDirectMessages |
+		discordgo.IntentsGuildMessageReactions |
+		discordgo.IntentsDirectMessageReactions |
 		discordgo.IntentsMessageContent
 
+	priceStore, err := prices.Open(cfg.DataFile("prices.json"))
+	if err != nil {
+		return nil, fmt.Errorf("base des prix HDV: %w", err)
+	}
+	lfgStore, err := lfg.Open(cfg.DataFile("lfg.json"))
+	if err != nil {
+		return nil, fmt.Errorf("base des sorties de groupe: %w", err)
+	}
+	profileStore, err := profiles.Open(cfg.DataFile("profiles.json"))
+	if err != nil {
+		return nil, fmt.Errorf("base des profils de joueurs: %w", err)
+	}
+	reminderStore, err := reminders.Open(cfg.DataFile("reminders.json"))
+	if err != nil {
+		return nil, fmt.Errorf("base des rappels: %w", err)
+	}
+	guildKBStore, err := guildkb.Open(cfg.DataFile("guildkb.json"))
+	if err != nil {
+		return nil, fmt.Errorf("fiches des serveurs: %w", err)
+	}
+	conversationStore, err := conversations.Open(cfg.DataFile("conversations.json"))
+	if err != nil {
+		return nil, fmt.Errorf("mémoire des conversations: %w", err)
+	}
+	feedbackStore, err := conversations.OpenFeedback(cfg.DataFile("feedback.json"))
+	if err != nil {
+		return nil, fmt.Errorf("avis sur les réponses: %w", err)
+	}
+
+	privacyStore, err := privacy.Open(cfg.DataFile("privacy.json"))
+	if err != nil {
+		return nil, fmt.Errorf("préférences de confidentialité: %w", err)
+	}
+
 	b := &Bot{
-		session:      session,
-		aiClient:     ai.NewClient(cfg.DeepSeekKey, cfg.DeepSeekURL, cfg.DeepSeekModel),
-		searchClient: search.NewClient(cfg.TavilyKey),
-		rateLimiter:  NewRateLimiter(5, 60*time.Second), // 5 requêtes/minute/utilisateur
-		logger:       logger,
+		session:       session,
+		rateLimiter:   NewRateLimiter(cfg.RateLimit.Requests, cfg.RateLimit.Window),
+		dmLimiter:     NewRateLimiter(cfg.DM.RateLimit.Requests, cfg.DM.RateLimit.Window),
+		dmQuota:       NewRateLimiter(cfg.DM.DailyQuota, 24*time.Hour),
+		botLoops:      NewRateLimiter(cfg.Bots.Loop.Requests, cfg.Bots.Loop.Window),
+		abuseReports:  NewRateLimiter(1, cfg.Audit.AbuseCooldown),
+		logger:        logging.For(logger, "bot"),
+		rootLogger:    logger,
+		logLevels:     levels,
+		scheduler:     scheduler.New(logging.For(logger, "scheduler")),
+		prices:        priceStore,
+		lfg:           lfgStore,
+		profiles:      profileStore,
+		reminders:     reminderStore,
+		guildKB:       guildKBStore,
+		conversations: conversationStore,
+		feedback:      feedbackStore,
+		privacy:       privacyStore,
+	}
+
+	svc, err := b.newServices(cfg)
+	if err != nil {
+		return nil, err
+	}
+	b.services.Store(svc)
+	b.scheduleAlmanax(svc)
+	b.scheduleAuditRetention(svc)
+	b.scheduleDMPurge(svc)
+	b.scheduleRunsPurge(svc)
+	b.purgeRuns(context.Background())
+	b.scheduleRuns(cfg.LFG.Reminder)
+	b.purgeDMs(context.Background())
+	if err := feedbackStore.Prune(time.Now().Add(-cfg.Feedback.Retention)); err != nil {
+		b.logger.Warn("Purge des avis impossible", slog.String("error", err.Error()))
 	}
 
 	// Enregistrement des handlers d'événements Discord
 	session.AddHandler(b.onReady)
 	session.AddHandler(b.onMessageCreate)
+	session.AddHandler(b.onMessageUpdate)
 	session.AddHandler(b.onMessageDelete)
+	session.AddHandler(b.onReactionAdd)
+	session.AddHandler(b.onReactionRemove)
+	session.AddHandler(b.onInteractionCreate)
 
 	return b, nil
 }
 
+// Reload applique une nouvelle configuration sans couper la connexion Discord.
+// Le token Discord et le dossier de données nécessitent un redémarrage pour être pris en compte.
+func (b *Bot) Reload(cfg *config.Config) error {
+	svc, err := b.newServices(cfg)
+	if err != nil {
+		return err
+	}
+
+	old := b.services.Swap(svc)
+	b.rateLimiter.SetLimits(cfg.RateLimit.Requests, cfg.RateLimit.Window)
+	b.dmLimiter.SetLimits(cfg.DM.RateLimit.Requests, cfg.DM.RateLimit.Window)
+	b.dmQuota.SetLimits(cfg.DM.DailyQuota, 24*time.Hour)
+	b.botLoops.SetLimits(cfg.Bots.Loop.Requests, cfg.Bots.Loop.Window)
+	b.abuseReports.SetLimits(1, cfg.Audit.AbuseCooldown)
+	b.scheduleAlmanax(svc)
+	b.scheduleAuditRetention(svc)
+	b.scheduleDMPurge(svc)
+	b.scheduleRunsPurge(svc)
+	if old.cfg.LFG.Reminder != cfg.LFG.Reminder {
+		b.scheduleRuns(cfg.LFG.Reminder)
+	}
+	b.auditReload(old.cfg, cfg)
+
+	if old.cfg.Discord.Token != cfg.Discord.Token {
+		b.logger.Warn("Le token Discord a changé : redémarrage nécessaire pour l'appliquer")
+	}
+	if old.cfg.DataDir != cfg.DataDir {
+		b.logger.Warn("Le dossier de données a changé : redémarrage nécessaire pour l'appliquer")
+	}
+	if old.cfg.Discord.Status != cfg.Discord.Status {
+		_ = b.session.UpdateGameStatus(0, cfg.Discord.Status)
+	}
+	return nil
+}
+
+// newServices construit les clients IA et recherche à partir de la configuration.
+func (b *Bot) newServices(cfg *config.Config) (*services, error) {
+	provider := cfg.Provider()
+	aiClient := ai.NewClient(provider.APIKey.Value(), provider.URL, provider.Model)
+	aiClient.SetTemperature(*provider.Temperature)
+	aiClient.SetTimeout(provider.Timeout)
+	aiClient.SetLogger(logging.For(b.rootLogger, "ai"))
+
+	searchClient := search.NewClient(cfg.Search.TavilyKey.Value())
+	searchClient.SetTimeout(cfg.Search.Timeout)
+	searchClient.SetMaxResults(cfg.Search.MaxResults)
+	searchClient.SetLogger(logging.For(b.rootLogger, "search"))
+
+	itemsDB, err := items.Load(cfg.Items.File)
+	if err != nil {
+		return nil, err
+	}
+	if !itemsDB.HasStats() {
+		b.logger.Warn("Base d'objets sans caractéristiques (items.file) : outil lookup_item désactivé, voir items import")
+	}
+
+	calendar, err := almanax.Load(cfg.Almanax.File)
+	if err != nil {
+		return nil, err
+	}
+	if calendar.Len() == 0 {
+		b.logger.Warn("Almanax vide (almanax.file) : annonces et outil get_almanax désactivés")
+	}
+
+	xpTable, err := calc.LoadXPTable(cfg.XP.File)
+	if err != nil {
+		return nil, err
+	}
+	if xpTable.MaxLevel() == 0 {
+		b.logger.Warn("Table d'expérience absente (xp.file) : calculateur d'XP désactivé")
+	}
+
+	svc := &services{cfg: cfg, aiClient: aiClient, searchClient: searchClient, items: itemsDB, almanax: calendar, xp: xpTable}
+
+	if cfg.Fetch.Enabled() {
+		svc.fetchClient = newFetchClient(cfg.Fetch)
+		svc.fetchClient.SetLogger(logging.For(b.rootLogger, "fetch"))
+	}
+
+	if cfg.Knowledge.Enabled() {
+		index, err := rag.OpenIndex(cfg.Knowledge.IndexFile)
+		if err != nil {
+			return nil, fmt.Errorf("base de connaissances: %w", err)
+		}
+		emb := cfg.Knowledge.Embeddings
+		embedder := rag.NewEmbedder(emb.APIKey.Value(), emb.URL, emb.Model)
+		embedder.SetTimeout(emb.Timeout)
+		embedder.SetLogger(logging.For(b.rootLogger, "rag"))
+		svc.knowledge = rag.New(index, embedder, cfg.Knowledge.TopK)
+		svc.embedder = embedder
+	}
+
+	if err := setupModeration(svc, logging.For(b.rootLogger, "moderation")); err != nil {
+		return nil, err
+	}
+
+	// Enregistrement/rejeu des échanges HTTP (debug des hallucinations)
+	if err := b.setupCassettes(svc); err != nil {
+		return nil, err
+	}
+	return svc, nil
+}
+
+// cassetteState garde le transport de cassettes d'un rechargement à l'autre : recréé, un
+// Replayer resservirait des échanges déjà rejoués et un Recorder perdrait ses cassettes ouvertes.
+type cassetteState struct {
+	mu      sync.Mutex
+	mode    string
+	dir     string
+	secrets []string
+	rt      http.RoundTripper
+}
+
+// transport retourne le transport de cassettes de la configuration, réutilisé tant que le mode,
+// le dossier et les secrets à masquer sont inchangés (nil si les cassettes sont désactivées).
+func (c *cassetteState) transport(cfg *config.Config, logger *slog.Logger) (http.RoundTripper, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	secrets := cfg.Secrets()
+	if c.rt != nil && c.mode == cfg.Cassette.Mode && c.dir == cfg.Cassette.Dir && slices.Equal(c.secrets, secrets) {
+		return c.rt, nil
+	}
+
+	redactor := cassette.NewRedactor(secrets...)
+	var rt http.RoundTripper
+	switch cfg.Cassette.Mode {
+	case cassette.ModeRecord:
+		rt = cassette.NewRecorder(cfg.Cassette.Dir, nil, redactor, logger)
+	case cassette.ModeReplay:
+		replayer, err := cassette.NewReplayer(cfg.Cassette.Dir, redactor)
+		if err != nil {
+			return nil, fmt.Errorf("impossible de charger les cassettes: %w", err)
+		}
+		rt = replayer
+	}
+	c.mode, c.dir, c.secrets, c.rt = cfg.Cassette.Mode, cfg.Cassette.Dir, secrets, rt
+	return rt, nil
+}
+
+// setupCassettes branche le transport d'enregistrement ou de rejeu sur les clients HTTP.
+func (b *Bot) setupCassettes(svc *services) error {
+	cfg := svc.cfg
+	rt, err := b.cassettes.transport(cfg, b.logger)
+	if err != nil || rt == nil {
+		return err
+	}
+
+	svc.aiClient.SetTransport(rt)
+	svc.searchClient.SetTransport(rt)
+	if svc.fetchClient != nil {
+		svc.fetchClient.SetTransport(rt)
+	}
+	if svc.embedder != nil {
+		svc.embedder.SetTransport(rt)
+	}
+	if endpoint, ok := svc.classifier.(*moderation.Endpoint); ok {
+		endpoint.SetTransport(rt)
+	}
+	b.logger.Warn("Cassettes HTTP actives",
+		slog.String("mode", cfg.Cassette.Mode),
+		slog.String("path", cfg.Cassette.Dir),
+	)
+	return nil
+}
+
 // Start ouvre la connexion WebSocket avec Discord.
 func (b *Bot) Start() error {
-	return b.session.Open()
+	if err := b.session.Open(); err != nil {
+		return err
+	}
+	// Après la connexion : les rappels échus pendant l'arrêt partent immédiatement
+	b.restoreReminders()
+	return nil
 }
 
-// Stop ferme proprement la connexion Discord.
+// Stop arrête les tâches planifiées et ferme proprement la connexion Discord.
 func (b *Bot) Stop() error {
+	b.scheduler.Stop()
 	return b.session.Close()
 }
 
@@ -85,20 +372,10 @@ func (b *Bot) onReady(s *discordgo.Session, _ *discordgo.Ready) {
 	)
 
 	// Définition du statut "En train de jouer à..."
-	_ = s.UpdateGameStatus(0, "Répondre aux noob du Zaap")
-}
+	_ = s.UpdateGameStatus(0, b.services.Load().cfg.Discord.Status)
 
-// onMessageDelete log les messages supprimés (sécurité/audit).
-func (b *Bot) onMessageDelete(s *discordgo.Session, m *discordgo.MessageDelete) {
-	// On ne peut pas vérifier .Author sur un événement de suppression
-	// si le message n'est pas en cache, on log juste l'ID.
-	if m.BeforeDelete != nil && !m.BeforeDelete.Author.Bot {
-		b.logger.Info("Message supprimé",
-			slog.String("author", m.BeforeDelete.Author.Username),
-			slog.String("content", m.BeforeDelete.Content),
-			slog.String("channel", m.ChannelID),
-		)
-	}
+	// Déclaration des commandes slash
+	b.registerCommands(s)
 }
 
 // onMessageCreate est le handler principal : filtre, rate limit, puis appel IA.
@@ -108,35 +385,91 @@ func (b *Bot) onMessageCreate(s *discordgo.Session, m *discordgo.MessageCreate)
 		return
 	}
 
-	// 2. Le bot ne répond que s'il est mentionné (@bot)
-	if !b.isMentioned(s, m.Message) {
+	svc := b.services.Load()
+
+	// Autres bots et webhooks : ignorés sauf autorisation explicite (bots.allowed, bots.webhooks)
+	isAutomated := automated(m.Message)
+	// Journal d'audit : contenu gardé en mémoire pour décrire une suppression ou une modification
+	if m.GuildID != "" && !isAutomated {
+		b.trackMessage(s, svc, m.Message)
+	}
+	if isAutomated && !acceptsAutomated(m.Message, svc.cfg.Bots) {
+		return
+	}
+
+	// 2. Règles de channels : autorisations, mention requise ou non, interventions spontanées
+	// (un message privé s'adresse toujours au bot)
+	why := direct
+	if m.GuildID != "" {
+		why = b.engagement(s, m.Message, svc)
+	}
+	if why == ignored || (why == lurking && b.optedOut(m.Author.ID)) {
+		return
+	}
+	// Un bot doit s'adresser à Otom-AI pour obtenir une réponse, et jamais plus de quelques fois
+	// d'affilée : ces garde-fous valent aussi en message privé
+	if isAutomated && why != mentioned && why != threadStart && why != direct {
+		return
+	}
+	if isAutomated && !b.loopGuard(m.Message) {
+		return
+	}
+
+	// Messages privés : politique distincte (activation, quotas, mémoire par utilisateur)
+	if why == direct {
+		b.onDirectMessage(s, m, svc)
 		return
 	}
 
-	// 4. Sécurité : Rate limiting utilisateur
+	// 3. Sécurité : Rate limiting utilisateur
 	allowed, retryAfter := b.rateLimiter.Allow(m.Author.ID)
 	if !allowed {
-		b.replyToMessage(s, m.Message, fmt.Sprintf(
-			"⏳ Hop là, tu t'es pris pour Flasho ?! Attends encore %.1f secondes et là j'accepterai de t'écouter.",
-			retryAfter.Seconds(),
-		))
+		if why != lurking { // Personne n'attend de réponse à une intervention spontanée
+			b.auditRateLimit(s, svc, m.GuildID, m.ChannelID, m.Author)
+			b.replyToMessage(s, m.Message, fmt.Sprintf(
+				"⏳ Hop là, tu t'es pris pour Flasho ?! Attends encore %.1f secondes et là j'accepterai de t'écouter.",
+				retryAfter.Seconds(),
+			))
+		}
 		return
 	}
 
-	// 5. Traitement IA (DeepSeek + tool calling Tavily)
-	b.handleAIResponse(s, m)
+	// 4. Traitement IA (DeepSeek + tool calling Tavily)
+	b.handleAIResponse(s, m, svc, why)
 }
 
 // ---------- Logique IA ----------
 
 // handleAIResponse orchestre l'appel au LLM avec indicateur de frappe ("typing").
-func (b *Bot) handleAIResponse(s *discordgo.Session, m *discordgo.MessageCreate) {
-	// Indicateur "Bot est en train d'écrire..." (typing indicator)
-	_ = s.ChannelTyping(m.ChannelID)
-
+func (b *Bot) handleAIResponse(s *discordgo.Session, m *discordgo.MessageCreate, svc *services, why engagement) {
 	// Nettoyage du contenu (suppression de la mention du bot)
 	cleanContent := b.stripBotMention(s, m.Content)
 
+	// Modération du message reçu (refus, masquage ou simple signalement selon le serveur)
+	cleanContent, ok := b.moderateInput(s, m.Message, svc, cleanContent, why)
+	if !ok {
+		return
+	}
+
+	// Conversation déportée dans un nouveau fil : il accueille la réponse et la suite des échanges
+	channelID := m.ChannelID
+	if why == threadStart {
+		thread, err := b.startThread(s, m.Message, cleanContent, svc)
+		if err != nil {
+			b.logger.Warn("Impossible d'ouvrir un fil, réponse dans le channel",
+				slog.String("channel", m.ChannelID),
+				slog.String("error", err.Error()),
+			)
+		} else {
+			channelID = thread.ID
+		}
+	}
+
+	// Indicateur "Bot est en train d'écrire..." (typing indicator), sauf pour une intervention spontanée qui peut être abandonnée
+	if why != lurking {
+		_ = s.ChannelTyping(channelID)
+	}
+
 	// Log du user et des premiers mots de son message
 	b.logger.Info("Message reçu",
 		slog.String("user", m.Author.Username),
@@ -145,75 +478,144 @@ func (b *Bot) handleAIResponse(s *discordgo.Session, m *discordgo.MessageCreate)
 		slog.String("channel", m.ChannelID),
 	)
 
-	// Récupération de l'historique récent du channel pour enrichir le contexte
-	history := b.fetchChannelHistory(s, m.ChannelID, m.ID, 20)
+	// Récupération de l'historique récent du channel pour enrichir le contexte (aucun pour un fil qui vient d'être ouvert).
+	// En message privé, la mémoire de l'utilisateur remplace l'historique du channel.
+	var history []ai.Message
+	var turns []conversations.Turn // Même historique, conservé avec les avis
+	switch {
+	case why == direct:
+		history, turns = b.dmHistory(svc, m.Author)
+	case channelID == m.ChannelID:
+		history, turns = b.fetchChannelHistory(s, m.ChannelID, m.ID, svc.cfg.History.Depth)
+	}
 
 	// Construction du contexte conversationnel
-	messages := make([]ai.Message, 0, 2+len(history))
-	messages = append(messages, ai.Message{Role: "system", Content: systemPrompt})
+	messages := make([]ai.Message, 0, 6+len(history))
+	messages = append(messages, ai.Message{Role: "system", Content: svc.cfg.SystemPrompt()})
+	messages = append(messages, ai.Message{Role: "system", Content: untrusted.Notice})
+	messages = append(messages, ai.Message{Role: "system", Content: dateContext(svc.cfg.Location())})
+	if sheet := b.guildKBContext(m.GuildID, cleanContent); sheet != "" {
+		messages = append(messages, ai.Message{Role: "system", Content: sheet})
+	}
+	if why == lurking {
+		messages = append(messages, ai.Message{Role: "system", Content: lurkPrompt})
+	}
+	if automated(m.Message) {
+		messages = append(messages, ai.Message{Role: "system", Content: botPrompt})
+	}
+	if profile := b.profileContext(m.GuildID, m.Author.ID); profile != "" {
+		messages = append(messages, ai.Message{Role: "user", Content: profile})
+	}
 	messages = append(messages, history...)
-	messages = append(messages, ai.Message{Role: "user", Content: fmt.Sprintf("[%s] %s", m.Author.Username, cleanContent)})
-
-	// Définition des outils disponibles
-	tools := []ai.ToolDef{ai.SearchToolDef()}
+	messages = append(messages, ai.Message{Role: "user", Content: authorLabel(m.Message) + " " + untrusted.Strip(cleanContent)})
 
 	// Appel au LLM avec support du tool calling
-	ctx, cancel := context.WithTimeout(context.Background(), 90*time.Second)
+	ctx, cancel := context.WithTimeout(context.Background(), svc.cfg.AI.Timeout)
 	defer cancel()
+	ctx = cassette.WithName(ctx, m.ChannelID+"-"+m.ID) // Une cassette par message traité (question et appels d'outils)
 
-	result, err := b.aiClient.Complete(ctx, messages, tools, b.searchClient.Search)
+	tools := b.tools(svc, m.Message)
+	if why == lurking {
+		tools = withoutActions(tools)
+	}
+	result, err := svc.aiClient.Complete(ctx, messages, tools)
 	if err != nil {
 		b.handleAIError(s, m, err)
 		return
 	}
 
-	// Log de l'utilisation de la recherche web
-	if result.WebSearchUsed {
-		if result.WebSearchError != nil {
-			b.logger.Error("Recherche web échouée",
-				slog.String("user", m.Author.Username),
-				slog.String("query", result.WebSearchQuery),
-				slog.String("error", result.WebSearchError.Error()),
-			)
-		} else {
-			b.logger.Info("Recherche web utilisée",
-				slog.String("user", m.Author.Username),
-				slog.String("query", result.WebSearchQuery),
-			)
-		}
+	b.logToolUses(m.Author.Username, result)
+
+	// Intervention spontanée jugée inutile par le LLM
+	if why == lurking && strings.Contains(result.Reply, lurkPass) {
+		b.logger.Debug("Intervention spontanée abandonnée", slog.String("channel", m.ChannelID))
+		return
 	}
 
-	// Envoi de la réponse en reply (tronquée à 2000 caractères, limite Discord)
-	b.replyToMessage(s, m.Message, truncate(result.Reply, 2000))
+	// Envoi de la réponse en reply (tronquée à 2000 caractères, limite Discord), ou dans le fil ouvert
+	// Les boutons (régénérer, plus court...) accompagnent toute réponse sollicitée.
+	reply, refused := b.moderateOutput(s, m.Message, svc, result.Reply)
+	if refused && why == lurking {
+		return
+	}
+	reply = truncate(reply, 2000)
+	send := &discordgo.MessageSend{Content: reply}
+	if channelID == m.ChannelID {
+		send.Reference = m.Reference()
+	}
+	if why != lurking {
+		send.Components = replyButtons()
+	}
+	sent := b.sendMessage(s, channelID, send)
+	if sent == nil {
+		return
+	}
+	if why != lurking {
+		b.replies.put(sent.ID, replyContext{
+			request:  m.Message,
+			question: cleanContent,
+			turns:    turns,
+			messages: messages,
+			reply:    reply,
+		}, time.Now())
+	}
+	if why == direct {
+		b.rememberDM(svc, m.Author.ID, cleanContent, reply)
+	}
+	if svc.cfg.Feedback.Enabled {
+		b.recordAnswer(s, sent, m.Message, svc, cleanContent, turns, result)
+	}
 }
 
 // handleAIError gère les erreurs de l'API IA avec des messages thématiques Dofus.
 func (b *Bot) handleAIError(s *discordgo.Session, m *discordgo.MessageCreate, err error) {
+	b.replyToMessage(s, m.Message, b.aiErrorMessage(err))
+}
+
+// aiErrorMessage log une erreur de l'API IA et retourne le message à présenter à l'utilisateur.
+func (b *Bot) aiErrorMessage(err error) string {
 	var apiErr *ai.APIError
 	if errors.As(err, &apiErr) {
 		b.logger.Error("Erreur API IA", slog.Int("status", apiErr.StatusCode), slog.String("body", apiErr.Body))
-		b.replyToMessage(s, m.Message, apiErr.UserMessage())
-		return
+		return apiErr.UserMessage()
 	}
 
 	b.logger.Error("Erreur API IA", slog.String("error", err.Error()))
-	b.replyToMessage(s, m.Message,
-		"Oups, on dirait que le Dieu Xélor fait encore des siennes, mes signaux sont perturbés ! Ré-essaye dans quelques instants.",
-	)
+	return "Oups, on dirait que le Dieu Xélor fait encore des siennes, mes signaux sont perturbés ! Ré-essaye dans quelques instants."
+}
+
+// logToolUses log l'utilisation des outils (recherche web, base d'objets...) lors d'une complétion.
+func (b *Bot) logToolUses(username string, result *ai.CompletionResult) {
+	for _, use := range result.ToolUses {
+		if use.Error != nil {
+			b.logger.Error("Outil en échec",
+				slog.String("user", username),
+				slog.String("tool", use.Name),
+				slog.String("arguments", use.Arguments),
+				slog.String("error", use.Error.Error()),
+			)
+			continue
+		}
+		b.logger.Info("Outil utilisé",
+			slog.String("user", username),
+			slog.String("tool", use.Name),
+			slog.String("arguments", use.Arguments),
+		)
+	}
 }
 
 // ---------- Utilitaires ----------
 
 // fetchChannelHistory récupère les N derniers messages du channel (avant le message courant)
 // et les convertit en messages AI pour enrichir le contexte conversationnel.
-func (b *Bot) fetchChannelHistory(s *discordgo.Session, channelID, beforeID string, limit int) []ai.Message {
+func (b *Bot) fetchChannelHistory(s *discordgo.Session, channelID, beforeID string, limit int) ([]ai.Message, []conversations.Turn) {
 	msgs, err := s.ChannelMessages(channelID, limit, beforeID, "", "")
 	if err != nil {
 		b.logger.Warn("Impossible de récupérer l'historique du channel",
 			slog.String("channel", channelID),
 			slog.String("error", err.Error()),
 		)
-		return nil
+		return nil, nil
 	}
 
 	// Discord renvoie les messages du plus récent au plus ancien, on les inverse
@@ -223,29 +625,55 @@ func (b *Bot) fetchChannelHistory(s *discordgo.Session, channelID, beforeID stri
 
 	botID := s.State.User.ID
 	history := make([]ai.Message, 0, len(msgs))
+	turns := make([]conversations.Turn, 0, len(msgs))
 
 	for _, msg := range msgs {
+		// Dans un fil ouvert sur un message, la question d'origine est celle du message de départ
+		if msg.Type == discordgo.MessageTypeThreadStarterMessage && msg.ReferencedMessage != nil {
+			msg = msg.ReferencedMessage
+		}
 		if msg.Author == nil || msg.Content == "" {
 			continue
 		}
+		// Auteur ayant refusé la lecture de ses messages (/privacy optout)
+		if msg.Author.ID != botID && b.optedOut(msg.Author.ID) {
+			continue
+		}
 
 		if msg.Author.ID == botID {
 			// Message du bot → rôle "assistant"
 			history = append(history, ai.Message{Role: "assistant", Content: msg.Content})
+			turns = append(turns, conversations.Turn{Content: msg.Content, Bot: true})
 		} else {
-			// Message d'un utilisateur → rôle "user" avec préfixe du pseudo
+			// Message d'un utilisateur (ou d'un autre bot) → rôle "user" avec préfixe du pseudo,
+			// contenu placé dans un bloc de données non fiables (il ne doit pas dicter la conduite du bot)
 			cleaned := b.stripBotMention(s, msg.Content)
 			if cleaned == "" {
 				continue
 			}
-			history = append(history, ai.Message{
-				Role:    "user",
-				Content: fmt.Sprintf("[%s] %s", msg.Author.Username, cleaned),
+			if found := untrusted.Scan(cleaned); len(found) > 0 {
+				b.logger.Info("Consignes suspectes dans l'historique du channel",
+					slog.String("author", msg.Author.Username),
+					slog.String("channel", channelID),
+					slog.String("matches", strings.Join(found, " | ")),
+				)
+			}
+			label, wrapped := authorLabel(msg), untrusted.Wrap("message", cleaned)
+			history = append(history, ai.Message{Role: "user", Content: label + " " + wrapped})
+			turns = append(turns, conversations.Turn{
+				Author:   strings.Trim(label, "[]"),
+				AuthorID: msg.Author.ID,
+				Content:  untrusted.Unwrap(wrapped),
 			})
 		}
 	}
 
-	return history
+	return history, turns
+}
+
+// dateContext indique au LLM la date et l'heure courantes (utile pour "vendredi", "demain"...).
+func dateContext(loc *time.Location) string {
+	return fmt.Sprintf("Nous sommes le %s (fuseau %s).", frtime.DateTime(time.Now().In(loc)), loc)
 }
 
 // isMentioned vérifie si le bot est mentionné dans le message.
@@ -268,16 +696,23 @@ func (b *Bot) stripBotMention(s *discordgo.Session, content string) string {
 }
 
 // replyToMessage répond directement au message d'un utilisateur (reply Discord).
-func (b *Bot) replyToMessage(s *discordgo.Session, m *discordgo.Message, content string) {
-	if _, err := s.ChannelMessageSendComplex(m.ChannelID, &discordgo.MessageSend{
-		Content:   content,
-		Reference: m.Reference(),
-	}); err != nil {
+// Il retourne le message envoyé, nil en cas d'échec (déjà journalisé).
+func (b *Bot) replyToMessage(s *discordgo.Session, m *discordgo.Message, content string) *discordgo.Message {
+	return b.sendMessage(s, m.ChannelID, &discordgo.MessageSend{Content: content, Reference: m.Reference()})
+}
+
+// sendMessage envoie un message dans un channel et journalise l'échec éventuel.
+// Il retourne le message envoyé, nil en cas d'échec.
+func (b *Bot) sendMessage(s *discordgo.Session, channelID string, send *discordgo.MessageSend) *discordgo.Message {
+	sent, err := s.ChannelMessageSendComplex(channelID, send)
+	if err != nil {
 		b.logger.Error("Impossible d'envoyer un message",
-			slog.String("channel", m.ChannelID),
+			slog.String("channel", channelID),
 			slog.String("error", err.Error()),
 		)
+		return nil
 	}
+	return sent
 }
 
 // truncate tronque une chaîne à la longueur maximale donnée.
@@ -0,0 +1,53 @@
+package bot
+
+import (
+	"fmt"
+	"log/slog"
+	"otom-ai/config"
+	"slices"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+// botPrompt cadre les réponses à un autre bot.
+const botPrompt = "Le message auquel tu réponds provient d'un autre bot, pas d'un humain. " +
+	"Réponds brièvement, sans poser de question ni le mentionner, pour ne pas relancer l'échange."
+
+// automated indique si le message provient d'un bot ou d'un webhook.
+func automated(m *discordgo.Message) bool {
+	return m.WebhookID != "" || (m.Author != nil && m.Author.Bot)
+}
+
+// acceptsAutomated applique la configuration bots à un message de bot ou de webhook.
+func acceptsAutomated(m *discordgo.Message, cfg config.BotsConfig) bool {
+	if m.WebhookID != "" {
+		return cfg.Webhooks
+	}
+	return slices.Contains(cfg.Allowed, m.Author.ID)
+}
+
+// loopGuard compte les échanges avec un bot ou un webhook dans le channel et indique
+// s'il faut encore lui répondre : au-delà de bots.loop, le bot se tait jusqu'à la fin de la fenêtre.
+func (b *Bot) loopGuard(m *discordgo.Message) bool {
+	allowed, retryAfter := b.botLoops.Allow(m.ChannelID + ":" + m.Author.ID)
+	if !allowed {
+		b.logger.Warn("Boucle avec un bot détectée, message ignoré",
+			slog.String("bot", m.Author.Username),
+			slog.String("bot_id", m.Author.ID),
+			slog.String("channel", m.ChannelID),
+			slog.Duration("retry_after", retryAfter),
+		)
+	}
+	return allowed
+}
+
+// authorLabel préfixe un message de l'historique avec son auteur, en distinguant bots et webhooks.
+func authorLabel(m *discordgo.Message) string {
+	switch {
+	case m.WebhookID != "":
+		return fmt.Sprintf("[%s · webhook]", m.Author.Username)
+	case m.Author.Bot:
+		return fmt.Sprintf("[%s · bot]", m.Author.Username)
+	}
+	return fmt.Sprintf("[%s]", m.Author.Username)
+}
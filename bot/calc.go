@@ -0,0 +1,48 @@
+package bot
+
+import (
+	"otom-ai/ai"
+	"otom-ai/calc"
+)
+
+// calculatorTools retourne les calculateurs déterministes (dégâts, caractéristiques,
+// expérience) proposés au LLM. Le calculateur d'expérience n'est proposé que si une
+// table d'expérience est configurée.
+func calculatorTools(xp *calc.XPTable) []ai.Tool {
+	tools := []ai.Tool{
+		ai.DamageTool(func(a ai.DamageArgs) (string, error) {
+			r, err := calc.Damage(calc.DamageInput{
+				BaseMin:       a.BaseMin,
+				BaseMax:       a.BaseMax,
+				CritMin:       a.CritMin,
+				CritMax:       a.CritMax,
+				Stat:          a.Stat,
+				Power:         a.Power,
+				Fixed:         a.FixedDamage,
+				CritFixed:     a.CritDamage,
+				FinalPercent:  a.FinalPercent,
+				ResistPercent: a.ResistPercent,
+				ResistFixed:   a.ResistFixed,
+				CritResist:    a.CritResist,
+				CritChance:    a.CritChance,
+			})
+			if err != nil {
+				return "", err
+			}
+			return r.Describe(), nil
+		}),
+		ai.StatPointsTool(func(a ai.StatPointsArgs) (string, error) {
+			return calc.Stats(calc.StatsInput{
+				Characteristic: a.Characteristic,
+				Current:        a.Current,
+				Target:         a.Target,
+				Scrolled:       a.Scrolled,
+				Level:          a.Level,
+			})
+		}),
+	}
+	if xp.MaxLevel() > 0 {
+		tools = append(tools, ai.XPTool(xp.ToLevel))
+	}
+	return tools
+}
@@ -0,0 +1,140 @@
+package bot
+
+import (
+	"log/slog"
+	"math/rand/v2"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+// engagement indique pourquoi le bot répond (ou non) à un message.
+type engagement int
+
+const (
+	ignored     engagement = iota
+	mentioned              // Mention explicite du bot
+	freeTalk               // Channel dédié : réponse sans mention
+	lurking                // Intervention spontanée dans une conversation
+	threadStart            // Mention dans un channel à fils automatiques : la conversation part dans un nouveau fil
+	direct                 // Message privé
+)
+
+// maxThreadName est la longueur maximale du nom d'un fil (limite Discord : 100).
+const maxThreadName = 90
+
+// lurkPass est la réponse par laquelle le LLM renonce à une intervention spontanée.
+const lurkPass = "[PASS]"
+
+// lurkPrompt cadre les interventions spontanées.
+const lurkPrompt = "Personne ne t'a sollicité : tu lis la conversation et peux y intervenir spontanément. " +
+	"N'interviens que si tu apportes une information utile ou une touche d'humour bienvenue, en une ou deux phrases. " +
+	"Sinon, réponds exactement " + lurkPass + " et rien d'autre."
+
+// lurkState mémorise la dernière intervention spontanée par channel.
+type lurkState struct {
+	mu   sync.Mutex
+	last map[string]time.Time
+}
+
+// take réserve une intervention dans le channel si le délai depuis la précédente est écoulé.
+func (l *lurkState) take(channelID string, cooldown time.Duration, now time.Time) bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.last == nil {
+		l.last = map[string]time.Time{}
+	}
+	if now.Sub(l.last[channelID]) < cooldown {
+		return false
+	}
+	l.last[channelID] = now
+	return true
+}
+
+// engagement applique les règles de channels (globales puis du serveur) à un message.
+// Un fil de discussion suit les règles de son channel parent.
+func (b *Bot) engagement(s *discordgo.Session, m *discordgo.Message, svc *services) engagement {
+	channels := []string{m.ChannelID}
+	inThread, botThread := false, false
+	// Cache de la session uniquement : pas d'appel à l'API Discord pour chaque message reçu. Les fils
+	// actifs y sont tenus à jour par la Gateway ; un fil absent est traité comme un channel.
+	if ch, err := s.State.Channel(m.ChannelID); err == nil && ch.IsThread() {
+		channels = append(channels, ch.ParentID)
+		inThread = true
+		botThread = ch.OwnerID == s.State.User.ID
+	}
+	matches := func(list []string) bool {
+		return slices.ContainsFunc(channels, func(id string) bool { return slices.Contains(list, id) })
+	}
+	allows := func(allowed, denied []string) bool {
+		return !matches(denied) && (len(allowed) == 0 || matches(allowed))
+	}
+
+	global := svc.cfg.Channels
+	rules := svc.cfg.GuildChannels(m.GuildID)
+	if !allows(global.Allowed, global.Denied) || !allows(rules.Allowed, rules.Denied) {
+		return ignored
+	}
+	// Channels "fils uniquement" : les messages hors fil sont ignorés
+	if !inThread && slices.Contains(rules.ThreadsOnly, m.ChannelID) {
+		return ignored
+	}
+
+	switch {
+	case b.isMentioned(s, m) && !inThread && slices.Contains(rules.AutoThread, m.ChannelID):
+		return threadStart
+	case b.isMentioned(s, m):
+		return mentioned
+	case matches(rules.Free):
+		return freeTalk
+	case botThread && matches(rules.AutoThread):
+		return freeTalk // Suite d'une conversation dans un fil ouvert par le bot
+	}
+
+	// MinWords et Cooldown sont renseignés par la configuration dès que le serveur est configuré
+	lurk := rules.Lurk
+	if lurk.Probability <= 0 || len(strings.Fields(m.Content)) < *lurk.MinWords || rand.Float64() >= lurk.Probability {
+		return ignored
+	}
+	if !b.lurk.take(m.ChannelID, *lurk.Cooldown, time.Now()) {
+		return ignored
+	}
+	return lurking
+}
+
+// channel retourne un channel depuis le cache de la session, ou l'API Discord à défaut.
+func (b *Bot) channel(s *discordgo.Session, id string) *discordgo.Channel {
+	if ch, err := s.State.Channel(id); err == nil {
+		return ch
+	}
+	ch, err := s.Channel(id)
+	if err != nil {
+		b.logger.Warn("Channel introuvable", slog.String("channel", id), slog.String("error", err.Error()))
+		return nil
+	}
+	return ch
+}
+
+// startThread ouvre un fil à partir du message, nommé d'après la question.
+func (b *Bot) startThread(s *discordgo.Session, m *discordgo.Message, question string, svc *services) (*discordgo.Channel, error) {
+	return s.MessageThreadStartComplex(m.ChannelID, m.ID, &discordgo.ThreadStart{
+		Name:                threadName(question, m.Author.Username),
+		AutoArchiveDuration: svc.cfg.GuildChannels(m.GuildID).ThreadArchiveMinutes(),
+	})
+}
+
+// threadName tire le nom d'un fil de la première ligne de la question.
+func threadName(question, username string) string {
+	line, _, _ := strings.Cut(question, "\n")
+	name := strings.Join(strings.Fields(line), " ")
+	if name == "" {
+		return "Discussion avec " + username
+	}
+	if r := []rune(name); len(r) > maxThreadName {
+		name = strings.TrimSpace(string(r[:maxThreadName-1])) + "…"
+	}
+	return name
+}
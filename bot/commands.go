@@ -0,0 +1,154 @@
+package bot
+
+import (
+	"log/slog"
+	"strings"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+// command associe une commande slash Discord à son handler.
+type command struct {
+	def     *discordgo.ApplicationCommand
+	handler func(s *discordgo.Session, i *discordgo.InteractionCreate)
+}
+
+// component associe un préfixe d'identifiant de composant (bouton...) à son handler.
+// L'identifiant complet est de la forme "<préfixe>:<arg1>:<arg2>...".
+type component struct {
+	prefix  string
+	handler func(s *discordgo.Session, i *discordgo.InteractionCreate, args []string)
+}
+
+// commands retourne toutes les commandes slash du bot.
+func (b *Bot) commands() []command {
+	return []command{
+		b.adminCommand(),
+		b.pricesCommand(),
+		b.dungeonCommand(),
+		b.profileCommand(),
+		b.remindersCommand(),
+		b.guildKBCommand(),
+		b.privacyCommand(),
+	}
+}
+
+// components retourne tous les handlers de composants interactifs du bot.
+func (b *Bot) components() []component {
+	return []component{
+		{prefix: lfgComponent, handler: b.handleLFGButton},
+		{prefix: replyComponent, handler: b.handleReplyButton},
+		{prefix: privacyComponent, handler: b.handlePrivacyButton},
+	}
+}
+
+// registerCommands déclare (ou met à jour) les commandes slash globales auprès de Discord.
+func (b *Bot) registerCommands(s *discordgo.Session) {
+	cmds := b.commands()
+	defs := make([]*discordgo.ApplicationCommand, 0, len(cmds))
+	for _, c := range cmds {
+		defs = append(defs, c.def)
+	}
+
+	if _, err := s.ApplicationCommandBulkOverwrite(s.State.User.ID, "", defs); err != nil {
+		b.logger.Error("Impossible d'enregistrer les commandes slash", slog.String("error", err.Error()))
+		return
+	}
+	b.logger.Info("Commandes slash enregistrées", slog.Int("count", len(defs)))
+}
+
+// onInteractionCreate distribue les interactions (commandes slash, boutons) vers leur handler.
+func (b *Bot) onInteractionCreate(s *discordgo.Session, i *discordgo.InteractionCreate) {
+	switch i.Type {
+	case discordgo.InteractionApplicationCommand:
+		name := i.ApplicationCommandData().Name
+		for _, c := range b.commands() {
+			if c.def.Name == name {
+				c.handler(s, i)
+				return
+			}
+		}
+		b.logger.Warn("Commande slash inconnue", slog.String("command", name))
+	case discordgo.InteractionMessageComponent:
+		parts := strings.Split(i.MessageComponentData().CustomID, ":")
+		for _, c := range b.components() {
+			if c.prefix == parts[0] {
+				c.handler(s, i, parts[1:])
+				return
+			}
+		}
+		b.logger.Warn("Composant inconnu", slog.String("custom_id", i.MessageComponentData().CustomID))
+	}
+}
+
+// ---------- Utilitaires d'interaction ----------
+
+// respond répond à une interaction. Une réponse éphémère n'est visible que par son auteur.
+func (b *Bot) respond(s *discordgo.Session, i *discordgo.InteractionCreate, content string, ephemeral bool) {
+	data := &discordgo.InteractionResponseData{Content: truncate(content, 2000)}
+	if ephemeral {
+		data.Flags = discordgo.MessageFlagsEphemeral
+	}
+	if err := s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseChannelMessageWithSource,
+		Data: data,
+	}); err != nil {
+		b.logger.Error("Impossible de répondre à l'interaction",
+			slog.String("channel", i.ChannelID),
+			slog.String("error", err.Error()),
+		)
+	}
+}
+
+// deferResponse accuse réception d'une interaction dont le traitement dépasse 3 secondes.
+// La réponse définitive est envoyée ensuite avec editResponse.
+func (b *Bot) deferResponse(s *discordgo.Session, i *discordgo.InteractionCreate, ephemeral bool) error {
+	data := &discordgo.InteractionResponseData{}
+	if ephemeral {
+		data.Flags = discordgo.MessageFlagsEphemeral
+	}
+	err := s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseDeferredChannelMessageWithSource,
+		Data: data,
+	})
+	if err != nil {
+		b.logger.Error("Impossible d'accuser réception de l'interaction",
+			slog.String("channel", i.ChannelID),
+			slog.String("error", err.Error()),
+		)
+	}
+	return err
+}
+
+// editResponse remplace la réponse (différée ou non) d'une interaction.
+func (b *Bot) editResponse(s *discordgo.Session, i *discordgo.InteractionCreate, content string) {
+	content = truncate(content, 2000)
+	if _, err := s.InteractionResponseEdit(i.Interaction, &discordgo.WebhookEdit{Content: &content}); err != nil {
+		b.logger.Error("Impossible de modifier la réponse à l'interaction",
+			slog.String("channel", i.ChannelID),
+			slog.String("error", err.Error()),
+		)
+	}
+}
+
+// interactionUser retourne l'auteur d'une interaction (en serveur ou en message privé).
+func interactionUser(i *discordgo.InteractionCreate) *discordgo.User {
+	if i.Member != nil {
+		return i.Member.User
+	}
+	return i.User
+}
+
+// isAdmin indique si l'auteur de l'interaction est administrateur du serveur.
+func isAdmin(i *discordgo.InteractionCreate) bool {
+	return i.Member != nil && i.Member.Permissions&discordgo.PermissionAdministrator != 0
+}
+
+// optionMap indexe les options d'une commande par nom.
+func optionMap(opts []*discordgo.ApplicationCommandInteractionDataOption) map[string]*discordgo.ApplicationCommandInteractionDataOption {
+	m := make(map[string]*discordgo.ApplicationCommandInteractionDataOption, len(opts))
+	for _, o := range opts {
+		m[o.Name] = o
+	}
+	return m
+}
@@ -0,0 +1,181 @@
+package bot
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"maps"
+	"net/http"
+	"otom-ai/ai"
+	"otom-ai/conversations"
+	"otom-ai/untrusted"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+const (
+	// sharedGuildTTL est la durée de validité de la vérification "membre d'un serveur du bot".
+	sharedGuildTTL = time.Hour
+	// notSharedTTL est la durée de validité d'une vérification négative : plus courte, pour qu'un
+	// joueur qui vient de rejoindre un serveur n'attende pas une heure, mais assez longue pour
+	// qu'un inconnu insistant ne déclenche pas un appel à l'API par serveur à chaque message.
+	notSharedTTL = 10 * time.Minute
+	// dmPurgeJob est la tâche planifiée de purge de la mémoire des messages privés.
+	dmPurgeJob = "dm:purge"
+)
+
+// memberCache mémorise, par utilisateur, s'il partage un serveur avec le bot.
+type memberCache struct {
+	mu      sync.Mutex
+	entries map[string]memberEntry
+}
+
+// memberEntry est une vérification mise en cache.
+type memberEntry struct {
+	shared bool
+	at     time.Time
+}
+
+// onDirectMessage traite un message privé : activation, serveur commun, rate limit et quota
+// propres aux messages privés, puis appel IA avec la mémoire de l'utilisateur.
+func (b *Bot) onDirectMessage(s *discordgo.Session, m *discordgo.MessageCreate, svc *services) {
+	dm := svc.cfg.DM
+	if !dm.Enabled {
+		return
+	}
+
+	if dm.RequireSharedGuild && !b.sharesGuild(s, m.Author.ID) {
+		b.logger.Info("Message privé refusé : aucun serveur en commun", slog.String("user", m.Author.Username))
+		b.replyToMessage(s, m.Message, "🔒 Je ne discute en privé qu'avec les membres des serveurs où je suis installé. On se retrouve en guilde ?")
+		return
+	}
+
+	if allowed, retryAfter := b.dmLimiter.Allow(m.Author.ID); !allowed {
+		b.replyToMessage(s, m.Message, fmt.Sprintf(
+			"⏳ Doucement, même en privé ! Attends encore %.1f secondes.",
+			retryAfter.Seconds(),
+		))
+		return
+	}
+	if allowed, retryAfter := b.dmQuota.Allow(m.Author.ID); !allowed {
+		b.replyToMessage(s, m.Message, fmt.Sprintf(
+			"🌙 Tu as épuisé tes %d messages privés du jour. Reviens dans %s, ou retrouve-moi sur ton serveur !",
+			dm.DailyQuota, retryAfter.Round(time.Minute),
+		))
+		return
+	}
+
+	b.handleAIResponse(s, m, svc, direct)
+}
+
+// dmHistory retourne la mémoire d'un utilisateur en message privé sous forme de messages AI,
+// et sous forme d'historique conservé avec les avis.
+func (b *Bot) dmHistory(svc *services, user *discordgo.User) ([]ai.Message, []conversations.Turn) {
+	if b.optedOut(user.ID) {
+		return nil, nil
+	}
+	since := time.Now().Add(-svc.cfg.DM.MemoryTTL)
+	var history []ai.Message
+	var turns []conversations.Turn
+	for _, e := range b.conversations.Recent(user.ID, since) {
+		prompt := untrusted.Strip(e.Prompt)
+		history = append(history,
+			ai.Message{Role: "user", Content: fmt.Sprintf("[%s] %s", user.Username, prompt)},
+			ai.Message{Role: "assistant", Content: e.Reply},
+		)
+		turns = append(turns,
+			conversations.Turn{Author: user.Username, AuthorID: user.ID, Content: prompt},
+			conversations.Turn{Content: e.Reply, Bot: true},
+		)
+	}
+	return history, turns
+}
+
+// rememberDM ajoute un échange à la mémoire d'un utilisateur en message privé.
+func (b *Bot) rememberDM(svc *services, userID, prompt, reply string) {
+	if svc.cfg.DM.Memory == 0 || b.optedOut(userID) {
+		return
+	}
+	e := conversations.Exchange{Prompt: prompt, Reply: reply, At: time.Now()}
+	if err := b.conversations.Add(userID, e, svc.cfg.DM.Memory); err != nil {
+		b.logger.Error("Mémoire de la conversation non enregistrée", slog.String("error", err.Error()))
+	}
+}
+
+// sharesGuild indique si l'utilisateur est membre d'au moins un serveur où se trouve le bot.
+// Le cache des membres de la session est consulté d'abord pour tous les serveurs ; l'API Discord
+// n'est interrogée qu'à défaut. Les résultats, positifs comme négatifs, sont mis en cache ; un
+// résultat incertain (erreur de l'API) ne l'est pas.
+func (b *Bot) sharesGuild(s *discordgo.Session, userID string) bool {
+	now := time.Now()
+	b.members.mu.Lock()
+	e, ok := b.members.entries[userID]
+	b.members.mu.Unlock()
+	if ok && (now.Sub(e.at) < notSharedTTL || (e.shared && now.Sub(e.at) < sharedGuildTTL)) {
+		return e.shared
+	}
+
+	s.State.RLock()
+	guilds := make([]string, 0, len(s.State.Guilds))
+	for _, g := range s.State.Guilds {
+		guilds = append(guilds, g.ID)
+	}
+	s.State.RUnlock()
+
+	shared := slices.ContainsFunc(guilds, func(guildID string) bool {
+		_, err := s.State.Member(guildID, userID)
+		return err == nil
+	})
+	certain := true
+	for _, guildID := range guilds {
+		if shared {
+			break
+		}
+		_, err := s.GuildMember(guildID, userID)
+		if err == nil {
+			shared = true
+			break
+		}
+		var restErr *discordgo.RESTError
+		if !errors.As(err, &restErr) || restErr.Response.StatusCode != http.StatusNotFound {
+			certain = false
+			b.logger.Warn("Vérification de l'appartenance au serveur impossible",
+				slog.String("guild", guildID),
+				slog.String("error", err.Error()),
+			)
+		}
+	}
+	if !shared && !certain {
+		return false
+	}
+
+	b.members.mu.Lock()
+	if b.members.entries == nil {
+		b.members.entries = map[string]memberEntry{}
+	}
+	// Les vérifications expirées sont oubliées au passage, pour que le cache ne grossisse pas indéfiniment
+	maps.DeleteFunc(b.members.entries, func(_ string, e memberEntry) bool { return now.Sub(e.at) >= sharedGuildTTL })
+	b.members.entries[userID] = memberEntry{shared: shared, at: now}
+	b.members.mu.Unlock()
+	return shared
+}
+
+// scheduleDMPurge (re)programme la purge quotidienne de la mémoire des messages privés, pour que
+// les échanges plus anciens que dm.memory_ttl soient effacés même sans redémarrage.
+func (b *Bot) scheduleDMPurge(svc *services) {
+	if err := b.scheduler.Daily(dmPurgeJob, "04:30", svc.cfg.Location(), b.purgeDMs); err != nil {
+		b.logger.Error("Purge de la mémoire des messages privés non planifiée", slog.String("error", err.Error()))
+	}
+}
+
+// purgeDMs efface de la mémoire des messages privés les échanges antérieurs à dm.memory_ttl.
+func (b *Bot) purgeDMs(context.Context) {
+	cfg := b.services.Load().cfg
+	if err := b.conversations.Prune(time.Now().Add(-cfg.DM.MemoryTTL)); err != nil {
+		b.logger.Warn("Purge de la mémoire des conversations impossible", slog.String("error", err.Error()))
+	}
+}
@@ -0,0 +1,174 @@
+package bot
+
+import (
+	"fmt"
+	"log/slog"
+	"otom-ai/ai"
+	"otom-ai/conversations"
+	"otom-ai/persona"
+	"strings"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+// Réactions ajoutées sous les réponses du bot pour recueillir l'avis des utilisateurs.
+const (
+	feedbackUp   = "👍"
+	feedbackDown = "👎"
+)
+
+// feedbackVote traduit une réaction en vote (0 si ce n'est pas une réaction d'avis).
+func feedbackVote(emoji string) int {
+	switch emoji {
+	case feedbackUp:
+		return 1
+	case feedbackDown:
+		return -1
+	}
+	return 0
+}
+
+// recordAnswer enregistre une réponse publiée avec le contexte qui l'a produite,
+// puis ajoute les réactions 👍/👎 qui permettent de la noter.
+// Rien n'est conservé pour un utilisateur ayant refusé la lecture de ses messages.
+func (b *Bot) recordAnswer(s *discordgo.Session, sent, m *discordgo.Message, svc *services, question string, turns []conversations.Turn, result *ai.CompletionResult) {
+	if b.optedOut(m.Author.ID) {
+		return
+	}
+	tools := make([]string, 0, len(result.ToolUses))
+	for _, use := range result.ToolUses {
+		tools = append(tools, use.Name)
+	}
+	now := time.Now()
+	answer := conversations.Answer{
+		MessageID:      sent.ID,
+		GuildID:        m.GuildID,
+		ChannelID:      sent.ChannelID,
+		Author:         m.Author.Username,
+		AuthorID:       m.Author.ID,
+		History:        turns,
+		Question:       question,
+		Reply:          sent.Content,
+		Tools:          tools,
+		Model:          svc.cfg.Provider().Model,
+		Persona:        svc.cfg.Persona,
+		PersonaVersion: persona.Version(svc.cfg.SystemPrompt()),
+		At:             now,
+	}
+	if err := b.feedback.Record(answer, svc.cfg.Feedback.Max); err != nil {
+		b.logger.Error("Réponse non enregistrée pour les avis", slog.String("error", err.Error()))
+		return
+	}
+	if err := b.feedback.Prune(now.Add(-svc.cfg.Feedback.Retention)); err != nil {
+		b.logger.Warn("Purge des avis impossible", slog.String("error", err.Error()))
+	}
+
+	for _, emoji := range []string{feedbackUp, feedbackDown} {
+		if err := s.MessageReactionAdd(sent.ChannelID, sent.ID, emoji); err != nil {
+			b.logger.Warn("Impossible d'ajouter la réaction d'avis",
+				slog.String("channel", sent.ChannelID),
+				slog.String("error", err.Error()),
+			)
+			return
+		}
+	}
+}
+
+// onReactionAdd enregistre un avis 👍/👎 sur une réponse suivie.
+func (b *Bot) onReactionAdd(s *discordgo.Session, r *discordgo.MessageReactionAdd) {
+	vote := feedbackVote(r.Emoji.Name)
+	if vote == 0 || r.UserID == s.State.User.ID || b.optedOut(r.UserID) {
+		return
+	}
+	tracked, err := b.feedback.Vote(r.MessageID, r.UserID, vote)
+	if err != nil {
+		b.logger.Error("Avis non enregistré", slog.String("message", r.MessageID), slog.String("error", err.Error()))
+		return
+	}
+	if tracked {
+		b.logger.Info("Avis sur une réponse",
+			slog.String("message", r.MessageID),
+			slog.String("user_id", r.UserID),
+			slog.Int("vote", vote),
+		)
+	}
+}
+
+// onReactionRemove retire l'avis correspondant à une réaction retirée.
+func (b *Bot) onReactionRemove(s *discordgo.Session, r *discordgo.MessageReactionRemove) {
+	vote := feedbackVote(r.Emoji.Name)
+	if vote == 0 || r.UserID == s.State.User.ID {
+		return
+	}
+	if _, err := b.feedback.Unvote(r.MessageID, r.UserID, vote); err != nil {
+		b.logger.Error("Avis non retiré", slog.String("message", r.MessageID), slog.String("error", err.Error()))
+	}
+}
+
+// feedbackGroup cumule les avis des réponses produites par une même version du persona et un même modèle.
+type feedbackGroup struct {
+	persona, version, model string
+	answers, up, down       int
+}
+
+// handleAdminFeedback affiche le bilan des avis sur les réponses du serveur (/admin avis).
+func (b *Bot) handleAdminFeedback(s *discordgo.Session, i *discordgo.InteractionCreate) {
+	if !b.services.Load().cfg.Feedback.Enabled {
+		b.respond(s, i, "📭 La collecte des avis est désactivée (feedback.enabled).", true)
+		return
+	}
+	var answers []conversations.Answer
+	for _, a := range b.feedback.Answers() {
+		if a.GuildID == i.GuildID {
+			answers = append(answers, a)
+		}
+	}
+	b.respond(s, i, truncate(describeFeedback(answers), 2000), true)
+}
+
+// describeFeedback formate le bilan des avis par version du persona, puis les dernières réponses mal notées.
+func describeFeedback(answers []conversations.Answer) string {
+	if len(answers) == 0 {
+		return "📭 Aucune réponse suivie pour le moment."
+	}
+	var (
+		groups []*feedbackGroup
+		byKey  = map[string]*feedbackGroup{}
+		bad    []conversations.Answer
+	)
+	for _, a := range answers {
+		key := a.Persona + "/" + a.PersonaVersion + "/" + a.Model
+		g, ok := byKey[key]
+		if !ok {
+			g = &feedbackGroup{persona: a.Persona, version: a.PersonaVersion, model: a.Model}
+			byKey[key] = g
+			groups = append(groups, g)
+		}
+		up, down := a.Score()
+		g.answers++
+		g.up += up
+		g.down += down
+		if down > up {
+			bad = append(bad, a)
+		}
+	}
+
+	var sb strings.Builder
+	sb.WriteString("📊 **Avis sur les réponses** (export complet : `otom-ai feedback export`)\n")
+	for _, g := range groups {
+		fmt.Fprintf(&sb, "- **%s** `%s` (%s) : %d réponses, %s %d / %s %d", g.persona, g.version, g.model, g.answers, feedbackUp, g.up, feedbackDown, g.down)
+		if votes := g.up + g.down; votes > 0 {
+			fmt.Fprintf(&sb, " → %d%% satisfaits", g.up*100/votes)
+		}
+		sb.WriteString("\n")
+	}
+	if len(bad) > 0 {
+		sb.WriteString("\n👎 **Dernières réponses mal notées**\n")
+		for n := len(bad) - 1; n >= 0 && n >= len(bad)-5; n-- {
+			a := bad[n]
+			fmt.Fprintf(&sb, "- %s : « %s » → « %s »\n", a.Author, truncate(a.Question, 80), truncate(a.Reply, 120))
+		}
+	}
+	return sb.String()
+}
@@ -0,0 +1,217 @@
+package bot
+
+import (
+	"cmp"
+	"errors"
+	"fmt"
+	"log/slog"
+	"otom-ai/frtime"
+	"otom-ai/guildkb"
+	"strings"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+// guildKBContextChars borne la taille des entrées de la fiche injectées dans le contexte du LLM.
+const guildKBContextChars = 4000
+
+// guildKBCommand définit la commande /fiche (fiche de connaissances du serveur, réservée aux administrateurs).
+func (b *Bot) guildKBCommand() command {
+	minID := 1.0
+	idOption := func(desc string) *discordgo.ApplicationCommandOption {
+		return &discordgo.ApplicationCommandOption{Type: discordgo.ApplicationCommandOptionInteger, Name: "id", Description: desc, Required: true, MinValue: &minID}
+	}
+	keywordsDesc := fmt.Sprintf("Mots-clés séparés par des virgules (%q : toujours transmise)", guildkb.Always)
+
+	return command{
+		def: &discordgo.ApplicationCommand{
+			Name:                     "fiche",
+			Description:              "Fiche de connaissances du serveur (règlement, recrutement...) transmise au bot",
+			DefaultMemberPermissions: &adminPermission,
+			Options: []*discordgo.ApplicationCommandOption{
+				{
+					Type:        discordgo.ApplicationCommandOptionSubCommand,
+					Name:        "ajouter",
+					Description: "Ajoute une entrée",
+					Options: []*discordgo.ApplicationCommandOption{
+						{Type: discordgo.ApplicationCommandOptionString, Name: "titre", Description: "Titre de l'entrée", Required: true, MaxLength: 100},
+						{Type: discordgo.ApplicationCommandOptionString, Name: "mots-cles", Description: keywordsDesc, Required: true},
+						{Type: discordgo.ApplicationCommandOptionString, Name: "texte", Description: "Contenu transmis au bot quand un message évoque ces mots-clés", Required: true, MaxLength: guildkb.MaxText},
+					},
+				},
+				{
+					Type:        discordgo.ApplicationCommandOptionSubCommand,
+					Name:        "modifier",
+					Description: "Modifie une entrée (les champs omis sont conservés)",
+					Options: []*discordgo.ApplicationCommandOption{
+						idOption("Numéro de l'entrée (voir /fiche liste)"),
+						{Type: discordgo.ApplicationCommandOptionString, Name: "titre", Description: "Nouveau titre", MaxLength: 100},
+						{Type: discordgo.ApplicationCommandOptionString, Name: "mots-cles", Description: "Nouveaux mots-clés"},
+						{Type: discordgo.ApplicationCommandOptionString, Name: "texte", Description: "Nouveau contenu", MaxLength: guildkb.MaxText},
+					},
+				},
+				{
+					Type:        discordgo.ApplicationCommandOptionSubCommand,
+					Name:        "supprimer",
+					Description: "Supprime une entrée (restaurable depuis l'historique)",
+					Options: []*discordgo.ApplicationCommandOption{
+						idOption("Numéro de l'entrée (voir /fiche liste)"),
+					},
+				},
+				{
+					Type:        discordgo.ApplicationCommandOptionSubCommand,
+					Name:        "liste",
+					Description: "Affiche les entrées de la fiche",
+				},
+				{
+					Type:        discordgo.ApplicationCommandOptionSubCommand,
+					Name:        "historique",
+					Description: "Affiche les dernières modifications",
+					Options: []*discordgo.ApplicationCommandOption{
+						{Type: discordgo.ApplicationCommandOptionInteger, Name: "id", Description: "Limiter à une entrée", MinValue: &minID},
+					},
+				},
+				{
+					Type:        discordgo.ApplicationCommandOptionSubCommand,
+					Name:        "restaurer",
+					Description: "Rétablit une entrée telle qu'elle était à une révision",
+					Options: []*discordgo.ApplicationCommandOption{
+						{Type: discordgo.ApplicationCommandOptionInteger, Name: "version", Description: "Numéro de révision (voir /fiche historique)", Required: true, MinValue: &minID},
+					},
+				},
+			},
+		},
+		handler: b.handleGuildKB,
+	}
+}
+
+// handleGuildKB traite la commande /fiche.
+func (b *Bot) handleGuildKB(s *discordgo.Session, i *discordgo.InteractionCreate) {
+	if i.GuildID == "" {
+		b.respond(s, i, "📜 La fiche se gère depuis un serveur.", true)
+		return
+	}
+	if !isAdmin(i) {
+		b.respond(s, i, "🛡️ Seuls les administrateurs peuvent modifier la fiche du serveur.", true)
+		return
+	}
+	data := i.ApplicationCommandData()
+	if len(data.Options) == 0 {
+		return
+	}
+	sub := data.Options[0]
+	opts := optionMap(sub.Options)
+	user := interactionUser(i)
+	editor := guildkb.Editor{ID: user.ID, Name: user.Username}
+	now := time.Now()
+
+	str := func(name string) string {
+		if o, ok := opts[name]; ok {
+			return o.StringValue()
+		}
+		return ""
+	}
+	id := 0
+	if o, ok := opts["id"]; ok {
+		id = int(o.IntValue())
+	}
+
+	var (
+		e      guildkb.Entry
+		err    error
+		action string
+	)
+	switch sub.Name {
+	case "ajouter":
+		e, err = b.guildKB.Add(i.GuildID, str("titre"), str("mots-cles"), str("texte"), editor, now)
+		action = "ajoutée"
+	case "modifier":
+		e, err = b.guildKB.Edit(i.GuildID, id, str("titre"), str("mots-cles"), str("texte"), editor, now)
+		action = "modifiée"
+	case "supprimer":
+		e, err = b.guildKB.Delete(i.GuildID, id, editor, now)
+		action = "supprimée"
+	case "restaurer":
+		e, err = b.guildKB.Restore(i.GuildID, int(opts["version"].IntValue()), editor, now)
+		action = "restaurée"
+	case "liste":
+		b.respond(s, i, truncate(describeGuildKB(b.guildKB.Entries(i.GuildID)), 2000), true)
+		return
+	case "historique":
+		loc := b.services.Load().cfg.Location()
+		b.respond(s, i, truncate(describeGuildKBHistory(b.guildKB.History(i.GuildID, id), loc), 2000), true)
+		return
+	default:
+		return
+	}
+
+	if errors.Is(err, guildkb.ErrNotFound) {
+		b.respond(s, i, fmt.Sprintf("🤷 Aucune entrée #%d dans la fiche.", id), true)
+		return
+	}
+	if err != nil {
+		b.respond(s, i, "❌ "+err.Error(), true)
+		return
+	}
+	b.logger.Info("Fiche du serveur modifiée",
+		slog.String("guild", i.GuildID),
+		slog.String("user", user.Username),
+		slog.String("action", sub.Name),
+		slog.Int("entry", e.ID),
+	)
+	b.auditAdmin(s, i, "Fiche du serveur : entrée "+action, e.Format())
+	b.respond(s, i, fmt.Sprintf("📜 Entrée %s : %s", action, e.Format()), true)
+}
+
+// guildKBContext présente au LLM les entrées de la fiche du serveur évoquées par le message ("" si aucune).
+func (b *Bot) guildKBContext(guildID, message string) string {
+	if guildID == "" {
+		return ""
+	}
+	entries := b.guildKB.Relevant(guildID, message, guildKBContextChars)
+	if len(entries) == 0 {
+		return ""
+	}
+	var sb strings.Builder
+	sb.WriteString("Informations propres à ce serveur Discord, rédigées par ses administrateurs (elles priment sur tes connaissances générales) :\n")
+	for _, e := range entries {
+		fmt.Fprintf(&sb, "## %s\n%s\n", e.Title, e.Text)
+	}
+	return sb.String()
+}
+
+// describeGuildKB formate la liste des entrées de la fiche.
+func describeGuildKB(entries []guildkb.Entry) string {
+	if len(entries) == 0 {
+		return "📭 La fiche est vide. Ajoute le règlement ou les conditions de recrutement avec `/fiche ajouter`."
+	}
+	var sb strings.Builder
+	total := 0
+	for _, e := range entries {
+		total += len([]rune(e.Text))
+	}
+	fmt.Fprintf(&sb, "📜 **Fiche du serveur** (%d/%d entrées, %d/%d caractères)\n", len(entries), guildkb.MaxEntries, total, guildkb.MaxTotal)
+	for _, e := range entries {
+		fmt.Fprintf(&sb, "- %s : %s\n", e.Format(), truncate(e.Text, 80))
+	}
+	return sb.String()
+}
+
+// describeGuildKBHistory formate les dernières révisions de la fiche.
+func describeGuildKBHistory(revisions []guildkb.Revision, loc *time.Location) string {
+	if len(revisions) == 0 {
+		return "📭 Aucune modification enregistrée."
+	}
+	var sb strings.Builder
+	sb.WriteString("🕰️ **Historique de la fiche** (restaurer avec `/fiche restaurer version:<n>`)\n")
+	for n, r := range revisions {
+		if n == 20 {
+			fmt.Fprintf(&sb, "… et %d révisions plus anciennes\n", len(revisions)-n)
+			break
+		}
+		fmt.Fprintf(&sb, "- v%d %s de #%d « %s » par %s le %s\n",
+			r.Version, r.Action, r.Entry.ID, r.Entry.Title, cmp.Or(r.By, "un ancien administrateur"), frtime.DateTime(r.At.In(loc)))
+	}
+	return sb.String()
+}
@@ -0,0 +1,87 @@
+package bot
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+// reindexTimeout borne la durée d'une indexation (calcul des embeddings compris).
+const reindexTimeout = 15 * time.Minute
+
+// handleAdminReindex reconstruit l'index de la base de connaissances en arrière-plan.
+// Avec l'option dossier, seuls les documents de ce sous-dossier de knowledge.docs_dir sont
+// réindexés, le reste de l'index est conservé.
+func (b *Bot) handleAdminReindex(s *discordgo.Session, i *discordgo.InteractionCreate, opts map[string]*discordgo.ApplicationCommandInteractionDataOption) {
+	svc := b.services.Load()
+	if svc.knowledge == nil {
+		b.respond(s, i, "📚 La base de connaissances n'est pas configurée (section `knowledge.embeddings`).", true)
+		return
+	}
+
+	sub, label := "", svc.cfg.Knowledge.DocsDir
+	if o, ok := opts["dossier"]; ok {
+		sub = filepath.Clean(o.StringValue())
+		if !filepath.IsLocal(sub) {
+			b.respond(s, i, fmt.Sprintf("❌ Dossier invalide %q : il doit se trouver dans `%s`.", o.StringValue(), label), true)
+			return
+		}
+		label = filepath.Join(label, sub)
+	}
+
+	if !b.reindexing.CompareAndSwap(false, true) {
+		b.respond(s, i, "⏳ Une indexation est déjà en cours, patience !", true)
+		return
+	}
+	if err := b.deferResponse(s, i, true); err != nil {
+		b.reindexing.Store(false)
+		return
+	}
+
+	b.logger.Warn("Réindexation de la base de connaissances",
+		slog.String("admin", interactionUser(i).Username),
+		slog.String("dir", label),
+	)
+	b.auditAdmin(s, i, "Réindexation de la base de connaissances", fmt.Sprintf("Dossier `%s`", label))
+
+	go func() {
+		defer b.reindexing.Store(false)
+
+		// La configuration a pu être rechargée depuis la commande
+		svc := b.services.Load()
+		if svc.knowledge == nil {
+			b.editResponse(s, i, "📚 La base de connaissances n'est plus configurée.")
+			return
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), reindexTimeout)
+		defer cancel()
+
+		start := time.Now()
+		stats, err := svc.knowledge.Reindex(ctx, svc.cfg.Knowledge.DocsDir, sub)
+		if err != nil {
+			b.logger.Error("Échec de la réindexation", slog.String("dir", label), slog.String("error", err.Error()))
+			b.editResponse(s, i, fmt.Sprintf("❌ Indexation de `%s` impossible : %s", label, err))
+			return
+		}
+
+		b.logger.Info("Base de connaissances réindexée",
+			slog.String("dir", label),
+			slog.Int("documents", stats.Documents),
+			slog.Int("chunks", stats.Chunks),
+			slog.Int("skipped", len(stats.Skipped)),
+			slog.Duration("duration", time.Since(start)),
+		)
+		msg := fmt.Sprintf("📚 Index reconstruit depuis `%s` : %d documents, %d passages (%s).",
+			label, stats.Documents, stats.Chunks, time.Since(start).Round(time.Second))
+		if len(stats.Skipped) > 0 {
+			msg += fmt.Sprintf("\n⚠️ Ignorés (illisibles ou vides) : %s", strings.Join(stats.Skipped, ", "))
+		}
+		b.editResponse(s, i, msg)
+	}()
+}
@@ -0,0 +1,424 @@
+package bot
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"otom-ai/frtime"
+	"otom-ai/lfg"
+	"strings"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+const (
+	// lfgComponent préfixe les identifiants des boutons d'inscription ("lfg:join:<id>:<rôle>").
+	lfgComponent = "lfg"
+	// lfgJobPrefix préfixe les tâches planifiées de rappel des sorties.
+	lfgJobPrefix = "lfg:"
+	// lfgPurgeJob est la tâche planifiée de purge des anciennes sorties (hors de lfgJobPrefix).
+	lfgPurgeJob = "lfg-purge"
+	// lfgMaxAhead borne la date d'une sortie.
+	lfgMaxAhead = 60 * 24 * time.Hour
+	// lfgMaxSlots borne le nombre de places par rôle.
+	lfgMaxSlots = 8
+	// lfgKeep est la durée de conservation d'une sortie après son début.
+	lfgKeep = 24 * time.Hour
+)
+
+// runRequest décrit une sortie à créer (commande /donjon ou outil du LLM).
+type runRequest struct {
+	GuildID   string
+	ChannelID string
+	CreatorID string
+	Dungeon   string
+	When      string // Date et heure en français ("samedi 21h") ou ISO
+	Slots     map[lfg.Role]int
+	Classes   string
+	Note      string
+}
+
+// dungeonCommand définit la commande /donjon (organisation des sorties de groupe).
+func (b *Bot) dungeonCommand() command {
+	minSlots, maxSlots := 0.0, float64(lfgMaxSlots)
+	slot := func(name, desc string) *discordgo.ApplicationCommandOption {
+		return &discordgo.ApplicationCommandOption{Type: discordgo.ApplicationCommandOptionInteger, Name: name, Description: desc, MinValue: &minSlots, MaxValue: maxSlots}
+	}
+
+	return command{
+		def: &discordgo.ApplicationCommand{
+			Name:        "donjon",
+			Description: "Organise une sortie donjon ou boss",
+			Options: []*discordgo.ApplicationCommandOption{
+				{
+					Type:        discordgo.ApplicationCommandOptionSubCommand,
+					Name:        "create",
+					Description: "Planifie une sortie avec inscriptions par rôle",
+					Options: []*discordgo.ApplicationCommandOption{
+						{Type: discordgo.ApplicationCommandOptionString, Name: "donjon", Description: "Donjon ou boss (ex: Comte Harebourg)", Required: true},
+						{Type: discordgo.ApplicationCommandOptionString, Name: "quand", Description: "Date et heure (ex: samedi 21h, demain 20h30, 24/10 21h)", Required: true},
+						slot("tanks", "Places de tank (défaut : 1)"),
+						slot("soins", "Places de soigneur (défaut : 1)"),
+						slot("dps", "Places de DPS (défaut : 2)"),
+						{Type: discordgo.ApplicationCommandOptionString, Name: "classes", Description: "Classes souhaitées (ex: Eniripsa, Sacrieur)"},
+						{Type: discordgo.ApplicationCommandOptionString, Name: "note", Description: "Infos complémentaires (succès visés, niveau minimum...)"},
+					},
+				},
+				{
+					Type:        discordgo.ApplicationCommandOptionSubCommand,
+					Name:        "list",
+					Description: "Liste les sorties à venir",
+				},
+				{
+					Type:        discordgo.ApplicationCommandOptionSubCommand,
+					Name:        "cancel",
+					Description: "Annule une sortie (organisateur ou administrateur)",
+					Options: []*discordgo.ApplicationCommandOption{
+						{Type: discordgo.ApplicationCommandOptionString, Name: "id", Description: "Identifiant de la sortie (voir /donjon list)", Required: true},
+					},
+				},
+			},
+		},
+		handler: b.handleDungeon,
+	}
+}
+
+// handleDungeon traite la commande /donjon.
+func (b *Bot) handleDungeon(s *discordgo.Session, i *discordgo.InteractionCreate) {
+	if i.GuildID == "" {
+		b.respond(s, i, "🏰 Les sorties s'organisent sur un serveur, pas en message privé !", true)
+		return
+	}
+	data := i.ApplicationCommandData()
+	if len(data.Options) == 0 {
+		return
+	}
+	sub := data.Options[0]
+	opts := optionMap(sub.Options)
+
+	switch sub.Name {
+	case "create":
+		req := runRequest{
+			GuildID:   i.GuildID,
+			ChannelID: i.ChannelID,
+			CreatorID: interactionUser(i).ID,
+			Dungeon:   opts["donjon"].StringValue(),
+			When:      opts["quand"].StringValue(),
+			Slots:     map[lfg.Role]int{lfg.Tank: 1, lfg.Heal: 1, lfg.DPS: 2},
+		}
+		for name, role := range map[string]lfg.Role{"tanks": lfg.Tank, "soins": lfg.Heal, "dps": lfg.DPS} {
+			if o, ok := opts[name]; ok {
+				req.Slots[role] = int(o.IntValue())
+			}
+		}
+		if o, ok := opts["classes"]; ok {
+			req.Classes = o.StringValue()
+		}
+		if o, ok := opts["note"]; ok {
+			req.Note = o.StringValue()
+		}
+
+		e, err := b.createRun(req)
+		if err != nil {
+			b.respond(s, i, "❌ "+err.Error(), true)
+			return
+		}
+		b.respond(s, i, fmt.Sprintf("✅ Sortie **%s** créée (id `%s`) !", e.Dungeon, e.ID), true)
+
+	case "list":
+		b.respond(s, i, b.describeRuns(i.GuildID), true)
+
+	case "cancel":
+		id := strings.TrimSpace(opts["id"].StringValue())
+		e, ok := b.lfg.Get(id)
+		if !ok || e.GuildID != i.GuildID {
+			b.respond(s, i, fmt.Sprintf("🤷 Aucune sortie `%s` sur ce serveur.", id), true)
+			return
+		}
+		if e.CreatorID != interactionUser(i).ID && !isAdmin(i) {
+			b.respond(s, i, "🛡️ Seul l'organisateur (ou un administrateur) peut annuler cette sortie.", true)
+			return
+		}
+		b.cancelRun(e)
+		b.logger.Info("Sortie annulée",
+			slog.String("id", e.ID),
+			slog.String("user", interactionUser(i).Username),
+		)
+		b.respond(s, i, fmt.Sprintf("🗑️ Sortie **%s** annulée.", e.Dungeon), true)
+	}
+}
+
+// createRun crée une sortie, publie son message d'inscription et programme son rappel.
+func (b *Bot) createRun(req runRequest) (lfg.Event, error) {
+	svc := b.services.Load()
+	loc := svc.cfg.Location()
+	now := time.Now().In(loc)
+
+	dungeon := strings.TrimSpace(req.Dungeon)
+	if dungeon == "" {
+		return lfg.Event{}, errors.New("précise le donjon ou le boss visé")
+	}
+	start, err := frtime.Parse(req.When, now, loc)
+	if err != nil {
+		return lfg.Event{}, fmt.Errorf("date non comprise: %w", err)
+	}
+	if !start.After(now) {
+		return lfg.Event{}, fmt.Errorf("le %s est déjà passé", frtime.DateTime(start))
+	}
+	if start.Sub(now) > lfgMaxAhead {
+		return lfg.Event{}, errors.New("impossible de planifier une sortie à plus de 60 jours")
+	}
+
+	for role, n := range req.Slots {
+		if n < 0 || n > lfgMaxSlots {
+			return lfg.Event{}, fmt.Errorf("nombre de places %s invalide (0 à %d)", role.Label(), lfgMaxSlots)
+		}
+	}
+
+	e, err := b.lfg.Create(lfg.Event{
+		GuildID:   req.GuildID,
+		ChannelID: req.ChannelID,
+		CreatorID: req.CreatorID,
+		Dungeon:   dungeon,
+		Start:     start,
+		Slots:     req.Slots,
+		Classes:   strings.TrimSpace(req.Classes),
+		Note:      strings.TrimSpace(req.Note),
+	})
+	if err != nil {
+		return lfg.Event{}, err
+	}
+
+	embed, buttons := lfgMessage(e, loc)
+	msg, err := b.session.ChannelMessageSendComplex(e.ChannelID, &discordgo.MessageSend{
+		Embeds:     []*discordgo.MessageEmbed{embed},
+		Components: buttons,
+	})
+	if err != nil {
+		_ = b.lfg.Delete(e.ID)
+		return lfg.Event{}, fmt.Errorf("impossible de publier la sortie: %w", err)
+	}
+	if err := b.lfg.SetMessage(e.ID, msg.ID); err != nil {
+		b.logger.Error("Message de sortie non enregistré", slog.String("id", e.ID), slog.String("error", err.Error()))
+	}
+	e.MessageID = msg.ID
+
+	b.scheduleRun(e, svc.cfg.LFG.Reminder)
+	b.logger.Info("Sortie créée",
+		slog.String("id", e.ID),
+		slog.String("dungeon", e.Dungeon),
+		slog.Time("start", e.Start),
+		slog.String("creator", e.CreatorID),
+	)
+	return e, nil
+}
+
+// cancelRun supprime une sortie, son rappel et ses boutons d'inscription.
+func (b *Bot) cancelRun(e lfg.Event) {
+	b.scheduler.Cancel(lfgJobPrefix + e.ID)
+	if err := b.lfg.Delete(e.ID); err != nil {
+		b.logger.Error("Suppression de la sortie impossible", slog.String("id", e.ID), slog.String("error", err.Error()))
+	}
+	if e.MessageID == "" {
+		return
+	}
+	content := fmt.Sprintf("~~%s~~ — sortie annulée.", e.Dungeon)
+	empty := []discordgo.MessageComponent{}
+	if _, err := b.session.ChannelMessageEditComplex(&discordgo.MessageEdit{
+		Channel:    e.ChannelID,
+		ID:         e.MessageID,
+		Content:    &content,
+		Embeds:     &[]*discordgo.MessageEmbed{},
+		Components: &empty,
+	}); err != nil {
+		b.logger.Warn("Message de sortie non mis à jour", slog.String("id", e.ID), slog.String("error", err.Error()))
+	}
+}
+
+// handleLFGButton traite les boutons d'inscription ("join:<id>:<rôle>" ou "leave:<id>").
+func (b *Bot) handleLFGButton(s *discordgo.Session, i *discordgo.InteractionCreate, args []string) {
+	if len(args) < 2 {
+		return
+	}
+	action, id := args[0], args[1]
+	user := interactionUser(i)
+
+	var (
+		e   lfg.Event
+		err error
+	)
+	switch action {
+	case "join":
+		if len(args) < 3 {
+			return
+		}
+		var role lfg.Role
+		if role, err = lfg.ParseRole(args[2]); err == nil {
+			e, err = b.lfg.Join(id, user.ID, role, time.Now())
+		}
+	case "leave":
+		e, err = b.lfg.Leave(id, user.ID)
+	default:
+		return
+	}
+
+	switch {
+	case errors.Is(err, lfg.ErrFull):
+		b.respond(s, i, "😬 Plus de place pour ce rôle ! Tente un autre rôle ou croise les doigts pour un désistement.", true)
+		return
+	case errors.Is(err, lfg.ErrStarted):
+		b.respond(s, i, "⌛ Trop tard, le groupe est déjà parti !", true)
+		return
+	case errors.Is(err, lfg.ErrNotFound):
+		b.respond(s, i, "🤷 Cette sortie n'existe plus.", true)
+		return
+	case err != nil:
+		b.logger.Error("Inscription impossible", slog.String("id", id), slog.String("error", err.Error()))
+		b.respond(s, i, "❌ Inscription impossible pour le moment.", true)
+		return
+	}
+
+	// Mise à jour du message d'inscription (accuse aussi réception du clic)
+	embed, buttons := lfgMessage(e, b.services.Load().cfg.Location())
+	if err := s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseUpdateMessage,
+		Data: &discordgo.InteractionResponseData{
+			Embeds:     []*discordgo.MessageEmbed{embed},
+			Components: buttons,
+		},
+	}); err != nil {
+		b.logger.Error("Impossible de mettre à jour la sortie", slog.String("id", id), slog.String("error", err.Error()))
+	}
+}
+
+// scheduleRun programme le rappel d'une sortie (mention des inscrits avant le début).
+func (b *Bot) scheduleRun(e lfg.Event, before time.Duration) {
+	if before <= 0 {
+		return
+	}
+	at := e.Start.Add(-before)
+	if at.Before(time.Now()) {
+		return
+	}
+	id := e.ID
+	b.scheduler.At(lfgJobPrefix+id, at, func(context.Context) {
+		b.remindRun(id)
+	})
+}
+
+// remindRun rappelle la sortie aux inscrits.
+func (b *Bot) remindRun(id string) {
+	e, ok := b.lfg.Get(id)
+	if !ok {
+		return
+	}
+
+	var mentions []string
+	for _, su := range e.Signups {
+		mentions = append(mentions, "<@"+su.UserID+">")
+	}
+	content := fmt.Sprintf("⏰ **%s** commence <t:%d:R> ! ", e.Dungeon, e.Start.Unix())
+	if len(mentions) == 0 {
+		content += "Personne n'est inscrit… il reste de la place pour les courageux !"
+	} else {
+		content += fmt.Sprintf("Préparez vos potions : %s (%d/%d)", strings.Join(mentions, " "), len(e.Signups), e.Size())
+	}
+
+	send := &discordgo.MessageSend{Content: content}
+	if e.MessageID != "" {
+		send.Reference = &discordgo.MessageReference{MessageID: e.MessageID, ChannelID: e.ChannelID, GuildID: e.GuildID}
+	}
+	if _, err := b.session.ChannelMessageSendComplex(e.ChannelID, send); err != nil {
+		b.logger.Error("Rappel de sortie non envoyé", slog.String("id", id), slog.String("error", err.Error()))
+	}
+}
+
+// scheduleRuns (re)programme les rappels de toutes les sorties à venir : après un redémarrage,
+// ou quand lfg.reminder change au rechargement de la configuration.
+func (b *Bot) scheduleRuns(reminder time.Duration) {
+	b.scheduler.CancelPrefix(lfgJobPrefix)
+	for _, e := range b.lfg.Upcoming("", time.Now()) {
+		b.scheduleRun(e, reminder)
+	}
+}
+
+// scheduleRunsPurge (re)programme la purge quotidienne des sorties terminées.
+func (b *Bot) scheduleRunsPurge(svc *services) {
+	if err := b.scheduler.Daily(lfgPurgeJob, "04:15", svc.cfg.Location(), b.purgeRuns); err != nil {
+		b.logger.Error("Purge des anciennes sorties non planifiée", slog.String("error", err.Error()))
+	}
+}
+
+// purgeRuns supprime les sorties commencées depuis plus de lfgKeep.
+func (b *Bot) purgeRuns(context.Context) {
+	if err := b.lfg.Prune(time.Now(), lfgKeep); err != nil {
+		b.logger.Warn("Purge des anciennes sorties impossible", slog.String("error", err.Error()))
+	}
+}
+
+// describeRuns liste les sorties à venir d'un serveur.
+func (b *Bot) describeRuns(guildID string) string {
+	loc := b.services.Load().cfg.Location()
+	runs := b.lfg.Upcoming(guildID, time.Now())
+	if len(runs) == 0 {
+		return "📭 Aucune sortie prévue. Lance-toi avec `/donjon create` !"
+	}
+
+	var sb strings.Builder
+	sb.WriteString("📅 **Sorties à venir**\n")
+	for _, e := range runs {
+		fmt.Fprintf(&sb, "- `%s` **%s** — %s (%d/%d inscrits)\n",
+			e.ID, e.Dungeon, frtime.DateTime(e.Start.In(loc)), len(e.Signups), e.Size())
+	}
+	return sb.String()
+}
+
+// lfgMessage construit l'embed et les boutons d'inscription d'une sortie.
+func lfgMessage(e lfg.Event, loc *time.Location) (*discordgo.MessageEmbed, []discordgo.MessageComponent) {
+	embed := &discordgo.MessageEmbed{
+		Title:       "🏰 " + e.Dungeon,
+		Description: fmt.Sprintf("📅 %s (<t:%d:R>)\nOrganisé par <@%s>", frtime.DateTime(e.Start.In(loc)), e.Start.Unix(), e.CreatorID),
+		Color:       0x8E44AD,
+		Footer:      &discordgo.MessageEmbedFooter{Text: "id " + e.ID + " · Clique sur un rôle pour t'inscrire"},
+	}
+
+	var buttons []discordgo.MessageComponent
+	for _, role := range lfg.Roles {
+		slots := e.Slots[role]
+		if slots == 0 {
+			continue
+		}
+		members := e.Members(role)
+		value := "—"
+		if len(members) > 0 {
+			value = "<@" + strings.Join(members, ">\n<@") + ">"
+		}
+		embed.Fields = append(embed.Fields, &discordgo.MessageEmbedField{
+			Name:   fmt.Sprintf("%s %s (%d/%d)", role.Emoji(), role.Label(), len(members), slots),
+			Value:  value,
+			Inline: true,
+		})
+		buttons = append(buttons, discordgo.Button{
+			Label:    role.Label(),
+			Emoji:    &discordgo.ComponentEmoji{Name: role.Emoji()},
+			Style:    discordgo.PrimaryButton,
+			CustomID: fmt.Sprintf("%s:join:%s:%s", lfgComponent, e.ID, role),
+			Disabled: len(members) >= slots,
+		})
+	}
+	if e.Classes != "" {
+		embed.Fields = append(embed.Fields, &discordgo.MessageEmbedField{Name: "🎯 Classes souhaitées", Value: e.Classes})
+	}
+	if e.Note != "" {
+		embed.Fields = append(embed.Fields, &discordgo.MessageEmbedField{Name: "📝 Note", Value: e.Note})
+	}
+
+	buttons = append(buttons, discordgo.Button{
+		Label:    "Se désinscrire",
+		Style:    discordgo.SecondaryButton,
+		CustomID: fmt.Sprintf("%s:leave:%s", lfgComponent, e.ID),
+	})
+	return embed, []discordgo.MessageComponent{discordgo.ActionsRow{Components: buttons}}
+}
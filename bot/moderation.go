@@ -0,0 +1,195 @@
+package bot
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"otom-ai/config"
+	"otom-ai/moderation"
+	"strings"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+// Réponses thématiques aux contenus refusés par la modération.
+const (
+	refusedInput  = "🙊 Oula, ce message ne passe pas la modération du serveur. Reformule sans ça et je suis tout à toi !"
+	refusedOutput = "🙊 Ma réponse ne passait pas la modération du serveur, je préfère garder ma langue de Bouftou dans ma poche sur ce coup-là."
+)
+
+// moderationHit décrit un contenu signalé par la modération.
+type moderationHit struct {
+	terms      []string // Passages trouvés par les listes
+	categories []string // Catégories signalées par le classifieur
+}
+
+// flagged indique si le contenu est signalé.
+func (h moderationHit) flagged() bool {
+	return len(h.terms) > 0 || len(h.categories) > 0
+}
+
+// reason résume le motif du signalement.
+func (h moderationHit) reason() string {
+	var parts []string
+	if len(h.terms) > 0 {
+		parts = append(parts, "liste : "+strings.Join(h.terms, ", "))
+	}
+	if len(h.categories) > 0 {
+		parts = append(parts, "classifieur : "+strings.Join(h.categories, ", "))
+	}
+	return strings.Join(parts, " ; ")
+}
+
+// setupModeration compile les listes de modération (globales et par serveur) et construit le classifieur.
+func setupModeration(svc *services, logger *slog.Logger) error {
+	cfg := svc.cfg
+	svc.filters = map[string]*moderation.Filter{}
+	global, err := moderation.NewFilter(cfg.Moderation.Words, cfg.Moderation.Patterns)
+	if err != nil {
+		return fmt.Errorf("modération: %w", err)
+	}
+	svc.filters[""] = global
+	for id, g := range cfg.Guilds {
+		if len(g.Moderation.Words) == 0 && len(g.Moderation.Patterns) == 0 {
+			continue
+		}
+		rules := cfg.GuildModeration(id)
+		filter, err := moderation.NewFilter(rules.Words, rules.Patterns)
+		if err != nil {
+			return fmt.Errorf("modération du serveur %s: %w", id, err)
+		}
+		svc.filters[id] = filter
+	}
+
+	switch cl := cfg.Moderation.Classifier; cl.Type {
+	case "llm":
+		svc.classifier = moderation.NewLLM(svc.aiClient)
+	case "endpoint":
+		endpoint := moderation.NewEndpoint(cl.APIKey.Value(), cl.URL, cl.Model)
+		endpoint.SetTimeout(cl.Timeout)
+		endpoint.SetLogger(logger)
+		svc.classifier = endpoint
+	}
+	return nil
+}
+
+// filter retourne les listes de modération d'un serveur (listes globales à défaut).
+func (svc *services) filter(guildID string) *moderation.Filter {
+	if f, ok := svc.filters[guildID]; ok {
+		return f
+	}
+	return svc.filters[""]
+}
+
+// checkContent passe un texte aux listes de modération puis au classifieur.
+// Une panne du classifieur laisse passer le contenu (les listes restent appliquées).
+func (b *Bot) checkContent(svc *services, guildID, text string) moderationHit {
+	hit := moderationHit{terms: svc.filter(guildID).Match(text)}
+	if svc.classifier == nil || strings.TrimSpace(text) == "" {
+		return hit
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), svc.cfg.Moderation.Classifier.Timeout)
+	defer cancel()
+	v, err := svc.classifier.Classify(ctx, text)
+	if err != nil {
+		b.logger.Warn("Classifieur de modération indisponible", slog.String("error", err.Error()))
+		return hit
+	}
+	if v.Flagged {
+		hit.categories = v.Categories
+		if len(hit.categories) == 0 {
+			hit.categories = []string{"non précisée"}
+		}
+	}
+	return hit
+}
+
+// moderateInput applique la modération au message reçu. Elle retourne le texte à transmettre
+// au LLM (masqué si besoin) et false si le message est refusé (avec une réponse thématique
+// si quelqu'un attend une réponse).
+func (b *Bot) moderateInput(s *discordgo.Session, m *discordgo.Message, svc *services, text string, why engagement) (string, bool) {
+	hit := b.checkContent(svc, m.GuildID, text)
+	if !hit.flagged() {
+		return text, true
+	}
+	rules := svc.cfg.GuildModeration(m.GuildID)
+	action := effectiveAction(rules.Input, hit)
+	b.reportModeration(s, m, svc, "Message d'un utilisateur", action, hit, text)
+
+	switch action {
+	case config.ModerationLog:
+		return text, true
+	case config.ModerationRedact:
+		return svc.filter(m.GuildID).Redact(text), true
+	}
+	if why != lurking {
+		b.replyToMessage(s, m, refusedInput)
+	}
+	return "", false
+}
+
+// moderateOutput applique la modération à une réponse du bot. Elle retourne le texte à publier
+// et true si la réponse a été refusée (remplacée par un message thématique).
+func (b *Bot) moderateOutput(s *discordgo.Session, m *discordgo.Message, svc *services, reply string) (string, bool) {
+	hit := b.checkContent(svc, m.GuildID, reply)
+	if !hit.flagged() {
+		return reply, false
+	}
+	rules := svc.cfg.GuildModeration(m.GuildID)
+	action := effectiveAction(rules.Output, hit)
+	b.reportModeration(s, m, svc, "Réponse du bot", action, hit, reply)
+
+	switch action {
+	case config.ModerationLog:
+		return reply, false
+	case config.ModerationRedact:
+		return svc.filter(m.GuildID).Redact(reply), false
+	}
+	return refusedOutput, true
+}
+
+// effectiveAction retourne l'action à appliquer : un contenu signalé par le seul classifieur
+// ne peut pas être masqué, il est refusé.
+func effectiveAction(action string, hit moderationHit) string {
+	if action == config.ModerationRedact && len(hit.terms) == 0 {
+		return config.ModerationRefuse
+	}
+	return action
+}
+
+// reportModeration log un signalement et le publie dans le journal d'audit du serveur, avec les
+// mêmes règles que les suppressions et modifications : le contenu n'est repris qu'avec
+// audit.content, jamais pour un auteur ayant refusé la lecture de ses messages, et rien n'est
+// publié pour un channel exclu du journal.
+func (b *Bot) reportModeration(s *discordgo.Session, m *discordgo.Message, svc *services, subject, action string, hit moderationHit, content string) {
+	b.logger.Warn("Contenu signalé par la modération",
+		slog.String("subject", subject),
+		slog.String("user", m.Author.Username),
+		slog.String("channel", m.ChannelID),
+		slog.String("action", action),
+		slog.String("reason", hit.reason()),
+	)
+	if m.GuildID == "" || b.auditExcluded(s, svc, m.GuildID, m.ChannelID) {
+		return
+	}
+
+	embed := &discordgo.MessageEmbed{
+		Title: "🛡️ Modération : " + subject,
+		Color: auditColorModeration,
+		Fields: []*discordgo.MessageEmbedField{
+			{Name: "Auteur de la question", Value: "<@" + m.Author.ID + ">", Inline: true},
+			{Name: "Channel", Value: "<#" + m.ChannelID + ">", Inline: true},
+			{Name: "Action", Value: action, Inline: true},
+			{Name: "Motif", Value: truncate(hit.reason(), 1000)},
+			{Name: "Message", Value: messageLink(m.GuildID, m.ChannelID, m.ID)},
+		},
+	}
+	// La réponse du bot reprend souvent la question : l'avis de son auteur vaut pour les deux
+	switch {
+	case b.optedOut(m.Author.ID):
+		embed.Description = auditOptedOut
+	case svc.cfg.Audit.Content:
+		embed.Description = auditQuote(content)
+	}
+	b.audit(s, svc, m.GuildID, config.AuditModeration, embed)
+}
@@ -0,0 +1,150 @@
+package bot
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"otom-ai/prices"
+	"strings"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+// pricesCommand définit la commande /prix (relevé et consultation des prix d'HDV).
+func (b *Bot) pricesCommand() command {
+	minPrice := 1.0
+	serverOption := &discordgo.ApplicationCommandOption{
+		Type:        discordgo.ApplicationCommandOptionString,
+		Name:        "serveur",
+		Description: "Serveur de jeu (défaut : serveur de la guilde)",
+	}
+
+	return command{
+		def: &discordgo.ApplicationCommand{
+			Name:        "prix",
+			Description: "Prix des ressources à l'hôtel des ventes",
+			Options: []*discordgo.ApplicationCommandOption{
+				{
+					Type:        discordgo.ApplicationCommandOptionSubCommand,
+					Name:        "ajouter",
+					Description: "Signale un prix relevé à l'HDV",
+					Options: []*discordgo.ApplicationCommandOption{
+						{Type: discordgo.ApplicationCommandOptionString, Name: "objet", Description: "Nom de la ressource ou de l'objet", Required: true},
+						{Type: discordgo.ApplicationCommandOptionInteger, Name: "prix", Description: "Prix du lot en kamas", Required: true, MinValue: &minPrice},
+						{Type: discordgo.ApplicationCommandOptionInteger, Name: "lot", Description: "Taille du lot (défaut : 1)", Choices: []*discordgo.ApplicationCommandOptionChoice{
+							{Name: "x1", Value: 1},
+							{Name: "x10", Value: 10},
+							{Name: "x100", Value: 100},
+						}},
+						serverOption,
+					},
+				},
+				{
+					Type:        discordgo.ApplicationCommandOptionSubCommand,
+					Name:        "voir",
+					Description: "Affiche le prix relevé d'un objet et son coût de fabrication",
+					Options: []*discordgo.ApplicationCommandOption{
+						{Type: discordgo.ApplicationCommandOptionString, Name: "objet", Description: "Nom de la ressource ou de l'objet", Required: true},
+						serverOption,
+					},
+				},
+			},
+		},
+		handler: b.handlePrices,
+	}
+}
+
+// handlePrices traite la commande /prix.
+func (b *Bot) handlePrices(s *discordgo.Session, i *discordgo.InteractionCreate) {
+	data := i.ApplicationCommandData()
+	if len(data.Options) == 0 {
+		return
+	}
+	sub := data.Options[0]
+	opts := optionMap(sub.Options)
+	svc := b.services.Load()
+
+	server := svc.cfg.GameServer(i.GuildID)
+	if o, ok := opts["serveur"]; ok {
+		server = strings.TrimSpace(o.StringValue())
+	}
+	if server == "" {
+		b.respond(s, i, "❓ Précise le serveur de jeu (option `serveur`) : aucun serveur par défaut n'est configuré pour cette guilde.", true)
+		return
+	}
+	item := b.canonicalItem(svc, opts["objet"].StringValue())
+
+	switch sub.Name {
+	case "ajouter":
+		lot := int64(1)
+		if o, ok := opts["lot"]; ok {
+			lot = o.IntValue()
+		}
+		unit := float64(opts["prix"].IntValue()) / float64(lot)
+
+		q, err := b.prices.Submit(server, item, unit, interactionUser(i).ID, time.Now())
+		if errors.Is(err, prices.ErrOutlier) {
+			b.respond(s, i, fmt.Sprintf("🤨 %s à %s kamas l'unité ? Ça sent l'arnaque à la Kamas-tastrophe : les derniers relevés tournent autour de %s kamas. Vérifie la taille du lot !",
+				item, prices.FormatKamas(unit), prices.FormatKamas(q.Unit)), true)
+			return
+		}
+		if err != nil {
+			b.logger.Error("Enregistrement du prix impossible", slog.String("error", err.Error()))
+			b.respond(s, i, "❌ Impossible d'enregistrer ce prix pour le moment.", true)
+			return
+		}
+
+		b.logger.Info("Prix HDV relevé",
+			slog.String("user", interactionUser(i).Username),
+			slog.String("server", server),
+			slog.String("item", item),
+			slog.Float64("unit", unit),
+		)
+		b.respond(s, i, fmt.Sprintf("💰 Merci ! %s sur %s : %s kamas l'unité (médiane de %d relevé(s)).",
+			item, server, prices.FormatKamas(q.Unit), q.Samples), true)
+
+	case "voir":
+		text, err := b.craftCost(svc, i.GuildID, item, 1, server)
+		if err != nil {
+			q, ok := b.prices.Quote(server, item, time.Now())
+			if !ok {
+				b.respond(s, i, fmt.Sprintf("🤷 Aucun prix relevé pour %s sur %s. Sois le premier avec `/prix ajouter` !", item, server), true)
+				return
+			}
+			text = fmt.Sprintf("%s sur %s : %s kamas l'unité (%d relevé(s), dernier le %s)",
+				q.Item, server, prices.FormatKamas(q.Unit), q.Samples, q.Latest.In(svc.cfg.Location()).Format("02/01/2006"))
+		}
+		b.respond(s, i, "```\n"+text+"```", false)
+	}
+}
+
+// craftCost calcule le coût d'un objet (achat, fabrication) pour la commande /prix et l'outil craft_cost.
+// Le serveur par défaut est celui de la guilde.
+func (b *Bot) craftCost(svc *services, guildID, item string, quantity int, server string) (string, error) {
+	if server == "" {
+		server = svc.cfg.GameServer(guildID)
+	}
+	if server == "" {
+		return "", errors.New("serveur de jeu inconnu : demande à l'utilisateur sur quel serveur il joue")
+	}
+
+	now := time.Now().In(svc.cfg.Location())
+	line, err := prices.NewCalculator(svc.items, b.prices).Cost(server, item, quantity, now)
+	if err != nil {
+		return "", err
+	}
+	return line.Describe(server, now), nil
+}
+
+// canonicalItem retourne le nom officiel de l'objet s'il est connu de la base locale.
+func (b *Bot) canonicalItem(svc *services, name string) string {
+	name = strings.TrimSpace(name)
+	if it, ok := svc.items.Item(name); ok {
+		return it.Name
+	}
+	if candidates := svc.items.Search(name, 2); len(candidates) == 1 {
+		return candidates[0].Name
+	}
+	return name
+}
@@ -0,0 +1,255 @@
+package bot
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"otom-ai/conversations"
+	"otom-ai/guildkb"
+	"otom-ai/lfg"
+	"otom-ai/prices"
+	"otom-ai/profiles"
+	"otom-ai/reminders"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+// privacyComponent préfixe le CustomID du bouton de confirmation de /privacy delete.
+const privacyComponent = "privacy"
+
+// privacyCommand définit la commande /privacy (contrôle des données personnelles).
+func (b *Bot) privacyCommand() command {
+	return command{
+		def: &discordgo.ApplicationCommand{
+			Name:        "privacy",
+			Description: "Tes données personnelles : lecture de tes messages, export, suppression",
+			Options: []*discordgo.ApplicationCommandOption{
+				{
+					Type:        discordgo.ApplicationCommandOptionSubCommand,
+					Name:        "optout",
+					Description: "Le bot ne lit plus tes messages pour son contexte (historique, mémoire, avis)",
+				},
+				{
+					Type:        discordgo.ApplicationCommandOptionSubCommand,
+					Name:        "optin",
+					Description: "Le bot peut de nouveau lire tes messages pour son contexte",
+				},
+				{
+					Type:        discordgo.ApplicationCommandOptionSubCommand,
+					Name:        "export",
+					Description: "Reçois en message privé un fichier JSON de tes données conservées",
+				},
+				{
+					Type:        discordgo.ApplicationCommandOptionSubCommand,
+					Name:        "delete",
+					Description: "Supprime tes données conservées (conversations, profils, avis, rappels, prix)",
+				},
+			},
+		},
+		handler: b.handlePrivacy,
+	}
+}
+
+// handlePrivacy traite la commande /privacy. Toutes les réponses sont éphémères.
+func (b *Bot) handlePrivacy(s *discordgo.Session, i *discordgo.InteractionCreate) {
+	data := i.ApplicationCommandData()
+	if len(data.Options) == 0 {
+		return
+	}
+	user := interactionUser(i)
+
+	switch data.Options[0].Name {
+	case "optout":
+		changed, err := b.privacy.OptOut(user.ID, time.Now())
+		if err != nil {
+			b.logger.Error("Refus de lecture non enregistré", slog.String("user_id", user.ID), slog.String("error", err.Error()))
+			b.respond(s, i, "❌ Impossible d'enregistrer ton choix pour le moment.", true)
+			return
+		}
+		if changed {
+			b.messages.forgetUser(user.ID)
+			b.logger.Info("Lecture des messages refusée", slog.String("user_id", user.ID))
+		}
+		b.respond(s, i, "🙈 C'est noté : je ne lis plus tes messages pour mon contexte (historique des channels, "+
+			"mémoire des messages privés, avis). Je réponds toujours quand tu me mentionnes. "+
+			"`/privacy delete` efface ce que j'ai déjà conservé, `/privacy optin` annule ce choix.", true)
+
+	case "optin":
+		if _, err := b.privacy.OptIn(user.ID); err != nil {
+			b.logger.Error("Retrait du refus de lecture impossible", slog.String("user_id", user.ID), slog.String("error", err.Error()))
+			b.respond(s, i, "❌ Impossible d'enregistrer ton choix pour le moment.", true)
+			return
+		}
+		b.respond(s, i, "👀 C'est noté : je peux de nouveau lire tes messages pour suivre les conversations.", true)
+
+	case "export":
+		b.handlePrivacyExport(s, i, user)
+
+	case "delete":
+		err := s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+			Type: discordgo.InteractionResponseChannelMessageWithSource,
+			Data: &discordgo.InteractionResponseData{
+				Content: "⚠️ Supprimer tes conversations en message privé, profils de joueur, avis, rappels en attente " +
+					"et prix relevés ? C'est définitif (pense à `/privacy export` avant).",
+				Flags: discordgo.MessageFlagsEphemeral,
+				Components: []discordgo.MessageComponent{discordgo.ActionsRow{Components: []discordgo.MessageComponent{
+					discordgo.Button{Label: "Tout supprimer", Style: discordgo.DangerButton, CustomID: privacyComponent + ":delete"},
+				}}},
+			},
+		})
+		if err != nil {
+			b.logger.Error("Impossible de répondre à l'interaction", slog.String("channel", i.ChannelID), slog.String("error", err.Error()))
+		}
+	}
+}
+
+// handlePrivacyButton supprime les données de l'utilisateur qui confirme /privacy delete.
+func (b *Bot) handlePrivacyButton(s *discordgo.Session, i *discordgo.InteractionCreate, args []string) {
+	if len(args) < 1 || args[0] != "delete" {
+		return
+	}
+	user := interactionUser(i)
+	content := "🧹 Tes données ont été supprimées et ton nom retiré de l'historique de la fiche du serveur. " +
+		"Les sorties de groupe restent visibles des autres inscrits : quitte-les depuis leurs boutons si besoin."
+	if err := b.forgetUser(user.ID); err != nil {
+		b.logger.Error("Suppression des données personnelles incomplète", slog.String("user_id", user.ID), slog.String("error", err.Error()))
+		content = "❌ Une partie de tes données n'a pas pu être supprimée. Réessaie dans quelques instants."
+	} else {
+		b.logger.Info("Données personnelles supprimées", slog.String("user_id", user.ID))
+	}
+	err := s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseUpdateMessage,
+		Data: &discordgo.InteractionResponseData{Content: content, Components: []discordgo.MessageComponent{}},
+	})
+	if err != nil {
+		b.logger.Error("Impossible de répondre à l'interaction", slog.String("channel", i.ChannelID), slog.String("error", err.Error()))
+	}
+}
+
+// forgetUser efface les données d'un utilisateur de tous les stores, et de la mémoire du bot.
+// Ses modifications de la fiche du serveur sont anonymisées (le contenu appartient au serveur).
+// Le refus de lecture (/privacy optout) est conservé : c'est lui qui protège les données futures.
+func (b *Bot) forgetUser(userID string) error {
+	errs := []error{
+		b.conversations.Forget(userID),
+		b.profiles.ForgetUser(userID),
+		b.feedback.ForgetUser(userID),
+		b.prices.ForgetUser(userID),
+		b.guildKB.ForgetEditor(userID),
+	}
+	for _, r := range b.reminders.ForUser(userID) {
+		b.scheduler.Cancel(reminderJobPrefix + r.ID)
+		if err := b.reminders.Delete(r.ID); err != nil && !errors.Is(err, reminders.ErrNotFound) {
+			errs = append(errs, err)
+		}
+	}
+	b.messages.forgetUser(userID)
+	b.replies.forgetUser(userID)
+	return errors.Join(errs...)
+}
+
+// userExport est le fichier envoyé par /privacy export. Il ne contient pas les données des
+// autres utilisateurs (historique des channels, votes des autres, inscrits des sorties).
+type userExport struct {
+	UserID        string                         `json:"user_id"`
+	ExportedAt    time.Time                      `json:"exported_at"`
+	OptedOutSince *time.Time                     `json:"opted_out_since,omitempty"`
+	Conversations []conversations.Exchange       `json:"conversations"` // Mémoire des messages privés
+	Profiles      map[string]profiles.Profile    `json:"profiles"`      // Par serveur Discord
+	Answers       []exportedAnswer               `json:"answers"`       // Réponses à tes questions conservées pour les avis
+	Votes         map[string]int                 `json:"votes"`         // Tes avis, par message de réponse
+	Reminders     []reminders.Reminder           `json:"reminders"`
+	Prices        map[string][]prices.Submission `json:"prices"` // Par serveur de jeu
+	Dungeons      []exportedRun                  `json:"dungeons"`
+	SheetEdits    map[string][]guildkb.Revision  `json:"sheet_edits"` // Tes modifications de la fiche, par serveur Discord
+}
+
+// exportedAnswer est une réponse conservée pour les avis, sans l'historique du channel.
+type exportedAnswer struct {
+	MessageID string    `json:"message_id"`
+	GuildID   string    `json:"guild_id,omitempty"`
+	ChannelID string    `json:"channel_id"`
+	Question  string    `json:"question"`
+	Reply     string    `json:"reply"`
+	At        time.Time `json:"at"`
+}
+
+// exportedRun est une sortie de groupe créée par l'utilisateur ou à laquelle il est inscrit.
+type exportedRun struct {
+	ID      string    `json:"id"`
+	GuildID string    `json:"guild_id"`
+	Dungeon string    `json:"dungeon"`
+	Start   time.Time `json:"start"`
+	Creator bool      `json:"creator"`
+	Role    lfg.Role  `json:"role,omitempty"` // Rôle d'inscription ("" si non inscrit)
+}
+
+// userData rassemble les données conservées sur un utilisateur.
+func (b *Bot) userData(userID string) userExport {
+	out := userExport{
+		UserID:        userID,
+		ExportedAt:    time.Now(),
+		Conversations: b.conversations.Recent(userID, time.Time{}),
+		Profiles:      b.profiles.ForUser(userID),
+		Votes:         b.feedback.VotesBy(userID),
+		Reminders:     b.reminders.ForUser(userID),
+		Prices:        b.prices.ForUser(userID),
+		SheetEdits:    b.guildKB.ByEditor(userID),
+	}
+	if since, ok := b.privacy.OptedOut(userID); ok {
+		out.OptedOutSince = &since
+	}
+	for _, a := range b.feedback.Authored(userID) {
+		out.Answers = append(out.Answers, exportedAnswer{
+			MessageID: a.MessageID, GuildID: a.GuildID, ChannelID: a.ChannelID,
+			Question: a.Question, Reply: a.Reply, At: a.At,
+		})
+	}
+	for _, e := range b.lfg.ForUser(userID) {
+		run := exportedRun{ID: e.ID, GuildID: e.GuildID, Dungeon: e.Dungeon, Start: e.Start, Creator: e.CreatorID == userID}
+		for _, su := range e.Signups {
+			if su.UserID == userID {
+				run.Role = su.Role
+			}
+		}
+		out.Dungeons = append(out.Dungeons, run)
+	}
+	return out
+}
+
+// handlePrivacyExport envoie à l'utilisateur, en message privé, le fichier JSON de ses données.
+func (b *Bot) handlePrivacyExport(s *discordgo.Session, i *discordgo.InteractionCreate, user *discordgo.User) {
+	data, err := json.MarshalIndent(b.userData(user.ID), "", "  ")
+	if err != nil {
+		b.logger.Error("Export des données personnelles impossible", slog.String("user_id", user.ID), slog.String("error", err.Error()))
+		b.respond(s, i, "❌ Impossible de préparer l'export pour le moment.", true)
+		return
+	}
+	ch, err := s.UserChannelCreate(user.ID)
+	if err == nil {
+		_, err = s.ChannelMessageSendComplex(ch.ID, &discordgo.MessageSend{
+			Content: "📦 Voici les données que je conserve sur toi.",
+			Files: []*discordgo.File{{
+				Name:        fmt.Sprintf("otom-ai-%s.json", user.ID),
+				ContentType: "application/json",
+				Reader:      bytes.NewReader(data),
+			}},
+		})
+	}
+	if err != nil {
+		b.logger.Warn("Export des données personnelles non envoyé", slog.String("user_id", user.ID), slog.String("error", err.Error()))
+		b.respond(s, i, "📪 Impossible de t'envoyer un message privé : ouvre tes messages privés aux membres du serveur et réessaie.", true)
+		return
+	}
+	b.logger.Info("Données personnelles exportées", slog.String("user_id", user.ID))
+	b.respond(s, i, "📬 Export envoyé en message privé !", true)
+}
+
+// optedOut indique si un utilisateur a refusé que le bot lise ses messages (/privacy optout).
+func (b *Bot) optedOut(userID string) bool {
+	_, ok := b.privacy.OptedOut(userID)
+	return ok
+}
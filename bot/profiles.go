@@ -0,0 +1,193 @@
+package bot
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"otom-ai/profiles"
+	"otom-ai/untrusted"
+	"strings"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+// maxListedProfiles borne le nombre de profils listés au LLM (outil get_player_profile sans joueur).
+const maxListedProfiles = 40
+
+// profileCommand définit la commande /perso (personnages des joueurs).
+func (b *Bot) profileCommand() command {
+	minLevel, maxLevel := 1.0, float64(profiles.MaxLevel)
+	classChoices := make([]*discordgo.ApplicationCommandOptionChoice, len(profiles.Classes))
+	for i, c := range profiles.Classes {
+		classChoices[i] = &discordgo.ApplicationCommandOptionChoice{Name: c, Value: c}
+	}
+
+	return command{
+		def: &discordgo.ApplicationCommand{
+			Name:        "perso",
+			Description: "Tes personnages Dofus, pour des conseils adaptés",
+			Options: []*discordgo.ApplicationCommandOption{
+				{
+					Type:        discordgo.ApplicationCommandOptionSubCommand,
+					Name:        "ajouter",
+					Description: "Enregistre ou met à jour un personnage",
+					Options: []*discordgo.ApplicationCommandOption{
+						{Type: discordgo.ApplicationCommandOptionString, Name: "nom", Description: "Nom du personnage", Required: true},
+						{Type: discordgo.ApplicationCommandOptionString, Name: "classe", Description: "Classe", Required: true, Choices: classChoices},
+						{Type: discordgo.ApplicationCommandOptionInteger, Name: "niveau", Description: "Niveau", Required: true, MinValue: &minLevel, MaxValue: maxLevel},
+						{Type: discordgo.ApplicationCommandOptionString, Name: "serveur", Description: "Serveur de jeu (défaut : serveur de la guilde)"},
+						{Type: discordgo.ApplicationCommandOptionString, Name: "build", Description: "Lien vers le build (DofusBook...)"},
+					},
+				},
+				{
+					Type:        discordgo.ApplicationCommandOptionSubCommand,
+					Name:        "retirer",
+					Description: "Supprime un personnage",
+					Options: []*discordgo.ApplicationCommandOption{
+						{Type: discordgo.ApplicationCommandOptionString, Name: "nom", Description: "Nom du personnage", Required: true},
+					},
+				},
+				{
+					Type:        discordgo.ApplicationCommandOptionSubCommand,
+					Name:        "voir",
+					Description: "Affiche les personnages d'un joueur",
+					Options: []*discordgo.ApplicationCommandOption{
+						{Type: discordgo.ApplicationCommandOptionUser, Name: "joueur", Description: "Joueur (défaut : toi)"},
+					},
+				},
+			},
+		},
+		handler: b.handleProfile,
+	}
+}
+
+// handleProfile traite la commande /perso.
+func (b *Bot) handleProfile(s *discordgo.Session, i *discordgo.InteractionCreate) {
+	if i.GuildID == "" {
+		b.respond(s, i, "🧙 Les personnages s'enregistrent sur un serveur, pas en message privé !", true)
+		return
+	}
+	data := i.ApplicationCommandData()
+	if len(data.Options) == 0 {
+		return
+	}
+	sub := data.Options[0]
+	opts := optionMap(sub.Options)
+	user := interactionUser(i)
+
+	switch sub.Name {
+	case "ajouter":
+		c := profiles.Character{
+			Name:   opts["nom"].StringValue(),
+			Class:  opts["classe"].StringValue(),
+			Level:  int(opts["niveau"].IntValue()),
+			Server: b.services.Load().cfg.GameServer(i.GuildID),
+		}
+		if o, ok := opts["serveur"]; ok {
+			c.Server = o.StringValue()
+		}
+		if o, ok := opts["build"]; ok {
+			c.Build = o.StringValue()
+		}
+
+		p, err := b.profiles.Set(i.GuildID, user.ID, user.Username, c, time.Now())
+		if err != nil {
+			b.logger.Warn("Personnage refusé", slog.String("user", user.Username), slog.String("error", err.Error()))
+			b.respond(s, i, "❌ "+err.Error(), true)
+			return
+		}
+		b.logger.Info("Personnage enregistré",
+			slog.String("user", user.Username),
+			slog.String("character", c.Name),
+		)
+		b.respond(s, i, "✅ C'est noté ! Je tiendrai compte de tes personnages dans mes conseils.\n"+describeProfile(p), true)
+
+	case "retirer":
+		name := opts["nom"].StringValue()
+		err := b.profiles.Remove(i.GuildID, user.ID, name)
+		switch {
+		case errors.Is(err, profiles.ErrNotFound):
+			b.respond(s, i, fmt.Sprintf("🤷 Aucun personnage « %s » dans ton profil.", name), true)
+		case err != nil:
+			b.logger.Error("Suppression du personnage impossible", slog.String("error", err.Error()))
+			b.respond(s, i, "❌ Impossible de supprimer ce personnage pour le moment.", true)
+		default:
+			b.respond(s, i, fmt.Sprintf("🗑️ %s a été retiré de ton profil.", name), true)
+		}
+
+	case "voir":
+		target := user
+		if o, ok := opts["joueur"]; ok {
+			target = o.UserValue(s)
+		}
+		p, ok := b.profiles.Get(i.GuildID, target.ID)
+		if !ok {
+			b.respond(s, i, fmt.Sprintf("🤷 %s n'a enregistré aucun personnage (`/perso ajouter`).", target.Username), true)
+			return
+		}
+		b.respond(s, i, describeProfile(p), true)
+	}
+}
+
+// playerProfile répond à l'outil get_player_profile : un joueur (pseudo, mention ou
+// nom de personnage) ou, sans joueur, tous les profils du serveur. Les profils, saisis
+// par les joueurs, sont transmis dans un bloc de données non fiables.
+func (b *Bot) playerProfile(guildID, player string) string {
+	player = strings.TrimSpace(player)
+	if player == "" {
+		all := b.profiles.Guild(guildID)
+		if len(all) == 0 {
+			return "AUCUN PROFIL : aucun joueur n'a enregistré de personnage (commande /perso ajouter)."
+		}
+		var sb strings.Builder
+		for n, p := range all {
+			if n == maxListedProfiles {
+				fmt.Fprintf(&sb, "... et %d autres joueurs\n", len(all)-n)
+				break
+			}
+			sb.WriteString(p.Summary() + "\n")
+		}
+		return untrusted.Wrap("profils des joueurs", sb.String())
+	}
+
+	if id := strings.Trim(player, "<@!>"); id != player {
+		if p, ok := b.profiles.Get(guildID, id); ok {
+			return untrusted.Wrap("profil de joueur", p.Summary())
+		}
+	} else if p, ok := b.profiles.Find(guildID, player); ok {
+		return untrusted.Wrap("profil de joueur", p.Summary())
+	}
+	return fmt.Sprintf("AUCUN PROFIL pour %q : ce joueur n'a pas enregistré ses personnages (commande /perso ajouter). N'invente pas sa classe ni son niveau.", player)
+}
+
+// profileContext présente au LLM les personnages de l'auteur du message ("" s'il n'en a pas).
+// Saisis par le joueur, ils sont balisés comme non fiables et injectés en message utilisateur,
+// jamais en message système.
+func (b *Bot) profileContext(guildID, userID string) string {
+	if guildID == "" {
+		return ""
+	}
+	p, ok := b.profiles.Get(guildID, userID)
+	if !ok {
+		return ""
+	}
+	return "Personnages de l'utilisateur qui te parle (adapte tes conseils à sa classe et à son niveau) :\n" + untrusted.Wrap("profil de joueur", p.Summary())
+}
+
+// describeProfile formate un profil pour Discord.
+func describeProfile(p profiles.Profile) string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "🧙 **Personnages de %s**\n", p.Username)
+	for _, c := range p.Characters {
+		fmt.Fprintf(&sb, "- **%s** : %s niveau %d", c.Name, c.Class, c.Level)
+		if c.Server != "" {
+			fmt.Fprintf(&sb, " (%s)", c.Server)
+		}
+		if c.Build != "" {
+			fmt.Fprintf(&sb, " — [build](<%s>)", c.Build)
+		}
+		sb.WriteString("\n")
+	}
+	return sb.String()
+}
@@ -25,6 +25,16 @@ func NewRateLimiter(limit int, window time.Duration) *RateLimiter {
 	}
 }
 
+// SetLimits modifie les paramètres du rate limiter (rechargement de la configuration).
+// Les requêtes déjà enregistrées sont conservées.
+func (rl *RateLimiter) SetLimits(limit int, window time.Duration) {
+	rl.mu.Lock()
+	defer rl.mu.Unlock()
+
+	rl.limit = limit
+	rl.window = window
+}
+
 // Allow vérifie si l'utilisateur peut effectuer une requête.
 // Retourne (true, 0) si autorisé, ou (false, retryAfter) si limité.
 func (rl *RateLimiter) Allow(userID string) (bool, time.Duration) {
@@ -0,0 +1,222 @@
+package bot
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"otom-ai/ai"
+	"otom-ai/frtime"
+	"otom-ai/reminders"
+	"strings"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+const (
+	// reminderJobPrefix préfixe les tâches planifiées des rappels.
+	reminderJobPrefix = "reminder:"
+	// reminderMaxAhead borne la date d'un rappel.
+	reminderMaxAhead = 365 * 24 * time.Hour
+	// reminderRetry est le délai avant une nouvelle tentative d'envoi d'un rappel.
+	reminderRetry = 10 * time.Minute
+	// reminderGiveUp est le retard au-delà duquel un rappel impossible à envoyer est abandonné.
+	reminderGiveUp = 24 * time.Hour
+)
+
+// remindersCommand définit la commande /rappels (consultation et annulation des rappels).
+func (b *Bot) remindersCommand() command {
+	return command{
+		def: &discordgo.ApplicationCommand{
+			Name:        "rappels",
+			Description: "Tes rappels programmés (demande-les en mentionnant le bot)",
+			Options: []*discordgo.ApplicationCommandOption{
+				{
+					Type:        discordgo.ApplicationCommandOptionSubCommand,
+					Name:        "liste",
+					Description: "Liste tes rappels en attente",
+				},
+				{
+					Type:        discordgo.ApplicationCommandOptionSubCommand,
+					Name:        "annuler",
+					Description: "Annule un rappel",
+					Options: []*discordgo.ApplicationCommandOption{
+						{Type: discordgo.ApplicationCommandOptionString, Name: "id", Description: "Identifiant du rappel (voir /rappels liste)", Required: true},
+					},
+				},
+			},
+		},
+		handler: b.handleReminders,
+	}
+}
+
+// handleReminders traite la commande /rappels.
+func (b *Bot) handleReminders(s *discordgo.Session, i *discordgo.InteractionCreate) {
+	data := i.ApplicationCommandData()
+	if len(data.Options) == 0 {
+		return
+	}
+	sub := data.Options[0]
+	user := interactionUser(i)
+
+	switch sub.Name {
+	case "liste":
+		loc := b.services.Load().cfg.Location()
+		pending := b.reminders.ForUser(user.ID)
+		if len(pending) == 0 {
+			b.respond(s, i, "📭 Aucun rappel en attente. Demande-moi par exemple : « rappelle-moi samedi 20h de faire mon donjon ».", true)
+			return
+		}
+		var sb strings.Builder
+		sb.WriteString("⏰ **Tes rappels**\n")
+		for _, r := range pending {
+			fmt.Fprintf(&sb, "- `%s` %s : %s\n", r.ID, frtime.DateTime(r.At.In(loc)), truncate(r.Text, 100))
+		}
+		b.respond(s, i, sb.String(), true)
+
+	case "annuler":
+		id := strings.TrimSpace(optionMap(sub.Options)["id"].StringValue())
+		r, ok := b.reminders.Get(id)
+		if !ok || r.UserID != user.ID {
+			b.respond(s, i, fmt.Sprintf("🤷 Aucun rappel `%s` à ton nom.", id), true)
+			return
+		}
+		b.scheduler.Cancel(reminderJobPrefix + id)
+		if err := b.reminders.Delete(id); err != nil && !errors.Is(err, reminders.ErrNotFound) {
+			b.logger.Error("Annulation du rappel impossible", slog.String("id", id), slog.String("error", err.Error()))
+			b.respond(s, i, "❌ Impossible d'annuler ce rappel pour le moment.", true)
+			return
+		}
+		b.respond(s, i, fmt.Sprintf("🗑️ Rappel annulé : %s", truncate(r.Text, 100)), true)
+	}
+}
+
+// createReminder enregistre et programme un rappel demandé dans un message (outil set_reminder).
+// Prévenir tout le salon demande à l'auteur le droit de mentionner @here dans ce salon.
+func (b *Bot) createReminder(m *discordgo.Message, args ai.ReminderArgs) (reminders.Reminder, error) {
+	loc := b.services.Load().cfg.Location()
+	now := time.Now().In(loc)
+
+	dm := args.DM || m.GuildID == ""
+	channel := args.Channel && !dm
+	if channel {
+		perms, err := b.session.State.MessagePermissions(m)
+		if err != nil || perms&discordgo.PermissionMentionEveryone == 0 {
+			return reminders.Reminder{}, errors.New("seuls les membres autorisés à mentionner @here dans ce salon peuvent programmer un rappel pour tout le salon")
+		}
+	}
+
+	at, err := frtime.Parse(args.When, now, loc)
+	if err != nil {
+		return reminders.Reminder{}, fmt.Errorf("date non comprise: %w", err)
+	}
+	if !at.After(now) {
+		return reminders.Reminder{}, fmt.Errorf("le %s est déjà passé", frtime.DateTime(at))
+	}
+	if at.Sub(now) > reminderMaxAhead {
+		return reminders.Reminder{}, errors.New("impossible de programmer un rappel à plus d'un an")
+	}
+
+	r, err := b.reminders.Add(reminders.Reminder{
+		GuildID:   m.GuildID,
+		ChannelID: m.ChannelID,
+		UserID:    m.Author.ID,
+		Text:      args.Text,
+		At:        at,
+		DM:        dm,
+		Channel:   channel,
+		CreatedAt: now,
+	})
+	if err != nil {
+		return reminders.Reminder{}, err
+	}
+	b.scheduleReminder(r)
+	b.logger.Info("Rappel programmé",
+		slog.String("id", r.ID),
+		slog.String("user", m.Author.Username),
+		slog.Time("at", r.At),
+	)
+	return r, nil
+}
+
+// scheduleReminder programme l'envoi d'un rappel (immédiat s'il est déjà dû).
+func (b *Bot) scheduleReminder(r reminders.Reminder) {
+	id := r.ID
+	b.scheduler.At(reminderJobPrefix+id, r.At, func(context.Context) {
+		b.sendReminder(id)
+	})
+}
+
+// sendReminder envoie un rappel puis le supprime. Un rappel en salon qui ne peut pas être
+// publié (salon supprimé, droits retirés) est envoyé en message privé. Un rappel qui n'a pu
+// être envoyé nulle part est retenté plus tard, puis abandonné après reminderGiveUp.
+func (b *Bot) sendReminder(id string) {
+	r, ok := b.reminders.Get(id)
+	if !ok {
+		return
+	}
+
+	if err := b.deliverReminder(r); err != nil {
+		if time.Since(r.At) < reminderGiveUp {
+			b.logger.Warn("Rappel non envoyé, nouvelle tentative prévue",
+				slog.String("id", id),
+				slog.String("user_id", r.UserID),
+				slog.Duration("retry_in", reminderRetry),
+				slog.String("error", err.Error()),
+			)
+			b.scheduler.At(reminderJobPrefix+id, time.Now().Add(reminderRetry), func(context.Context) {
+				b.sendReminder(id)
+			})
+			return
+		}
+		b.logger.Error("Rappel abandonné après plusieurs échecs", slog.String("id", id), slog.String("user_id", r.UserID), slog.String("error", err.Error()))
+	}
+
+	if err := b.reminders.Delete(id); err != nil && !errors.Is(err, reminders.ErrNotFound) {
+		b.logger.Error("Suppression du rappel impossible", slog.String("id", id), slog.String("error", err.Error()))
+	}
+}
+
+// deliverReminder publie un rappel dans son salon, ou à défaut en message privé.
+func (b *Bot) deliverReminder(r reminders.Reminder) error {
+	text := "⏰ Rappel : " + r.Text
+	if late := time.Since(r.At); late > time.Minute {
+		text += fmt.Sprintf("\n-# Prévu le %s, envoyé en retard (redémarrage du bot ou échec d'envoi).", frtime.DateTime(r.At.In(b.services.Load().cfg.Location())))
+	}
+
+	if !r.DM {
+		send := &discordgo.MessageSend{
+			Content:         "<@" + r.UserID + "> " + text,
+			AllowedMentions: &discordgo.MessageAllowedMentions{Users: []string{r.UserID}},
+		}
+		if r.Channel {
+			send.Content = "@here " + text + fmt.Sprintf("\n-# Programmé par <@%s>.", r.UserID)
+			send.AllowedMentions = &discordgo.MessageAllowedMentions{Parse: []discordgo.AllowedMentionType{discordgo.AllowedMentionTypeEveryone}}
+		}
+		_, err := b.session.ChannelMessageSendComplex(r.ChannelID, send)
+		if err == nil {
+			return nil
+		}
+		b.logger.Warn("Rappel non publié dans le salon, envoi en message privé",
+			slog.String("id", r.ID),
+			slog.String("channel", r.ChannelID),
+			slog.String("error", err.Error()),
+		)
+	}
+
+	ch, err := b.session.UserChannelCreate(r.UserID)
+	if err != nil {
+		return err
+	}
+	_, err = b.session.ChannelMessageSend(ch.ID, text)
+	return err
+}
+
+// restoreReminders reprogramme les rappels en attente après un redémarrage
+// (ceux échus pendant l'arrêt partent immédiatement).
+func (b *Bot) restoreReminders() {
+	for _, r := range b.reminders.All() {
+		b.scheduleReminder(r)
+	}
+}
@@ -0,0 +1,235 @@
+package bot
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"otom-ai/ai"
+	"otom-ai/cassette"
+	"otom-ai/conversations"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+const (
+	// replyComponent préfixe le CustomID des boutons des réponses.
+	replyComponent = "reply"
+	// replyContextTTL borne la durée pendant laquelle une réponse peut être retravaillée.
+	replyContextTTL = time.Hour
+	// replyContextMax borne le nombre de réponses dont le contexte est conservé.
+	replyContextMax = 200
+)
+
+// replyAction est une variante demandée sur une réponse publiée.
+type replyAction struct {
+	id          string
+	label       string
+	emoji       string
+	instruction string // Consigne ajoutée au contexte d'origine
+}
+
+// replyActions liste les boutons des réponses, dans l'ordre d'affichage.
+var replyActions = []replyAction{
+	{
+		id: "regen", label: "Régénérer", emoji: "🔄",
+		instruction: "L'utilisateur n'est pas satisfait de ta réponse précédente. Propose une autre réponse à son dernier message, différente sur le fond ou la forme.",
+	},
+	{
+		id: "short", label: "Plus court", emoji: "✂️",
+		instruction: "Reformule ta réponse précédente en beaucoup plus court : deux ou trois phrases maximum, l'essentiel seulement.",
+	},
+	{
+		id: "detail", label: "Plus de détails", emoji: "📖",
+		instruction: "Développe ta réponse précédente : plus de détails, d'exemples et d'étapes concrètes, en restant lisible sur Discord.",
+	},
+	{
+		id: "web", label: "Chercher sur le web", emoji: "🌐",
+		instruction: "Vérifie et complète ta réponse précédente avec une recherche internet (outil search_internet) avant de répondre, en t'appuyant sur les résultats.",
+	},
+}
+
+// replyButtons construit la rangée de boutons d'une réponse.
+func replyButtons() []discordgo.MessageComponent {
+	buttons := make([]discordgo.MessageComponent, 0, len(replyActions))
+	for _, a := range replyActions {
+		buttons = append(buttons, discordgo.Button{
+			Label:    a.label,
+			Emoji:    &discordgo.ComponentEmoji{Name: a.emoji},
+			Style:    discordgo.SecondaryButton,
+			CustomID: replyComponent + ":" + a.id,
+		})
+	}
+	return []discordgo.MessageComponent{discordgo.ActionsRow{Components: buttons}}
+}
+
+// replyContext conserve de quoi rejouer la complétion d'une réponse publiée.
+type replyContext struct {
+	request  *discordgo.Message   // Message auquel le bot a répondu
+	question string               // Question nettoyée de la mention du bot
+	turns    []conversations.Turn // Historique transmis au LLM, au format des avis
+	messages []ai.Message         // Contexte complet transmis au LLM, sans la réponse
+	reply    string               // Réponse actuellement publiée
+	at       time.Time
+}
+
+// replyCache conserve en mémoire le contexte des dernières réponses, par ID de message.
+type replyCache struct {
+	mu      sync.Mutex
+	entries map[string]replyContext
+}
+
+// put mémorise le contexte d'une réponse, en oubliant les plus anciennes.
+func (c *replyCache) put(messageID string, rc replyContext, now time.Time) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.entries == nil {
+		c.entries = map[string]replyContext{}
+	}
+	rc.at = now
+	c.entries[messageID] = rc
+
+	var oldestID string
+	for id, e := range c.entries {
+		if now.Sub(e.at) > replyContextTTL {
+			delete(c.entries, id)
+		} else if oldestID == "" || e.at.Before(c.entries[oldestID].at) {
+			oldestID = id
+		}
+	}
+	if len(c.entries) > replyContextMax {
+		delete(c.entries, oldestID)
+	}
+}
+
+// get retourne le contexte d'une réponse s'il est encore disponible.
+func (c *replyCache) get(messageID string, now time.Time) (replyContext, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	rc, ok := c.entries[messageID]
+	if !ok || now.Sub(rc.at) > replyContextTTL {
+		return replyContext{}, false
+	}
+	return rc, true
+}
+
+// forgetUser oublie les réponses aux questions d'un utilisateur.
+func (c *replyCache) forgetUser(userID string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for id, rc := range c.entries {
+		if rc.request.Author.ID == userID {
+			delete(c.entries, id)
+		}
+	}
+}
+
+// handleReplyButton rejoue la complétion d'une réponse avec la consigne du bouton et
+// remplace la réponse sur place. Réservé à l'auteur de la question, soumis à son rate limit.
+func (b *Bot) handleReplyButton(s *discordgo.Session, i *discordgo.InteractionCreate, args []string) {
+	if len(args) < 1 {
+		return
+	}
+	idx := slices.IndexFunc(replyActions, func(a replyAction) bool { return a.id == args[0] })
+	if idx < 0 {
+		return
+	}
+	action := replyActions[idx]
+	user := interactionUser(i)
+
+	rc, ok := b.replies.get(i.Message.ID, time.Now())
+	if !ok {
+		b.respond(s, i, "⌛ Cette réponse est trop ancienne pour être retravaillée. Mentionne-moi à nouveau !", true)
+		return
+	}
+	if rc.request.Author.ID != user.ID {
+		b.respond(s, i, fmt.Sprintf("🙅 Seul %s peut retravailler cette réponse. Pose ta propre question en me mentionnant !", rc.request.Author.Username), true)
+		return
+	}
+
+	// Une variante compte comme un nouveau message (rate limit, et quota en message privé)
+	limiters := []*RateLimiter{b.rateLimiter}
+	if rc.request.GuildID == "" {
+		limiters = []*RateLimiter{b.dmLimiter, b.dmQuota}
+	}
+	for _, l := range limiters {
+		if allowed, retryAfter := l.Allow(user.ID); !allowed {
+			b.auditRateLimit(s, b.services.Load(), rc.request.GuildID, i.ChannelID, user)
+			b.respond(s, i, fmt.Sprintf("⏳ Doucement ! Attends encore %s avant de me relancer.", retryAfter.Round(time.Second)), true)
+			return
+		}
+	}
+
+	if err := s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseDeferredMessageUpdate,
+	}); err != nil {
+		b.logger.Error("Impossible d'accuser réception de l'interaction",
+			slog.String("channel", i.ChannelID),
+			slog.String("error", err.Error()),
+		)
+		return
+	}
+	_ = s.ChannelTyping(i.ChannelID)
+
+	svc := b.services.Load()
+	messages := append(slices.Clone(rc.messages),
+		ai.Message{Role: "assistant", Content: rc.reply},
+		ai.Message{Role: "system", Content: action.instruction},
+	)
+	// Les outils qui agissent ont déjà agi lors de la réponse d'origine
+	tools := withoutActions(b.tools(svc, rc.request))
+
+	ctx, cancel := context.WithTimeout(context.Background(), svc.cfg.AI.Timeout)
+	defer cancel()
+	ctx = cassette.WithName(ctx, i.ChannelID+"-"+i.Message.ID+"-"+action.id)
+
+	result, err := svc.aiClient.Complete(ctx, messages, tools)
+	if err != nil {
+		b.followUp(s, i, b.aiErrorMessage(err))
+		return
+	}
+	b.logToolUses(user.Username, result)
+
+	reply, _ := b.moderateOutput(s, rc.request, svc, result.Reply)
+	reply = truncate(reply, 2000)
+	edited, err := s.ChannelMessageEdit(i.ChannelID, i.Message.ID, reply)
+	if err != nil {
+		b.logger.Error("Impossible de modifier la réponse",
+			slog.String("channel", i.ChannelID),
+			slog.String("error", err.Error()),
+		)
+		b.followUp(s, i, "❌ Impossible de modifier la réponse pour le moment.")
+		return
+	}
+	b.logger.Info("Réponse retravaillée",
+		slog.String("user", user.Username),
+		slog.String("action", action.id),
+		slog.String("channel", i.ChannelID),
+	)
+
+	rc.reply = reply
+	b.replies.put(edited.ID, rc, time.Now())
+
+	// Les avis portaient sur l'ancienne réponse : ils repartent de zéro
+	if svc.cfg.Feedback.Enabled {
+		if err := s.MessageReactionsRemoveAll(edited.ChannelID, edited.ID); err != nil {
+			b.logger.Warn("Impossible de retirer les réactions d'avis", slog.String("error", err.Error()))
+		}
+		b.recordAnswer(s, edited, rc.request, svc, rc.question, rc.turns, result)
+	}
+}
+
+// followUp envoie un message éphémère après une interaction déjà acquittée.
+func (b *Bot) followUp(s *discordgo.Session, i *discordgo.InteractionCreate, content string) {
+	if _, err := s.FollowupMessageCreate(i.Interaction, true, &discordgo.WebhookParams{
+		Content: truncate(content, 2000),
+		Flags:   discordgo.MessageFlagsEphemeral,
+	}); err != nil {
+		b.logger.Error("Impossible de répondre à l'interaction",
+			slog.String("channel", i.ChannelID),
+			slog.String("error", err.Error()),
+		)
+	}
+}
@@ -0,0 +1,135 @@
+package bot
+
+import (
+	"fmt"
+	"log/slog"
+	"otom-ai/ai"
+	"otom-ai/config"
+	"otom-ai/fetch"
+	"otom-ai/frtime"
+	"otom-ai/lfg"
+	"otom-ai/logging"
+	"otom-ai/prices"
+	"otom-ai/profiles"
+	"slices"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+// actionTools liste les outils qui agissent (rappel programmé, sortie publiée) plutôt que de
+// simplement renseigner le LLM.
+var actionTools = []string{"set_reminder", "create_dungeon_run"}
+
+// withoutActions retire les outils qui agissent : pour une complétion rejouée (ils ont déjà agi)
+// ou une intervention spontanée (personne n'a rien demandé au bot).
+func withoutActions(tools []ai.Tool) []ai.Tool {
+	return slices.DeleteFunc(tools, func(t ai.Tool) bool {
+		return slices.Contains(actionTools, t.Def.Function.Name)
+	})
+}
+
+// tools retourne les outils mis à disposition du LLM pour répondre au message m.
+// Les sources locales passent en premier : la recherche web n'est qu'un recours.
+// Les profils de joueurs et l'organisation de sorties ne sont proposés que sur un serveur (pas en message privé).
+func (b *Bot) tools(svc *services, m *discordgo.Message) []ai.Tool {
+	var tools []ai.Tool
+	if svc.items.HasStats() {
+		tools = append(tools, ai.LookupItemTool(svc.items.Lookup))
+	}
+	tools = append(tools,
+		ai.CraftCostTool(func(item string, quantity int, server string) (string, error) {
+			text, err := b.craftCost(svc, m.GuildID, item, quantity, server)
+			if err != nil {
+				return "", err
+			}
+			return "SOURCE: prix HDV relevés par les joueurs de la guilde\n" + text, nil
+		}),
+	)
+	if svc.almanax.Len() > 0 {
+		tools = append(tools, ai.AlmanaxTool(svc.almanax.Describe, svc.cfg.Location()))
+	}
+	tools = append(tools, calculatorTools(svc.xp)...)
+	tools = append(tools, ai.ReminderTool(func(args ai.ReminderArgs) (string, error) {
+		r, err := b.createReminder(m, args)
+		if err != nil {
+			return "", err
+		}
+		return fmt.Sprintf("Rappel programmé (id %s) pour le %s. L'utilisateur peut le retrouver ou l'annuler avec /rappels.",
+			r.ID, frtime.DateTime(r.At.In(svc.cfg.Location()))), nil
+	}))
+	if m.GuildID != "" {
+		tools = append(tools, ai.PlayerProfileTool(func(player string) (string, error) {
+			return b.playerProfile(m.GuildID, player), nil
+		}))
+		tools = append(tools, ai.DungeonRunTool(func(args ai.DungeonRunArgs) (string, error) {
+			e, err := b.createRun(runRequest{
+				GuildID:   m.GuildID,
+				ChannelID: m.ChannelID,
+				CreatorID: m.Author.ID,
+				Dungeon:   args.Dungeon,
+				When:      args.Start,
+				Slots:     map[lfg.Role]int{lfg.Tank: args.Tanks, lfg.Heal: args.Heals, lfg.DPS: args.DPS},
+				Classes:   args.Classes,
+				Note:      args.Note,
+			})
+			if err != nil {
+				return "", err
+			}
+			return fmt.Sprintf("Sortie créée (id %s) : %s le %s, %d places. Le message d'inscription est déjà publié dans le salon, ne le répète pas.",
+				e.ID, e.Dungeon, frtime.DateTime(e.Start.In(svc.cfg.Location())), e.Size()), nil
+		}))
+	}
+	if svc.knowledge != nil {
+		tools = append(tools, ai.KnowledgeBaseTool(svc.knowledge.Search))
+	}
+	tools = append(tools, ai.SearchTool(svc.searchClient.Search))
+	if svc.fetchClient != nil {
+		tools = append(tools, ai.FetchURLTool(svc.fetchClient.Fetch))
+	}
+	return tools
+}
+
+// offlineGuildID est le serveur fictif des questions posées hors de Discord : aucun réglage ni
+// profil de joueur ne lui correspond.
+const offlineGuildID = "offline"
+
+// Tools retourne les outils proposés au LLM hors de Discord (commande eval) : ceux d'une question
+// posée sur un serveur, sans les outils qui agissent. Les prix relevés et les profils sont lus
+// dans le dossier de données ; les cassettes ne sont pas utilisées.
+func Tools(cfg *config.Config, logger *slog.Logger) ([]ai.Tool, error) {
+	priceStore, err := prices.Open(cfg.DataFile("prices.json"))
+	if err != nil {
+		return nil, fmt.Errorf("base des prix HDV: %w", err)
+	}
+	profileStore, err := profiles.Open(cfg.DataFile("profiles.json"))
+	if err != nil {
+		return nil, fmt.Errorf("base des profils de joueurs: %w", err)
+	}
+	b := &Bot{
+		logger:     logging.For(logger, "bot"),
+		rootLogger: logger,
+		prices:     priceStore,
+		profiles:   profileStore,
+	}
+
+	offline := *cfg
+	offline.Cassette = config.CassetteConfig{}
+	svc, err := b.newServices(&offline)
+	if err != nil {
+		return nil, err
+	}
+	m := &discordgo.Message{GuildID: offlineGuildID, Author: &discordgo.User{}}
+	return withoutActions(b.tools(svc, m)), nil
+}
+
+// newFetchClient construit le client de lecture de pages web à partir de la configuration.
+func newFetchClient(cfg config.FetchConfig) *fetch.Client {
+	client := fetch.NewClient(fetch.Options{
+		AllowedDomains: cfg.AllowedDomains,
+		MaxBytes:       cfg.MaxKB * 1024,
+		MaxChars:       cfg.MaxChars(),
+		CacheTTL:       cfg.CacheTTL,
+	})
+	client.SetTimeout(cfg.Timeout)
+	return client
+}
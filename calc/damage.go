@@ -0,0 +1,99 @@
+// Package calc regroupe les calculateurs déterministes mis à disposition du LLM (dégâts,
+// points de caractéristiques, expérience) : le modèle leur délègue l'arithmétique au lieu
+// de deviner.
+package calc
+
+import (
+	"fmt"
+	"strings"
+)
+
+// DamageInput décrit un coup : ligne de dégâts du sort, caractéristiques du lanceur et
+// résistances de la cible dans l'élément du sort.
+type DamageInput struct {
+	BaseMin, BaseMax int // Dégâts de base du sort (ou de l'arme)
+	CritMin, CritMax int // Dégâts de base en coup critique (0 = identiques aux dégâts normaux)
+	Stat             int // Caractéristique de l'élément (Force, Intelligence, Chance, Agilité)
+	Power            int // Puissance
+	Fixed            int // Dommages fixes + dommages de l'élément
+	CritFixed        int // Dommages critiques (ajoutés en coup critique)
+	FinalPercent     int // Bonus de dommages finaux en % (sorts, mêlée ou distance), cumulés
+	ResistPercent    int // Résistance de la cible en % dans l'élément
+	ResistFixed      int // Résistance fixe de la cible dans l'élément
+	CritResist       int // Résistance critique de la cible (retirée en coup critique)
+	CritChance       int // Probabilité de coup critique en %
+}
+
+// Range est une fourchette de dégâts infligés.
+type Range struct {
+	Min, Max int
+}
+
+// Avg retourne la moyenne de la fourchette.
+func (r Range) Avg() float64 {
+	return float64(r.Min+r.Max) / 2
+}
+
+// DamageResult est le résultat d'un calcul de dégâts.
+type DamageResult struct {
+	Input    DamageInput
+	Normal   Range
+	Crit     Range
+	Expected float64 // Moyenne pondérée par la probabilité de coup critique
+}
+
+// Damage calcule les dégâts infligés, dans l'ordre du jeu :
+//
+//	brut   = base × (100 + caractéristique + puissance) / 100 + dommages fixes
+//	réduit = (brut - résistance fixe) × (100 - % résistance) / 100
+//	final  = réduit × (100 + % dommages finaux) / 100
+//
+// Chaque étape est arrondie à l'inférieur et les dégâts ne descendent pas sous 0.
+func Damage(in DamageInput) (DamageResult, error) {
+	if in.BaseMin < 0 || in.BaseMax < in.BaseMin {
+		return DamageResult{}, fmt.Errorf("dégâts de base invalides (%d à %d)", in.BaseMin, in.BaseMax)
+	}
+	if in.CritMin == 0 && in.CritMax == 0 {
+		in.CritMin, in.CritMax = in.BaseMin, in.BaseMax
+	}
+	if in.CritMin < 0 || in.CritMax < in.CritMin {
+		return DamageResult{}, fmt.Errorf("dégâts critiques invalides (%d à %d)", in.CritMin, in.CritMax)
+	}
+	in.CritChance = min(max(in.CritChance, 0), 100)
+
+	r := DamageResult{
+		Input:  in,
+		Normal: Range{hit(in, in.BaseMin, false), hit(in, in.BaseMax, false)},
+		Crit:   Range{hit(in, in.CritMin, true), hit(in, in.CritMax, true)},
+	}
+	cc := float64(in.CritChance) / 100
+	r.Expected = (1-cc)*r.Normal.Avg() + cc*r.Crit.Avg()
+	return r, nil
+}
+
+// hit calcule les dégâts d'une valeur de base.
+func hit(in DamageInput, base int, crit bool) int {
+	bonus := max(in.Stat+in.Power, 0) // Une caractéristique négative n'affaiblit pas en dessous de la base
+	dmg := base*(100+bonus)/100 + in.Fixed
+	resist := in.ResistFixed
+	if crit {
+		dmg += in.CritFixed
+		resist += in.CritResist
+	}
+	dmg = max(dmg-resist, 0) * (100 - in.ResistPercent) / 100
+	dmg = dmg * max(100+in.FinalPercent, 0) / 100
+	return max(dmg, 0)
+}
+
+// Describe présente le résultat pour le LLM, avec les hypothèses utilisées.
+func (r DamageResult) Describe() string {
+	in := r.Input
+	var b strings.Builder
+	fmt.Fprintf(&b, "Dégâts normaux : %d à %d (moyenne %.1f)\n", r.Normal.Min, r.Normal.Max, r.Normal.Avg())
+	fmt.Fprintf(&b, "Dégâts critiques : %d à %d (moyenne %.1f)\n", r.Crit.Min, r.Crit.Max, r.Crit.Avg())
+	fmt.Fprintf(&b, "Moyenne par coup avec %d %% de critique : %.1f\n", in.CritChance, r.Expected)
+	fmt.Fprintf(&b, "Hypothèses : base %d-%d (critique %d-%d), caractéristique %d, puissance %d, dommages fixes %d, dommages critiques %d, dommages finaux %+d %%, ",
+		in.BaseMin, in.BaseMax, in.CritMin, in.CritMax, in.Stat, in.Power, in.Fixed, in.CritFixed, in.FinalPercent)
+	fmt.Fprintf(&b, "cible à %d %% et %d de résistance fixe (%d de résistance critique)\n", in.ResistPercent, in.ResistFixed, in.CritResist)
+	return b.String()
+}
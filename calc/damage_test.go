@@ -0,0 +1,72 @@
+package calc
+
+import "testing"
+
+func TestDamage(t *testing.T) {
+	tests := []struct {
+		name     string
+		in       DamageInput
+		normal   Range
+		crit     Range
+		expected float64
+	}{
+		{
+			name:     "caractéristique, puissance et dommages",
+			in:       DamageInput{BaseMin: 20, BaseMax: 25, Stat: 400, Power: 100, Fixed: 10, CritFixed: 20},
+			normal:   Range{130, 160},
+			crit:     Range{150, 180},
+			expected: 145,
+		},
+		{
+			name:     "résistances puis dommages finaux, arrondis à l'inférieur",
+			in:       DamageInput{BaseMin: 20, BaseMax: 20, Stat: 100, ResistFixed: 10, ResistPercent: 50, FinalPercent: 20, CritResist: 5, CritChance: 50},
+			normal:   Range{18, 18},
+			crit:     Range{14, 14},
+			expected: 16,
+		},
+		{
+			name:   "résistance fixe supérieure aux dégâts",
+			in:     DamageInput{BaseMin: 10, BaseMax: 12, ResistFixed: 50},
+			normal: Range{0, 0},
+			crit:   Range{0, 0},
+		},
+		{
+			name:     "caractéristique négative",
+			in:       DamageInput{BaseMin: 10, BaseMax: 10, Stat: -50},
+			normal:   Range{10, 10},
+			crit:     Range{10, 10},
+			expected: 10,
+		},
+		{
+			name:     "dégâts critiques propres, probabilité bornée à 100 %",
+			in:       DamageInput{BaseMin: 10, BaseMax: 10, CritMin: 30, CritMax: 30, CritChance: 150},
+			normal:   Range{10, 10},
+			crit:     Range{30, 30},
+			expected: 30,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Damage(tt.in)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got.Normal != tt.normal || got.Crit != tt.crit || got.Expected != tt.expected {
+				t.Errorf("Damage() = normal %v, crit %v, moyenne %v, attendu %v, %v, %v",
+					got.Normal, got.Crit, got.Expected, tt.normal, tt.crit, tt.expected)
+			}
+		})
+	}
+}
+
+func TestDamageInvalid(t *testing.T) {
+	for _, in := range []DamageInput{
+		{BaseMin: 20, BaseMax: 10},
+		{BaseMin: -5, BaseMax: 10},
+		{BaseMin: 10, BaseMax: 20, CritMin: 30, CritMax: 25},
+	} {
+		if _, err := Damage(in); err == nil {
+			t.Errorf("Damage(%+v) accepté, attendu une erreur", in)
+		}
+	}
+}
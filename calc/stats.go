@@ -0,0 +1,178 @@
+package calc
+
+import (
+	"fmt"
+	"otom-ai/items"
+	"strings"
+)
+
+const (
+	// PointsPerLevel est le nombre de points de capital gagnés à chaque niveau.
+	PointsPerLevel = 5
+	// MaxScrolled est le nombre maximum de points de parchemin par caractéristique.
+	MaxScrolled = 100
+	// MaxLevel est le niveau maximum d'un personnage.
+	MaxLevel = 200
+)
+
+// MaxPoints est le nombre maximum de points investis dans une caractéristique (tout le capital du
+// niveau maximum, au coût minimal d'un capital par point).
+var MaxPoints = Capital(MaxLevel)
+
+// tier est un palier de coût : jusqu'à Until points investis, chaque point coûte Cost points de capital.
+type tier struct {
+	Until int // 0 = sans limite
+	Cost  int
+}
+
+// Characteristic est une caractéristique de base d'un personnage.
+type Characteristic struct {
+	Name  string
+	tiers []tier
+}
+
+// elementTiers sont les paliers communs à la Force, l'Intelligence, la Chance et l'Agilité.
+var elementTiers = []tier{{100, 1}, {200, 2}, {300, 3}, {0, 4}}
+
+// Characteristics liste les caractéristiques et leurs paliers de coût.
+var Characteristics = []Characteristic{
+	{"Vitalité", []tier{{0, 1}}},
+	{"Sagesse", []tier{{0, 3}}},
+	{"Force", elementTiers},
+	{"Intelligence", elementTiers},
+	{"Chance", elementTiers},
+	{"Agilité", elementTiers},
+}
+
+// FindCharacteristic retrouve une caractéristique par son nom (casse et accents ignorés).
+func FindCharacteristic(name string) (Characteristic, error) {
+	n := items.Normalize(name)
+	for _, c := range Characteristics {
+		if items.Normalize(c.Name) == n {
+			return c, nil
+		}
+	}
+	return Characteristic{}, fmt.Errorf("caractéristique inconnue %q", name)
+}
+
+// Cost retourne le coût en capital pour passer de from à to points investis (hors parchemins),
+// palier par palier.
+func (c Characteristic) Cost(from, to int) int {
+	cost, lo := 0, 0
+	for _, t := range c.tiers {
+		hi := t.Until
+		if hi == 0 {
+			hi = max(to, lo)
+		}
+		if n := min(to, hi) - max(from, lo); n > 0 {
+			cost += n * t.Cost
+		}
+		lo = hi
+	}
+	return cost
+}
+
+// Reachable retourne le nombre de points investis atteignable depuis from avec capital,
+// et le capital restant.
+func (c Characteristic) Reachable(from, capital int) (points, left int) {
+	points = from
+	for _, t := range c.tiers {
+		if t.Until != 0 && points >= t.Until {
+			continue // Palier déjà dépassé
+		}
+		n := capital / t.Cost
+		if t.Until != 0 {
+			n = min(n, t.Until-points)
+		}
+		points += n
+		capital -= n * t.Cost
+		if t.Until == 0 || points < t.Until {
+			break // Capital épuisé dans ce palier
+		}
+	}
+	return points, capital
+}
+
+// Capital retourne le capital total gagné à un niveau.
+func Capital(level int) int {
+	return PointsPerLevel * max(level-1, 0)
+}
+
+// StatsInput décrit une question d'allocation de points de caractéristique.
+type StatsInput struct {
+	Characteristic string
+	Current        int // Points déjà investis (hors parchemins)
+	Target         int // Points investis visés (0 = maximum atteignable au niveau donné)
+	Scrolled       int // Points de parchemin déjà appris
+	Level          int // Niveau du personnage (0 = inconnu)
+}
+
+// Stats calcule le coût d'une allocation de points et décrit le résultat pour le LLM.
+func Stats(in StatsInput) (string, error) {
+	c, err := FindCharacteristic(in.Characteristic)
+	if err != nil {
+		return "", err
+	}
+	if in.Current < 0 || in.Target < 0 || in.Scrolled < 0 || in.Scrolled > MaxScrolled {
+		return "", fmt.Errorf("valeurs invalides (parchemins : 0 à %d)", MaxScrolled)
+	}
+	if in.Current > MaxPoints || in.Target > MaxPoints || in.Level < 0 || in.Level > MaxLevel {
+		return "", fmt.Errorf("valeurs invalides (points investis : 0 à %d, niveau : 1 à %d ou 0 si inconnu)", MaxPoints, MaxLevel)
+	}
+	if in.Target == 0 && in.Level <= 0 {
+		return "", fmt.Errorf("préciser l'objectif de points ou le niveau du personnage")
+	}
+
+	var b strings.Builder
+	if in.Target > 0 {
+		if in.Target < in.Current {
+			return "", fmt.Errorf("l'objectif (%d) est inférieur aux points déjà investis (%d)", in.Target, in.Current)
+		}
+		cost := c.Cost(in.Current, in.Target)
+		fmt.Fprintf(&b, "%s : passer de %d à %d points investis coûte %d points de capital.\n", c.Name, in.Current, in.Target, cost)
+		if in.Level > 0 {
+			capital := Capital(in.Level)
+			if cost <= capital {
+				fmt.Fprintf(&b, "Au niveau %d (%d points de capital au total), c'est possible s'il reste assez de capital non dépensé.\n", in.Level, capital)
+			} else {
+				fmt.Fprintf(&b, "Au niveau %d, le capital total n'est que de %d points : impossible.\n", in.Level, capital)
+			}
+		}
+		fmt.Fprintf(&b, "Niveau minimum pour disposer de ce capital : %d.\n", (cost+PointsPerLevel-1)/PointsPerLevel+1)
+	} else {
+		capital := Capital(in.Level) - c.Cost(0, in.Current)
+		if capital < 0 {
+			return "", fmt.Errorf("%d points investis en %s coûtent plus que le capital du niveau %d", in.Current, c.Name, in.Level)
+		}
+		points, left := c.Reachable(in.Current, capital)
+		fmt.Fprintf(&b, "%s : au niveau %d, tout le capital restant (%d points) permet d'atteindre %d points investis (reste %d points de capital).\n",
+			c.Name, in.Level, capital, points, left)
+		in.Target = points
+	}
+
+	fmt.Fprintf(&b, "Total avec les parchemins : %d (dont %d de parchemins, maximum %d, qui ne coûtent pas de capital", in.Target+in.Scrolled, in.Scrolled, MaxScrolled)
+	if missing := MaxScrolled - in.Scrolled; missing > 0 {
+		fmt.Fprintf(&b, " ; encore %d points à parchotter", missing)
+	}
+	b.WriteString(").\n")
+	fmt.Fprintf(&b, "Barème : %s.\n", c.Scale())
+	return b.String(), nil
+}
+
+// Scale décrit les paliers de coût de la caractéristique.
+func (c Characteristic) Scale() string {
+	var parts []string
+	from := 0
+	for _, t := range c.tiers {
+		if t.Until == 0 {
+			parts = append(parts, fmt.Sprintf("%d capital par point au-delà de %d", t.Cost, from))
+			break
+		}
+		parts = append(parts, fmt.Sprintf("%d capital par point de %d à %d", t.Cost, from, t.Until))
+		from = t.Until
+	}
+	if len(c.tiers) == 1 {
+		return fmt.Sprintf("%d capital par point", c.tiers[0].Cost)
+	}
+	return strings.Join(parts, ", ")
+}
@@ -0,0 +1,56 @@
+package calc
+
+import "testing"
+
+func TestCharacteristicCost(t *testing.T) {
+	tests := []struct {
+		name     string
+		from, to int
+		want     int
+	}{
+		{"Force", 0, 100, 100},
+		{"Force", 0, 150, 200},
+		{"Force", 90, 210, 240}, // 10×1 + 100×2 + 10×3
+		{"Force", 350, 360, 40},
+		{"Force", 100, 50, 0},
+		{"Vitalité", 0, 1000, 1000},
+		{"Sagesse", 10, 20, 30},
+	}
+	for _, tt := range tests {
+		c, err := FindCharacteristic(tt.name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := c.Cost(tt.from, tt.to); got != tt.want {
+			t.Errorf("%s.Cost(%d, %d) = %d, attendu %d", tt.name, tt.from, tt.to, got, tt.want)
+		}
+	}
+}
+
+func TestCharacteristicReachable(t *testing.T) {
+	force, err := FindCharacteristic("force")
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		from, capital int
+		points, left  int
+	}{
+		{0, Capital(MaxLevel), 398, 3}, // 100 + 200 + 300 + 98×4 = 992
+		{0, 50, 50, 0},
+		{100, 5, 102, 1},
+		{250, 10, 253, 1},
+	}
+	for _, tt := range tests {
+		points, left := force.Reachable(tt.from, tt.capital)
+		if points != tt.points || left != tt.left {
+			t.Errorf("Reachable(%d, %d) = %d, %d, attendu %d, %d", tt.from, tt.capital, points, left, tt.points, tt.left)
+		}
+		if spent := force.Cost(tt.from, points); spent != tt.capital-left {
+			t.Errorf("Cost(%d, %d) = %d, attendu le capital dépensé %d", tt.from, points, spent, tt.capital-left)
+		}
+	}
+	if _, err := FindCharacteristic("Puissance"); err == nil {
+		t.Error("FindCharacteristic(Puissance) accepté, attendu une erreur")
+	}
+}
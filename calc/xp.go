@@ -0,0 +1,111 @@
+package calc
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// XPTable donne l'expérience totale requise pour chaque niveau. Elle est chargée depuis
+// un fichier local : aucune valeur n'est embarquée pour ne pas donner de chiffres faux.
+type XPTable struct {
+	totals []int64 // totals[i] = expérience totale pour atteindre le niveau i+1
+}
+
+// xpDataset est le format JSON de la table d'expérience.
+type xpDataset struct {
+	Levels []int64 `json:"levels"` // Expérience totale par niveau, en commençant au niveau 1 (0)
+}
+
+// LoadXPTable charge la table d'expérience ; elle est vide si path est vide.
+func LoadXPTable(path string) (*XPTable, error) {
+	if path == "" {
+		return &XPTable{}, nil
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("lecture de la table d'expérience: %w", err)
+	}
+	var ds xpDataset
+	if err := json.Unmarshal(data, &ds); err != nil {
+		return nil, fmt.Errorf("table d'expérience invalide %s: %w", path, err)
+	}
+	for i := 1; i < len(ds.Levels); i++ {
+		if ds.Levels[i] <= ds.Levels[i-1] {
+			return nil, fmt.Errorf("table d'expérience invalide %s: l'expérience du niveau %d n'est pas croissante", path, i+1)
+		}
+	}
+	return &XPTable{totals: ds.Levels}, nil
+}
+
+// MaxLevel retourne le niveau le plus élevé de la table (0 si elle est vide).
+func (t *XPTable) MaxLevel() int {
+	return len(t.totals)
+}
+
+// Total retourne l'expérience totale requise pour atteindre un niveau.
+func (t *XPTable) Total(level int) (int64, bool) {
+	if level < 1 || level > len(t.totals) {
+		return 0, false
+	}
+	return t.totals[level-1], true
+}
+
+// Level retourne le niveau correspondant à une expérience totale.
+func (t *XPTable) Level(xp int64) int {
+	level := 0
+	for level < len(t.totals) && t.totals[level] <= xp {
+		level++
+	}
+	return max(level, 1)
+}
+
+// ToLevel décrit pour le LLM l'expérience manquante pour passer de level (avec xp
+// d'expérience totale, 0 = début du niveau) au niveau target (0 = niveau suivant).
+func (t *XPTable) ToLevel(level int, xp int64, target int) (string, error) {
+	if t.MaxLevel() == 0 {
+		return "", fmt.Errorf("table d'expérience non configurée (xp.file) : ne donne pas de chiffres d'expérience de mémoire")
+	}
+	start, ok := t.Total(level)
+	if !ok {
+		return "", fmt.Errorf("niveau %d hors de la table (1 à %d)", level, t.MaxLevel())
+	}
+	if target == 0 {
+		target = level + 1
+	}
+	goal, ok := t.Total(target)
+	if !ok {
+		return "", fmt.Errorf("niveau visé %d hors de la table (1 à %d)", target, t.MaxLevel())
+	}
+	if target <= level {
+		return "", fmt.Errorf("le niveau visé (%d) doit être supérieur au niveau actuel (%d)", target, level)
+	}
+	if xp < start {
+		xp = start
+	}
+	if next, ok := t.Total(level + 1); ok && xp >= next {
+		return "", fmt.Errorf("%d points d'expérience correspondent au niveau %d, pas au niveau %d", xp, t.Level(xp), level)
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "Niveau %d → %d : %s points d'expérience manquants", level, target, formatInt(goal-xp))
+	fmt.Fprintf(&b, " (expérience totale actuelle %s, requise %s).\n", formatInt(xp), formatInt(goal))
+	if target == level+1 {
+		fmt.Fprintf(&b, "Progression dans le niveau %d : %.1f %%.\n", level, 100*float64(xp-start)/float64(goal-start))
+	}
+	return b.String(), nil
+}
+
+// formatInt écrit un entier positif avec une espace comme séparateur de milliers.
+func formatInt(n int64) string {
+	s := fmt.Sprintf("%d", n)
+	var out []byte
+	for i := range len(s) {
+		if i > 0 && (len(s)-i)%3 == 0 {
+			out = append(out, ' ')
+		}
+		out = append(out, s[i])
+	}
+	return string(out)
+}
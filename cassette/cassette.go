@@ -0,0 +1,159 @@
+// Package cassette enregistre et rejoue les échanges HTTP du bot (LLM et recherche web).
+// Les cassettes permettent de reproduire hors-ligne une conversation problématique
+// (hallucination, mauvais appel d'outil...) et d'en faire un test de non-régression.
+package cassette
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// Modes de fonctionnement supportés.
+const (
+	ModeOff    = ""       // Transport HTTP standard
+	ModeRecord = "record" // Enregistrement des échanges dans des cassettes
+	ModeReplay = "replay" // Rejeu déterministe des cassettes (aucun appel réseau)
+)
+
+// redacted remplace toute valeur sensible dans les cassettes.
+const redacted = "[REDACTED]"
+
+// Cassette regroupe les échanges HTTP d'une conversation, dans l'ordre d'exécution.
+type Cassette struct {
+	Name         string        `json:"name"`
+	Interactions []Interaction `json:"interactions"`
+}
+
+// Interaction est un couple requête/réponse enregistré.
+type Interaction struct {
+	Request  Request  `json:"request"`
+	Response Response `json:"response"`
+}
+
+// Request est la forme sérialisée (et expurgée) d'une requête HTTP.
+type Request struct {
+	Method  string      `json:"method"`
+	URL     string      `json:"url"`
+	Headers http.Header `json:"headers,omitempty"`
+	Body    string      `json:"body,omitempty"`
+}
+
+// Response est la forme sérialisée d'une réponse HTTP.
+type Response struct {
+	StatusCode int         `json:"status_code"`
+	Headers    http.Header `json:"headers,omitempty"`
+	Body       string      `json:"body,omitempty"`
+}
+
+// Load lit une cassette depuis un fichier JSON.
+func Load(path string) (*Cassette, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("lecture cassette %s: %w", path, err)
+	}
+	var c Cassette
+	if err := json.Unmarshal(data, &c); err != nil {
+		return nil, fmt.Errorf("décodage cassette %s: %w", path, err)
+	}
+	return &c, nil
+}
+
+// Save écrit la cassette dans un fichier JSON indenté (lisible pour le debug).
+func (c *Cassette) Save(path string) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return fmt.Errorf("création dossier cassettes: %w", err)
+	}
+	data, err := json.MarshalIndent(c, "", "  ")
+	if err != nil {
+		return fmt.Errorf("sérialisation cassette: %w", err)
+	}
+	if err := os.WriteFile(path, data, 0o644); err != nil {
+		return fmt.Errorf("écriture cassette %s: %w", path, err)
+	}
+	return nil
+}
+
+// ---------- Nommage par contexte ----------
+
+type nameKey struct{}
+
+// WithName associe un nom de cassette au contexte (ex: un identifiant de conversation).
+// Toutes les requêtes faites avec ce contexte sont regroupées dans la même cassette.
+func WithName(ctx context.Context, name string) context.Context {
+	return context.WithValue(ctx, nameKey{}, name)
+}
+
+// nameFromContext retourne le nom de cassette du contexte, ou fallback s'il n'y en a pas.
+func nameFromContext(ctx context.Context, fallback string) string {
+	if name, ok := ctx.Value(nameKey{}).(string); ok && name != "" {
+		return sanitizeName(name)
+	}
+	return fallback
+}
+
+var unsafeNameChars = regexp.MustCompile(`[^a-zA-Z0-9._-]+`)
+
+// sanitizeName rend un nom de cassette utilisable comme nom de fichier.
+func sanitizeName(name string) string {
+	return unsafeNameChars.ReplaceAllString(name, "_")
+}
+
+// ---------- Expurgation des secrets ----------
+
+// sensitiveHeaders liste les en-têtes portant des identifiants d'API.
+var sensitiveHeaders = []string{"Authorization", "X-Subscription-Token", "X-Api-Key", "Api-Key"}
+
+// sensitiveJSONField repère les clés d'API passées dans le corps JSON (ex: Tavily "api_key").
+var sensitiveJSONField = regexp.MustCompile(`("(?:api_key|apiKey|token|access_token)"\s*:\s*)"[^"]*"`)
+
+// Redactor masque les secrets connus dans les requêtes et réponses enregistrées.
+type Redactor struct {
+	secrets []string
+}
+
+// NewRedactor crée un Redactor masquant en plus les valeurs littérales données.
+func NewRedactor(secrets ...string) *Redactor {
+	r := &Redactor{}
+	for _, s := range secrets {
+		if s != "" {
+			r.secrets = append(r.secrets, s)
+		}
+	}
+	return r
+}
+
+// Headers retourne une copie des en-têtes avec les valeurs sensibles masquées.
+func (r *Redactor) Headers(h http.Header) http.Header {
+	out := h.Clone()
+	for _, name := range sensitiveHeaders {
+		if out.Get(name) != "" {
+			out.Set(name, redacted)
+		}
+	}
+	for name, values := range out {
+		for i, v := range values {
+			out[name][i] = r.String(v)
+		}
+	}
+	return out
+}
+
+// Body masque les champs JSON sensibles et les secrets littéraux d'un corps HTTP.
+func (r *Redactor) Body(body string) string {
+	body = sensitiveJSONField.ReplaceAllString(body, `${1}"`+redacted+`"`)
+	return r.String(body)
+}
+
+// String masque les secrets littéraux d'une chaîne quelconque (URL, en-tête...).
+func (r *Redactor) String(s string) string {
+	for _, secret := range r.secrets {
+		s = strings.ReplaceAll(s, secret, redacted)
+	}
+	return s
+}
@@ -0,0 +1,140 @@
+package cassette
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"path/filepath"
+	"slices"
+	"sync"
+	"time"
+)
+
+// maxOpenCassettes est le nombre de cassettes gardées en mémoire par le Recorder. Chaque
+// cassette est réécrite sur disque à chaque échange : une cassette écartée est simplement
+// relue si sa conversation reprend.
+const maxOpenCassettes = 64
+
+// Recorder est un http.RoundTripper qui exécute les requêtes via le transport sous-jacent
+// et enregistre chaque échange (expurgé) dans la cassette nommée par le contexte.
+type Recorder struct {
+	mu        sync.Mutex
+	dir       string
+	next      http.RoundTripper
+	redactor  *Redactor
+	logger    *slog.Logger
+	fallback  string               // Nom de cassette si le contexte n'en porte pas
+	cassettes map[string]*Cassette // Cassettes ouvertes, indexées par nom
+	recent    []string             // Noms des cassettes ouvertes, de la moins à la plus récemment utilisée
+}
+
+// NewRecorder crée un Recorder écrivant ses cassettes dans dir.
+// Si next est nil, http.DefaultTransport est utilisé.
+func NewRecorder(dir string, next http.RoundTripper, redactor *Redactor, logger *slog.Logger) *Recorder {
+	if next == nil {
+		next = http.DefaultTransport
+	}
+	if redactor == nil {
+		redactor = NewRedactor()
+	}
+	return &Recorder{
+		dir:       dir,
+		next:      next,
+		redactor:  redactor,
+		logger:    logger,
+		fallback:  "session-" + time.Now().Format("20060102-150405"),
+		cassettes: make(map[string]*Cassette),
+	}
+}
+
+// RoundTrip implémente http.RoundTripper.
+func (r *Recorder) RoundTrip(req *http.Request) (*http.Response, error) {
+	// Un RoundTripper ne doit pas modifier la requête reçue : on travaille sur une copie
+	req = req.Clone(req.Context())
+	reqBody, err := drainBody(&req.Body)
+	if err != nil {
+		return nil, fmt.Errorf("lecture corps requête: %w", err)
+	}
+
+	resp, err := r.next.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+
+	respBody, err := drainBody(&resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("lecture corps réponse: %w", err)
+	}
+
+	interaction := Interaction{
+		Request: Request{
+			Method:  req.Method,
+			URL:     r.redactor.String(req.URL.String()),
+			Headers: r.redactor.Headers(req.Header),
+			Body:    r.redactor.Body(string(reqBody)),
+		},
+		Response: Response{
+			StatusCode: resp.StatusCode,
+			Headers:    r.redactor.Headers(resp.Header),
+			Body:       r.redactor.Body(string(respBody)),
+		},
+	}
+
+	name := nameFromContext(req.Context(), r.fallback)
+	if err := r.append(name, interaction); err != nil {
+		// L'enregistrement est un outil de debug : il ne doit jamais casser l'appel réel
+		r.logger.Warn("Impossible d'enregistrer la cassette",
+			slog.String("cassette", name),
+			slog.String("error", err.Error()),
+		)
+	}
+	return resp, nil
+}
+
+// append ajoute un échange à la cassette et la réécrit sur disque.
+func (r *Recorder) append(name string, interaction Interaction) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	path := filepath.Join(r.dir, name+".json")
+	c, ok := r.cassettes[name]
+	if !ok {
+		c = &Cassette{Name: name}
+		// Conversation reprise après éviction : on complète la cassette déjà écrite
+		if saved, err := Load(path); err == nil {
+			c = saved
+		}
+		r.cassettes[name] = c
+	}
+	r.touch(name)
+	c.Interactions = append(c.Interactions, interaction)
+	return c.Save(path)
+}
+
+// touch marque la cassette comme la plus récemment utilisée et écarte de la mémoire les plus
+// anciennes au-delà de maxOpenCassettes (appelé verrou pris).
+func (r *Recorder) touch(name string) {
+	r.recent = slices.DeleteFunc(r.recent, func(n string) bool { return n == name })
+	r.recent = append(r.recent, name)
+	for len(r.recent) > maxOpenCassettes {
+		delete(r.cassettes, r.recent[0])
+		r.recent = r.recent[1:]
+	}
+}
+
+// drainBody lit entièrement un corps HTTP et le remplace par un lecteur équivalent,
+// afin que le transport et l'appelant puissent toujours le consommer.
+func drainBody(body *io.ReadCloser) ([]byte, error) {
+	if *body == nil || *body == http.NoBody {
+		return nil, nil
+	}
+	data, err := io.ReadAll(*body)
+	_ = (*body).Close()
+	if err != nil {
+		return nil, err
+	}
+	*body = io.NopCloser(bytes.NewReader(data))
+	return data, nil
+}
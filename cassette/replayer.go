@@ -0,0 +1,179 @@
+package cassette
+
+import (
+	"bytes"
+	"cmp"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// Replayer est un http.RoundTripper qui sert les réponses enregistrées sans aucun appel réseau.
+// Chaque cassette rejouée (nommée par le contexte, voir WithName) est liée à une seule cassette
+// enregistrée : d'abord celle d'un échange de même URL et même corps, sinon la première libre.
+// Faute de corps identique, le premier échange non rejoué vers le même endpoint est servi.
+type Replayer struct {
+	mu       sync.Mutex
+	redactor *Redactor
+	entries  []entry
+	bound    map[string]string // Cassette rejouée → cassette enregistrée
+	taken    map[string]bool   // Cassettes enregistrées déjà liées
+}
+
+// entry est un échange enregistré, avec le nom de sa cassette et l'empreinte de son corps.
+type entry struct {
+	Interaction
+	cassette string
+	hash     string
+	used     bool
+}
+
+// NewReplayer charge une cassette (fichier .json) ou toutes les cassettes d'un dossier.
+func NewReplayer(path string, redactor *Redactor) (*Replayer, error) {
+	if redactor == nil {
+		redactor = NewRedactor()
+	}
+
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, fmt.Errorf("cassette introuvable: %w", err)
+	}
+
+	files := []string{path}
+	if info.IsDir() {
+		files, err = filepath.Glob(filepath.Join(path, "*.json"))
+		if err != nil {
+			return nil, fmt.Errorf("listing des cassettes: %w", err)
+		}
+		sort.Strings(files)
+	}
+
+	r := &Replayer{redactor: redactor, bound: map[string]string{}, taken: map[string]bool{}}
+	for _, f := range files {
+		c, err := Load(f)
+		if err != nil {
+			return nil, err
+		}
+		name := cmp.Or(c.Name, strings.TrimSuffix(filepath.Base(f), ".json"))
+		for _, in := range c.Interactions {
+			r.entries = append(r.entries, entry{Interaction: in, cassette: name, hash: bodyHash(in.Request.Body)})
+		}
+	}
+	if len(r.entries) == 0 {
+		return nil, fmt.Errorf("aucun échange enregistré dans %s", path)
+	}
+	return r, nil
+}
+
+// RoundTrip implémente http.RoundTripper.
+func (r *Replayer) RoundTrip(req *http.Request) (*http.Response, error) {
+	var body []byte
+	if req.Body != nil && req.Body != http.NoBody {
+		data, err := io.ReadAll(req.Body)
+		_ = req.Body.Close()
+		if err != nil {
+			return nil, fmt.Errorf("lecture corps requête: %w", err)
+		}
+		body = data
+	}
+
+	url := r.redactor.String(req.URL.String())
+	hash := bodyHash(r.redactor.Body(string(body)))
+	live := nameFromContext(req.Context(), "")
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	// Une cassette rejouée sous son nom d'origine est liée d'office à son enregistrement
+	recorded, bound := r.bound[live]
+	if !bound && live != "" && !r.taken[live] && slices.ContainsFunc(r.entries, func(e entry) bool { return e.cassette == live }) {
+		recorded, bound = live, true
+		r.bind(live, live)
+	}
+	eligible := func(e entry) bool {
+		if bound {
+			return e.cassette == recorded
+		}
+		return !r.taken[e.cassette]
+	}
+
+	idx := r.find(func(e entry) bool {
+		return eligible(e) && e.Request.Method == req.Method && e.Request.URL == url && e.hash == hash
+	})
+	if idx < 0 {
+		idx = r.find(func(e entry) bool {
+			return eligible(e) && e.Request.Method == req.Method && endpoint(e.Request.URL) == endpoint(url)
+		})
+	}
+	if idx < 0 {
+		return nil, fmt.Errorf("aucun échange enregistré pour %s %s", req.Method, url)
+	}
+	e := &r.entries[idx]
+	e.used = true
+	if !bound && live != "" {
+		r.bind(live, e.cassette)
+	}
+
+	rec := e.Response
+	return &http.Response{
+		StatusCode:    rec.StatusCode,
+		Status:        fmt.Sprintf("%d %s", rec.StatusCode, http.StatusText(rec.StatusCode)),
+		Header:        rec.Headers.Clone(),
+		Body:          io.NopCloser(bytes.NewReader([]byte(rec.Body))),
+		ContentLength: int64(len(rec.Body)),
+		Request:       req,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+	}, nil
+}
+
+// Remaining retourne le nombre d'échanges enregistrés qui n'ont pas encore été rejoués.
+func (r *Replayer) Remaining() int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	n := 0
+	for _, e := range r.entries {
+		if !e.used {
+			n++
+		}
+	}
+	return n
+}
+
+// find retourne l'index du premier échange non rejoué satisfaisant match, ou -1.
+func (r *Replayer) find(match func(entry) bool) int {
+	for i, e := range r.entries {
+		if !e.used && match(e) {
+			return i
+		}
+	}
+	return -1
+}
+
+// bind lie une cassette rejouée à une cassette enregistrée (appelé verrou pris).
+func (r *Replayer) bind(live, recorded string) {
+	r.bound[live] = recorded
+	r.taken[recorded] = true
+}
+
+// bodyHash retourne l'empreinte SHA-256 d'un corps de requête (expurgé).
+func bodyHash(body string) string {
+	sum := sha256.Sum256([]byte(body))
+	return hex.EncodeToString(sum[:])
+}
+
+// endpoint retourne l'URL sans sa query string.
+func endpoint(url string) string {
+	base, _, _ := strings.Cut(url, "?")
+	return base
+}
@@ -0,0 +1,138 @@
+package cassette
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const chatURL = "https://api.deepseek.com/chat/completions"
+
+// exchange construit un échange enregistré : une requête POST et sa réponse.
+func exchange(url, body, reply string) Interaction {
+	return Interaction{
+		Request:  Request{Method: http.MethodPost, URL: url, Body: body},
+		Response: Response{StatusCode: http.StatusOK, Body: reply},
+	}
+}
+
+// openReplayer enregistre les cassettes données dans un dossier temporaire et les charge.
+func openReplayer(t *testing.T, cassettes ...Cassette) *Replayer {
+	t.Helper()
+	dir := t.TempDir()
+	for _, c := range cassettes {
+		if err := c.Save(filepath.Join(dir, c.Name+".json")); err != nil {
+			t.Fatal(err)
+		}
+	}
+	r, err := NewReplayer(dir, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return r
+}
+
+// replay envoie une requête POST sous le nom de cassette live et retourne le corps de la réponse.
+func replay(t *testing.T, r *Replayer, live, url, body string) (string, error) {
+	t.Helper()
+	ctx := WithName(context.Background(), live)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, strings.NewReader(body))
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp, err := r.RoundTrip(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(data), nil
+}
+
+func TestReplayerBinding(t *testing.T) {
+	recorded := []Cassette{
+		{Name: "1-a", Interactions: []Interaction{
+			exchange(chatURL, `{"q":"almanax"}`, "a1"),
+			exchange(chatURL, `{"q":"almanax","tool":"get_almanax"}`, "a2"),
+		}},
+		{Name: "1-b", Interactions: []Interaction{
+			exchange(chatURL, `{"q":"stuff"}`, "b1"),
+			exchange(chatURL, `{"q":"stuff","tool":"lookup_item"}`, "b2"),
+		}},
+	}
+
+	tests := []struct {
+		name  string
+		steps [][2]string // Cassette rejouée, corps de la requête
+		want  []string
+	}{
+		{
+			name:  "corps identique puis endpoint dans la cassette liée",
+			steps: [][2]string{{"2-x", `{"q":"stuff"}`}, {"2-x", `{"q":"stuff","at":"20:01"}`}},
+			want:  []string{"b1", "b2"},
+		},
+		{
+			name:  "nom d'origine lié d'office",
+			steps: [][2]string{{"1-b", `{"q":"autre"}`}, {"1-b", `{"q":"autre"}`}},
+			want:  []string{"b1", "b2"},
+		},
+		{
+			name:  "cassettes libres dans l'ordre des fichiers",
+			steps: [][2]string{{"2-x", `{"q":"?"}`}, {"2-y", `{"q":"?"}`}, {"2-x", `{"q":"?"}`}, {"2-y", `{"q":"?"}`}},
+			want:  []string{"a1", "b1", "a2", "b2"},
+		},
+		{
+			name:  "cassette déjà liée à une autre",
+			steps: [][2]string{{"2-x", `{"q":"stuff"}`}, {"2-y", `{"q":"stuff","tool":"lookup_item"}`}},
+			want:  []string{"b1", "a1"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := openReplayer(t, recorded...)
+			for i, step := range tt.steps {
+				got, err := replay(t, r, step[0], chatURL, step[1])
+				if err != nil {
+					t.Fatalf("étape %d: %v", i, err)
+				}
+				if got != tt.want[i] {
+					t.Errorf("étape %d (%s) = %q, attendu %q", i, step[0], got, tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestReplayerFallback(t *testing.T) {
+	const searchURL = "https://api.tavily.com/search"
+	r := openReplayer(t, Cassette{Name: "1-a", Interactions: []Interaction{
+		exchange(chatURL, `{"q":"premier"}`, "chat1"),
+		exchange(searchURL+"?lang=fr", `{"query":"dofus"}`, "search"),
+		exchange(chatURL, `{"q":"second"}`, "chat2"),
+	}})
+
+	// L'empreinte du corps prime sur l'ordre d'enregistrement
+	if got, err := replay(t, r, "1-a", chatURL, `{"q":"second"}`); err != nil || got != "chat2" {
+		t.Errorf("corps identique = %q, %v, attendu chat2", got, err)
+	}
+	// Même endpoint, query string et corps différents
+	if got, err := replay(t, r, "1-a", searchURL+"?lang=en", `{"query":"wakfu"}`); err != nil || got != "search" {
+		t.Errorf("même endpoint = %q, %v, attendu search", got, err)
+	}
+	if got, err := replay(t, r, "1-a", chatURL, `{"q":"modifié"}`); err != nil || got != "chat1" {
+		t.Errorf("premier échange libre = %q, %v, attendu chat1", got, err)
+	}
+	if n := r.Remaining(); n != 0 {
+		t.Errorf("Remaining() = %d, attendu 0", n)
+	}
+	// Un échange n'est servi qu'une fois
+	if _, err := replay(t, r, "1-a", chatURL, `{"q":"premier"}`); err == nil {
+		t.Error("échange déjà rejoué servi une seconde fois")
+	}
+}
@@ -0,0 +1,96 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"os"
+	"os/signal"
+	"otom-ai/almanax"
+	"path/filepath"
+	"time"
+)
+
+// almanaxUsage décrit la commande "otom-ai almanax".
+const almanaxUsage = "Usage : otom-ai almanax import [-o data/almanax.json] [-from AAAA-MM-JJ] [-days N] [-url source] [réponse.json]"
+
+// runAlmanax implémente la commande "otom-ai almanax" (import du jeu de données de l'Almanax).
+// Sans fichier en argument, les jours sont téléchargés depuis l'API communautaire.
+func runAlmanax(logger *slog.Logger, args []string) int {
+	if len(args) == 0 || args[0] != "import" {
+		fmt.Fprintln(os.Stderr, almanaxUsage)
+		return 2
+	}
+
+	year := time.Now().Year()
+	fs := flag.NewFlagSet("almanax import", flag.ContinueOnError)
+	out := fs.String("o", "data/almanax.json", "fichier de sortie (à référencer dans almanax.file)")
+	fromFlag := fs.String("from", fmt.Sprintf("%d-01-01", year), "premier jour téléchargé")
+	days := fs.Int("days", 0, "nombre de jours téléchargés (0 = jusqu'à la fin de l'année suivante)")
+	source := fs.String("url", almanax.DefaultSource, "API source de l'Almanax")
+	if err := fs.Parse(args[1:]); err != nil {
+		return 2
+	}
+	if fs.NArg() > 1 {
+		fmt.Fprintln(os.Stderr, almanaxUsage)
+		return 2
+	}
+
+	var ds almanax.Dataset
+	if fs.NArg() == 1 {
+		f, err := os.Open(fs.Arg(0))
+		if err != nil {
+			logger.Error("Ouverture de la réponse impossible", slog.String("error", err.Error()))
+			return 1
+		}
+		defer f.Close()
+		if ds, err = almanax.Import(f); err != nil {
+			logger.Error("Import de l'Almanax impossible", slog.String("error", err.Error()))
+			return 1
+		}
+	} else {
+		from, err := time.Parse("2006-01-02", *fromFlag)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, "Date -from invalide (AAAA-MM-JJ)")
+			return 2
+		}
+		if *days <= 0 {
+			*days = int(time.Date(from.Year()+2, 1, 1, 0, 0, 0, 0, time.UTC).Sub(from).Hours() / 24)
+		}
+		ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+		defer stop()
+		if ds, err = almanax.Fetch(ctx, &http.Client{Timeout: 30 * time.Second}, *source, from, *days); err != nil {
+			logger.Error("Téléchargement de l'Almanax impossible", slog.String("error", err.Error()))
+			return 1
+		}
+	}
+	if len(ds.Days) == 0 {
+		logger.Error("Aucun jour d'Almanax importé : fichier non écrit")
+		return 1
+	}
+
+	data, err := json.MarshalIndent(ds, "", "  ")
+	if err != nil {
+		logger.Error("Sérialisation de l'Almanax impossible", slog.String("error", err.Error()))
+		return 1
+	}
+	if err := os.MkdirAll(filepath.Dir(*out), 0o755); err != nil {
+		logger.Error("Création du dossier de sortie impossible", slog.String("error", err.Error()))
+		return 1
+	}
+	if err := os.WriteFile(*out, data, 0o644); err != nil {
+		logger.Error("Écriture de l'Almanax impossible", slog.String("error", err.Error()))
+		return 1
+	}
+
+	logger.Info("Almanax importé",
+		slog.String("file", *out),
+		slog.Int("days", len(ds.Days)),
+		slog.String("from", ds.Days[0].Date),
+		slog.String("to", ds.Days[len(ds.Days)-1].Date),
+	)
+	return 0
+}
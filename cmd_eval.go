@@ -0,0 +1,97 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"otom-ai/ai"
+	"otom-ai/bot"
+	"otom-ai/config"
+	"otom-ai/eval"
+	"time"
+)
+
+// runEval implémente la commande "otom-ai eval" : elle joue une suite de scénarios
+// contre le LLM configuré et écrit les rapports JSON et/ou Markdown.
+func runEval(logger *slog.Logger, args []string) int {
+	fs := flag.NewFlagSet("eval", flag.ContinueOnError)
+	jsonOut := fs.String("json", "", "fichier de sortie du rapport JSON")
+	mdOut := fs.String("md", "", "fichier de sortie du rapport Markdown (défaut : sortie standard)")
+	timeout := fs.Duration("timeout", 10*time.Minute, "durée maximale de l'évaluation")
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "Usage : otom-ai eval [options] <suite.yaml>")
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	if fs.NArg() != 1 {
+		fs.Usage()
+		return 2
+	}
+
+	cfg, err := config.Load()
+	if err != nil {
+		logger.Error("Échec du chargement de la configuration", slog.String("error", err.Error()))
+		return 1
+	}
+
+	suite, err := eval.LoadSuite(fs.Arg(0))
+	if err != nil {
+		logger.Error("Suite d'évaluation invalide", slog.String("error", err.Error()))
+		return 1
+	}
+
+	provider := cfg.Provider()
+	client := ai.NewClient(provider.APIKey.Value(), provider.URL, provider.Model)
+	client.SetTemperature(*provider.Temperature)
+	client.SetTimeout(provider.Timeout)
+	tools, err := bot.Tools(cfg, logger)
+	if err != nil {
+		logger.Error("Outils du bot indisponibles", slog.String("error", err.Error()))
+		return 1
+	}
+	runner := eval.NewRunner(client, tools, cfg.SystemPrompt(), logger)
+
+	ctx, cancel := context.WithTimeout(context.Background(), *timeout)
+	defer cancel()
+	report := runner.Run(ctx, suite)
+
+	if *jsonOut != "" {
+		if err := writeReport(*jsonOut, report.WriteJSON); err != nil {
+			logger.Error("Écriture du rapport JSON impossible", slog.String("error", err.Error()))
+			return 1
+		}
+	}
+	if *mdOut != "" {
+		if err := writeReport(*mdOut, report.WriteMarkdown); err != nil {
+			logger.Error("Écriture du rapport Markdown impossible", slog.String("error", err.Error()))
+			return 1
+		}
+	} else if err := report.WriteMarkdown(os.Stdout); err != nil {
+		return 1
+	}
+
+	logger.Info("Évaluation terminée",
+		slog.String("suite", report.Suite),
+		slog.String("prompt", report.PromptVersion),
+		slog.Float64("score", report.Score),
+	)
+	return 0
+}
+
+// writeReport crée le fichier path et y écrit le rapport via la fonction donnée.
+func writeReport(path string, write func(w io.Writer) error) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	if err := write(f); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
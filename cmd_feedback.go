@@ -0,0 +1,108 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"otom-ai/conversations"
+	"slices"
+	"time"
+)
+
+// feedbackLine est une ligne de l'export : la réponse notée, son contexte et le bilan des votes,
+// sans les identifiants Discord de l'auteur, des membres cités dans l'historique ni des votants. Les champs author, history et question reprennent ceux
+// des scénarios d'évaluation (evals/*.yaml).
+type feedbackLine struct {
+	conversations.Answer
+	AuthorID any `json:"author_id,omitempty"` // Masque Answer.AuthorID
+	Votes    any `json:"votes,omitempty"`     // Masque Answer.Votes
+	Up       int `json:"up"`
+	Down     int `json:"down"`
+	Score    int `json:"score"` // up - down
+}
+
+// runFeedback implémente la commande "otom-ai feedback" (export des avis sur les réponses du bot).
+func runFeedback(logger *slog.Logger, args []string) int {
+	const usage = "Usage : otom-ai feedback export [-i data/feedback.json] [-o avis.jsonl] [-rated up|down|any|all] [-since 168h]"
+	if len(args) == 0 || args[0] != "export" {
+		fmt.Fprintln(os.Stderr, usage)
+		return 2
+	}
+
+	fs := flag.NewFlagSet("feedback export", flag.ContinueOnError)
+	in := fs.String("i", "data/feedback.json", "fichier des avis (feedback.json du dossier data_dir)")
+	out := fs.String("o", "", "fichier JSONL de sortie (défaut : sortie standard)")
+	rated := fs.String("rated", "any", "réponses exportées : up (bilan positif), down (négatif), any (au moins un vote) ou all")
+	since := fs.Duration("since", 0, "n'exporter que les réponses plus récentes (0 = toutes)")
+	if err := fs.Parse(args[1:]); err != nil {
+		return 2
+	}
+	keep, ok := map[string]func(l feedbackLine) bool{
+		"up":   func(l feedbackLine) bool { return l.Score > 0 },
+		"down": func(l feedbackLine) bool { return l.Score < 0 },
+		"any":  func(l feedbackLine) bool { return l.Up+l.Down > 0 },
+		"all":  func(feedbackLine) bool { return true },
+	}[*rated]
+	if !ok || fs.NArg() != 0 {
+		fmt.Fprintln(os.Stderr, usage)
+		return 2
+	}
+
+	if _, err := os.Stat(*in); err != nil {
+		logger.Error("Fichier des avis introuvable", slog.String("file", *in), slog.String("error", err.Error()))
+		return 1
+	}
+	store, err := conversations.OpenFeedback(*in)
+	if err != nil {
+		logger.Error("Lecture des avis impossible", slog.String("error", err.Error()))
+		return 1
+	}
+
+	var w io.Writer = os.Stdout
+	if *out != "" {
+		f, err := os.Create(*out)
+		if err != nil {
+			logger.Error("Création du fichier de sortie impossible", slog.String("error", err.Error()))
+			return 1
+		}
+		defer f.Close()
+		w = f
+	}
+	buf := bufio.NewWriter(w)
+	enc := json.NewEncoder(buf)
+	enc.SetEscapeHTML(false)
+
+	exported := 0
+	for _, a := range store.Answers() {
+		if *since > 0 && time.Since(a.At) > *since {
+			continue
+		}
+		up, down := a.Score()
+		a.History = slices.Clone(a.History)
+		for i := range a.History {
+			a.History[i].AuthorID = "" // Pas d'identifiant Discord dans l'export
+		}
+		line := feedbackLine{Answer: a, Up: up, Down: down, Score: up - down}
+		if !keep(line) {
+			continue
+		}
+		if err := enc.Encode(line); err != nil {
+			logger.Error("Écriture de l'export impossible", slog.String("error", err.Error()))
+			return 1
+		}
+		exported++
+	}
+	if err := buf.Flush(); err != nil {
+		logger.Error("Écriture de l'export impossible", slog.String("error", err.Error()))
+		return 1
+	}
+
+	if *out != "" { // Sur la sortie standard, le log se mêlerait au JSONL
+		logger.Info("Avis exportés", slog.String("file", *out), slog.Int("answers", exported))
+	}
+	return 0
+}
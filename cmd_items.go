@@ -0,0 +1,63 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"log/slog"
+	"os"
+	"otom-ai/items"
+	"path/filepath"
+)
+
+// runItems implémente la commande "otom-ai items" (gestion de la base locale d'objets).
+func runItems(logger *slog.Logger, args []string) int {
+	if len(args) == 0 || args[0] != "import" {
+		fmt.Fprintln(os.Stderr, "Usage : otom-ai items import [-o data/items.json] <dump.json>")
+		return 2
+	}
+
+	fs := flag.NewFlagSet("items import", flag.ContinueOnError)
+	out := fs.String("o", "data/items.json", "fichier de sortie (à référencer dans items.file)")
+	if err := fs.Parse(args[1:]); err != nil {
+		return 2
+	}
+	if fs.NArg() != 1 {
+		fmt.Fprintln(os.Stderr, "Usage : otom-ai items import [-o data/items.json] <dump.json>")
+		return 2
+	}
+
+	f, err := os.Open(fs.Arg(0))
+	if err != nil {
+		logger.Error("Ouverture du dump impossible", slog.String("error", err.Error()))
+		return 1
+	}
+	defer f.Close()
+
+	dump, err := items.Import(f)
+	if err != nil {
+		logger.Error("Import du dump impossible", slog.String("error", err.Error()))
+		return 1
+	}
+
+	data, err := json.MarshalIndent(dump, "", "  ")
+	if err != nil {
+		logger.Error("Sérialisation de la base impossible", slog.String("error", err.Error()))
+		return 1
+	}
+	if err := os.MkdirAll(filepath.Dir(*out), 0o755); err != nil {
+		logger.Error("Création du dossier de sortie impossible", slog.String("error", err.Error()))
+		return 1
+	}
+	if err := os.WriteFile(*out, data, 0o644); err != nil {
+		logger.Error("Écriture de la base impossible", slog.String("error", err.Error()))
+		return 1
+	}
+
+	logger.Info("Base d'objets importée",
+		slog.String("file", *out),
+		slog.Int("items", len(dump.Items)),
+		slog.Int("sets", len(dump.Sets)),
+	)
+	return 0
+}
@@ -1,47 +1,883 @@
+// Package config charge la configuration du bot depuis un fichier YAML optionnel,
+// complété et surchargé par les variables d'environnement (et le fichier .env).
 package config
 
 import (
+	"bytes"
+	"cmp"
+	"errors"
 	"fmt"
+	"io"
 	"os"
+	"otom-ai/logging"
+	"otom-ai/persona"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
 
 	"github.com/joho/godotenv"
+	"gopkg.in/yaml.v3"
 )
 
+// DefaultFile est le fichier de configuration lu si CONFIG_FILE n'est pas défini.
+const DefaultFile = "config.yaml"
+
+// DefaultPersona est le nom du persona intégré au bot (persona.DefaultPrompt).
+const DefaultPersona = "default"
+
+// Config regroupe toute la configuration du bot.
 type Config struct {
-	DiscordToken  string // Token d'authentification Discord
-	DeepSeekKey   string // Clé API DeepSeek (compatible OpenAI)
-	TavilyKey     string // Clé API Tavily pour la recherche web
-	DeepSeekURL   string // URL de base de l'API DeepSeek
-	DeepSeekModel string // Modèle DeepSeek à utiliser
+	Discord    DiscordConfig            `yaml:"discord"`
+	AI         AIConfig                 `yaml:"ai"`
+	Search     SearchConfig             `yaml:"search"`
+	Fetch      FetchConfig              `yaml:"fetch"`
+	RateLimit  RateLimitConfig          `yaml:"rate_limit"`
+	History    HistoryConfig            `yaml:"history"`
+	Persona    string                   `yaml:"persona"`  // Nom du persona actif
+	Personas   map[string]PersonaConfig `yaml:"personas"` // Personas disponibles (en plus de "default")
+	Channels   ChannelsConfig           `yaml:"channels"`
+	Bots       BotsConfig               `yaml:"bots"`
+	Cassette   CassetteConfig           `yaml:"cassette"`
+	Logging    LoggingConfig            `yaml:"logging"`
+	Items      ItemsConfig              `yaml:"items"`
+	Almanax    AlmanaxConfig            `yaml:"almanax"`
+	XP         XPConfig                 `yaml:"xp"`
+	Knowledge  KnowledgeConfig          `yaml:"knowledge"`
+	LFG        LFGConfig                `yaml:"lfg"`
+	DM         DMConfig                 `yaml:"dm"`
+	Feedback   FeedbackConfig           `yaml:"feedback"`
+	Moderation ModerationConfig         `yaml:"moderation"`
+	Audit      AuditConfig              `yaml:"audit"`
+	Timezone   string                   `yaml:"timezone"` // Fuseau horaire des annonces et du contexte daté
+	DataDir    string                   `yaml:"data_dir"` // Dossier des données persistées (prix, événements...)
+	Guilds     map[string]GuildConfig   `yaml:"guilds"`   // Réglages par serveur Discord (clé : ID du serveur)
+
+	path string // Fichier d'où provient la configuration ("" si environnement seul)
+}
+
+// DiscordConfig contient les paramètres de connexion Discord.
+type DiscordConfig struct {
+	Token     Secret   `yaml:"token"`     // Token d'authentification Discord
+	Status    string   `yaml:"status"`    // Statut "En train de jouer à..."
+	Operators []string `yaml:"operators"` // IDs des opérateurs du bot (commandes qui touchent tout le processus)
+}
+
+// AIConfig contient les fournisseurs LLM disponibles et le fournisseur actif.
+type AIConfig struct {
+	Provider  string                    `yaml:"provider"`  // Nom du fournisseur actif
+	Providers map[string]ProviderConfig `yaml:"providers"` // Fournisseurs compatibles OpenAI
+	Timeout   time.Duration             `yaml:"timeout"`   // Durée max de traitement d'un message (tool calling compris)
+}
+
+// ProviderConfig décrit un fournisseur LLM compatible OpenAI.
+type ProviderConfig struct {
+	URL         string        `yaml:"url"`         // URL de l'endpoint chat/completions
+	APIKey      Secret        `yaml:"api_key"`     // Clé API
+	Model       string        `yaml:"model"`       // Modèle à utiliser
+	Temperature *float64      `yaml:"temperature"` // Entre 0.0 et 1.5 (absente = 0.2)
+	Timeout     time.Duration `yaml:"timeout"`     // Timeout HTTP d'un appel
+}
+
+// SearchConfig contient les paramètres de la recherche web.
+type SearchConfig struct {
+	TavilyKey  Secret        `yaml:"tavily_api_key"` // Clé API Tavily
+	Timeout    time.Duration `yaml:"timeout"`        // Timeout HTTP d'une recherche
+	MaxResults int           `yaml:"max_results"`    // Nombre de résultats transmis au LLM
+}
+
+// FetchConfig paramètre la lecture de pages web par le LLM (outil fetch_url).
+type FetchConfig struct {
+	AllowedDomains []string      `yaml:"allowed_domains"` // Domaines autorisés, sous-domaines compris (vide = outil désactivé)
+	MaxKB          int64         `yaml:"max_kb"`          // Taille maximale d'une page téléchargée
+	MaxTokens      int           `yaml:"max_tokens"`      // Budget approximatif du texte transmis au LLM
+	Timeout        time.Duration `yaml:"timeout"`         // Timeout HTTP d'un téléchargement
+	CacheTTL       time.Duration `yaml:"cache_ttl"`       // Durée de conservation d'une page en cache
+}
+
+// Enabled indique si l'outil fetch_url est disponible.
+func (f FetchConfig) Enabled() bool {
+	return len(f.AllowedDomains) > 0
+}
+
+// MaxChars convertit le budget en tokens en nombre de caractères (≈ 4 caractères par token).
+func (f FetchConfig) MaxChars() int {
+	return f.MaxTokens * 4
+}
+
+// RateLimitConfig paramètre le rate limiter par utilisateur.
+type RateLimitConfig struct {
+	Requests int           `yaml:"requests"` // Nombre max de requêtes par fenêtre
+	Window   time.Duration `yaml:"window"`   // Durée de la fenêtre glissante
+}
+
+// HistoryConfig paramètre le contexte conversationnel.
+type HistoryConfig struct {
+	Depth int `yaml:"depth"` // Nombre de messages du channel injectés dans le contexte
+}
+
+// PersonaConfig décrit un prompt système, en ligne ou dans un fichier.
+type PersonaConfig struct {
+	Prompt     string `yaml:"prompt"`
+	PromptFile string `yaml:"prompt_file"` // Chemin relatif au fichier de configuration
+}
+
+// ChannelsConfig restreint les channels où le bot répond.
+type ChannelsConfig struct {
+	Allowed []string `yaml:"allowed"` // Si non vide, seuls ces channels sont autorisés
+	Denied  []string `yaml:"denied"`  // Channels toujours ignorés
 }
 
+// BotsConfig règle la réponse aux messages des autres bots et des webhooks (ignorés par défaut).
+type BotsConfig struct {
+	Allowed  []string        `yaml:"allowed"`  // IDs des bots auxquels le bot répond quand ils le mentionnent
+	Webhooks bool            `yaml:"webhooks"` // Répondre aux webhooks (ex: relais PluralKit, Tupperbox)
+	Loop     RateLimitConfig `yaml:"loop"`     // Échanges maximum avec un même bot dans un channel (anti ping-pong)
+}
+
+// CassetteConfig paramètre l'enregistrement/rejeu des échanges HTTP.
+type CassetteConfig struct {
+	Mode string `yaml:"mode"` // "" (désactivé), "record" ou "replay"
+	Dir  string `yaml:"dir"`  // Dossier (ou fichier en replay) des cassettes
+}
+
+// ItemsConfig paramètre la base locale des objets Dofus.
+type ItemsConfig struct {
+	File string `yaml:"file"` // Base importée (vide = base embarquée)
+}
+
+// AlmanaxConfig paramètre le jeu de données de l'Almanax.
+type AlmanaxConfig struct {
+	File string `yaml:"file"` // Jeu de données local (vide = annonces et outil désactivés)
+}
+
+// XPConfig paramètre la table d'expérience du calculateur de niveaux.
+type XPConfig struct {
+	File string `yaml:"file"` // Table locale (vide = calculateur d'expérience désactivé)
+}
+
+// KnowledgeConfig paramètre la base de connaissances locale (guides, FAQ) interrogée par le LLM.
+type KnowledgeConfig struct {
+	DocsDir    string           `yaml:"docs_dir"`   // Dossier des documents Markdown/HTML à indexer
+	IndexFile  string           `yaml:"index_file"` // Index vectoriel persisté
+	TopK       int              `yaml:"top_k"`      // Nombre de passages transmis au LLM
+	Embeddings EmbeddingsConfig `yaml:"embeddings"`
+}
+
+// EmbeddingsConfig décrit un endpoint d'embeddings compatible OpenAI (fournisseur ou serveur local).
+type EmbeddingsConfig struct {
+	URL     string        `yaml:"url"`     // URL de l'endpoint embeddings (vide = base de connaissances désactivée)
+	APIKey  Secret        `yaml:"api_key"` // Clé API (facultative pour un serveur local)
+	Model   string        `yaml:"model"`
+	Timeout time.Duration `yaml:"timeout"` // Timeout HTTP d'un appel
+}
+
+// Enabled indique si la base de connaissances est configurée.
+func (k KnowledgeConfig) Enabled() bool {
+	return k.Embeddings.URL != ""
+}
+
+// LFGConfig paramètre l'organisation des sorties de groupe (/donjon).
+type LFGConfig struct {
+	Reminder time.Duration `yaml:"reminder"` // Délai du rappel avant le début (0 = pas de rappel)
+}
+
+// DMConfig paramètre les conversations en message privé, plus encadrées que sur les serveurs.
+type DMConfig struct {
+	Enabled            bool            `yaml:"enabled"`
+	RateLimit          RateLimitConfig `yaml:"rate_limit"`           // Plus strict que rate_limit
+	DailyQuota         int             `yaml:"daily_quota"`          // Messages par utilisateur sur 24h glissantes
+	Memory             int             `yaml:"memory"`               // Échanges mémorisés par utilisateur (contexte)
+	MemoryTTL          time.Duration   `yaml:"memory_ttl"`           // Oubli des échanges plus anciens
+	RequireSharedGuild bool            `yaml:"require_shared_guild"` // Réservé aux membres d'un serveur où est le bot
+}
+
+// FeedbackConfig paramètre la collecte des avis (réactions 👍/👎) sur les réponses du bot.
+type FeedbackConfig struct {
+	Enabled   bool          `yaml:"enabled"`
+	Retention time.Duration `yaml:"retention"` // Oubli des réponses plus anciennes (question et historique compris)
+	Max       int           `yaml:"max"`       // Nombre maximal de réponses conservées
+}
+
+// Actions de modération sur un message reçu ou une réponse du bot signalés.
+const (
+	ModerationRefuse = "refuse" // Message refusé (réponse thématique), réponse remplacée
+	ModerationRedact = "redact" // Passages interdits masqués (refus si seul le classifieur a signalé)
+	ModerationLog    = "log"    // Simple signalement, le contenu passe
+)
+
+// ModerationConfig paramètre le filtrage des messages reçus et des réponses du bot.
+// Chaque serveur peut compléter les listes et changer les actions (guilds.<id>.moderation).
+type ModerationConfig struct {
+	Words      []string         `yaml:"words"`    // Mots interdits (mots entiers, insensibles à la casse)
+	Patterns   []string         `yaml:"patterns"` // Expressions régulières interdites
+	Input      string           `yaml:"input"`    // Action sur un message reçu (refuse, redact ou log)
+	Output     string           `yaml:"output"`   // Action sur une réponse du bot (refuse, redact ou log)
+	Classifier ClassifierConfig `yaml:"classifier"`
+}
+
+// ClassifierConfig paramètre le classifieur appliqué en plus des listes.
+type ClassifierConfig struct {
+	Type    string        `yaml:"type"`    // "" (aucun), "llm" (fournisseur actif) ou "endpoint"
+	URL     string        `yaml:"url"`     // Endpoint compatible OpenAI /v1/moderations (type endpoint)
+	APIKey  Secret        `yaml:"api_key"` // Clé API (facultative pour un serveur local)
+	Model   string        `yaml:"model"`
+	Timeout time.Duration `yaml:"timeout"`
+}
+
+// Événements du journal d'audit publiés dans le channel des modérateurs.
+const (
+	AuditDeletions  = "deletions"  // Messages supprimés
+	AuditEdits      = "edits"      // Messages modifiés
+	AuditRateLimit  = "rate_limit" // Utilisateurs bloqués par le rate limit
+	AuditModeration = "moderation" // Contenus signalés par la modération
+	AuditAdmin      = "admin"      // Réglages modifiés par les administrateurs
+)
+
+// AuditEvents liste les événements du journal d'audit.
+var AuditEvents = []string{AuditDeletions, AuditEdits, AuditRateLimit, AuditModeration, AuditAdmin}
+
+// AuditConfig paramètre le journal d'audit, publié dans le channel des modérateurs de chaque serveur
+// (guilds.<id>.audit). Le contenu des messages n'est gardé qu'en mémoire, jamais sur disque.
+type AuditConfig struct {
+	Events        []string      `yaml:"events"`         // Événements publiés par défaut
+	Content       bool          `yaml:"content"`        // Contenu des messages supprimés ou modifiés repris dans le journal (désactivé par défaut)
+	CacheTTL      time.Duration `yaml:"cache_ttl"`      // Durée de conservation en mémoire du contenu des messages
+	CacheSize     int           `yaml:"cache_size"`     // Messages conservés en mémoire, tous serveurs confondus
+	Retention     time.Duration `yaml:"retention"`      // Événements effacés du channel d'audit après ce délai (0 = conservés)
+	AbuseCooldown time.Duration `yaml:"abuse_cooldown"` // Délai minimal entre deux signalements de rate limit d'un même utilisateur
+}
+
+// GuildAudit active le journal d'audit sur un serveur.
+type GuildAudit struct {
+	Channel  string   `yaml:"channel"`           // Channel des modérateurs (vide = journal désactivé)
+	Events   []string `yaml:"events"`            // Vide = audit.events
+	Excluded []string `yaml:"excluded_channels"` // Channels jamais journalisés (ex: salons privés)
+}
+
+// GuildModeration complète la modération globale sur un serveur.
+type GuildModeration struct {
+	Words      []string `yaml:"words"`
+	Patterns   []string `yaml:"patterns"`
+	Input      string   `yaml:"input"`       // Vide = action globale
+	Output     string   `yaml:"output"`      // Vide = action globale
+	LogChannel string   `yaml:"log_channel"` // Channel des modérateurs recevant les signalements
+}
+
+// GuildConfig regroupe les réglages propres à un serveur Discord.
+type GuildConfig struct {
+	Server     string              `yaml:"server"` // Serveur de jeu Dofus par défaut (prix HDV)
+	Almanax    AlmanaxAnnouncement `yaml:"almanax"`
+	Channels   GuildChannels       `yaml:"channels"`
+	Moderation GuildModeration     `yaml:"moderation"`
+	Audit      GuildAudit          `yaml:"audit"`
+}
+
+// GuildChannels règle où et comment le bot répond sur un serveur, en plus des règles globales
+// (channels). Un fil de discussion suit les règles de son channel parent.
+type GuildChannels struct {
+	Allowed     []string      `yaml:"allowed"`      // Si non vide, seuls ces channels sont autorisés
+	Denied      []string      `yaml:"denied"`       // Channels toujours ignorés
+	Free        []string      `yaml:"free"`         // Réponse sans mention (channel dédié au bot)
+	ThreadsOnly []string      `yaml:"threads_only"` // Réponse uniquement dans les fils de ces channels
+	AutoThread  []string      `yaml:"auto_thread"`  // Une mention ouvre un fil où la conversation se poursuit sans mention
+	ThreadIdle  time.Duration `yaml:"thread_idle"`  // Archivage automatique des fils après inactivité (1h, 24h, 72h ou 168h)
+	Lurk        LurkConfig    `yaml:"lurk"`
+}
+
+// ThreadArchiveMinutes retourne la durée d'archivage des fils en minutes, au format attendu par Discord.
+func (g GuildChannels) ThreadArchiveMinutes() int {
+	return int(g.ThreadIdle.Minutes())
+}
+
+// LurkConfig paramètre les interventions spontanées du bot dans les conversations.
+type LurkConfig struct {
+	Probability float64        `yaml:"probability"` // Probabilité de répondre à un message sans mention (0 = désactivé, max 1)
+	Cooldown    *time.Duration `yaml:"cooldown"`    // Délai minimal entre deux interventions dans un même channel (absent = 30m)
+	MinWords    *int           `yaml:"min_words"`   // Messages plus courts ignorés (absent = 5)
+}
+
+// AlmanaxAnnouncement paramètre l'annonce quotidienne de l'Almanax sur un serveur.
+type AlmanaxAnnouncement struct {
+	Channel string `yaml:"channel"` // Channel d'annonce (vide = désactivé)
+	Time    string `yaml:"time"`    // Heure d'annonce "HH:MM" (défaut 08:00)
+}
+
+// LoggingConfig paramètre les logs du bot (défini par le package logging, qui le valide).
+type LoggingConfig = logging.Config
+
+// LogFileConfig paramètre l'écriture des logs dans un fichier avec rotation.
+type LogFileConfig = logging.FileConfig
+
+// Load charge la configuration : valeurs par défaut, puis fichier YAML (CONFIG_FILE ou
+// config.yaml s'il existe), puis surcharges par variables d'environnement, et enfin validation.
 func Load() (*Config, error) {
 	_ = godotenv.Load()
 
-	cfg := &Config{
-		DiscordToken:  os.Getenv("DISCORD_TOKEN"),
-		DeepSeekKey:   os.Getenv("DEEPSEEK_API_KEY"),
-		TavilyKey:     os.Getenv("TAVILY_API_KEY"),
-		DeepSeekURL:   os.Getenv("DEEPSEEK_URL"),
-		DeepSeekModel: os.Getenv("DEEPSEEK_MODEL"),
+	cfg := defaults()
+
+	path := os.Getenv("CONFIG_FILE")
+	if path == "" {
+		if _, err := os.Stat(DefaultFile); err == nil {
+			path = DefaultFile
+		}
 	}
+	if path != "" {
+		if err := cfg.loadFile(path); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := cfg.applyEnv(); err != nil {
+		return nil, err
+	}
+
+	if err := cfg.validate(); err != nil {
+		return nil, err
+	}
+	return cfg, nil
+}
+
+// Path retourne le fichier de configuration utilisé ("" si environnement seul).
+func (c *Config) Path() string {
+	return c.path
+}
+
+// Location retourne le fuseau horaire configuré (validé au chargement).
+func (c *Config) Location() *time.Location {
+	loc, err := time.LoadLocation(c.Timezone)
+	if err != nil {
+		return time.UTC
+	}
+	return loc
+}
+
+// Secrets retourne la valeur en clair de tous les secrets configurés (pour masquage).
+func (c *Config) Secrets() []string {
+	secrets := []string{c.Discord.Token.Value(), c.Search.TavilyKey.Value(), c.Knowledge.Embeddings.APIKey.Value(), c.Moderation.Classifier.APIKey.Value()}
+	for _, p := range c.AI.Providers {
+		secrets = append(secrets, p.APIKey.Value())
+	}
+	return secrets
+}
+
+// IsOperator indique si un utilisateur Discord est un opérateur du bot (discord.operators).
+// Les administrateurs d'un serveur ne gèrent que leur serveur : les réglages du processus
+// (niveaux de log...) sont réservés aux opérateurs.
+func (c *Config) IsOperator(userID string) bool {
+	return userID != "" && slices.Contains(c.Discord.Operators, userID)
+}
+
+// DataFile retourne le chemin d'un fichier de données persistées.
+func (c *Config) DataFile(name string) string {
+	return filepath.Join(c.DataDir, name)
+}
+
+// GameServer retourne le serveur de jeu par défaut d'un serveur Discord ("" si non configuré).
+func (c *Config) GameServer(guildID string) string {
+	return c.Guilds[guildID].Server
+}
+
+// GuildChannels retourne les règles de channels d'un serveur (vides s'il n'est pas configuré).
+func (c *Config) GuildChannels(guildID string) GuildChannels {
+	return c.Guilds[guildID].Channels
+}
+
+// GuildModeration retourne la modération d'un serveur : listes globales complétées par celles du
+// serveur, actions du serveur à défaut des actions globales.
+func (c *Config) GuildModeration(guildID string) GuildModeration {
+	g := c.Guilds[guildID].Moderation
+	return GuildModeration{
+		Words:      slices.Concat(c.Moderation.Words, g.Words),
+		Patterns:   slices.Concat(c.Moderation.Patterns, g.Patterns),
+		Input:      cmp.Or(g.Input, c.Moderation.Input),
+		Output:     cmp.Or(g.Output, c.Moderation.Output),
+		LogChannel: g.LogChannel,
+	}
+}
+
+// AuditChannel retourne le channel d'audit d'un serveur pour un événement ("" s'il n'y est pas publié).
+// À défaut, les signalements de modération vont dans guilds.<id>.moderation.log_channel.
+func (c *Config) AuditChannel(guildID, event string) string {
+	g := c.Guilds[guildID]
+	events := g.Audit.Events
+	if len(events) == 0 {
+		events = c.Audit.Events
+	}
+	if g.Audit.Channel != "" && slices.Contains(events, event) {
+		return g.Audit.Channel
+	}
+	if event == AuditModeration {
+		return g.Moderation.LogChannel
+	}
+	return ""
+}
+
+// Provider retourne la configuration du fournisseur LLM actif.
+func (c *Config) Provider() ProviderConfig {
+	return c.AI.Providers[c.AI.Provider]
+}
+
+// SystemPrompt retourne le prompt système du persona actif.
+func (c *Config) SystemPrompt() string {
+	if p, ok := c.Personas[c.Persona]; ok && p.Prompt != "" {
+		return p.Prompt
+	}
+	return persona.DefaultPrompt
+}
+
+// defaults retourne la configuration par défaut (comportement historique du bot).
+func defaults() *Config {
+	return &Config{
+		Discord: DiscordConfig{Status: "Répondre aux noob du Zaap"},
+		AI: AIConfig{
+			Provider:  "deepseek",
+			Providers: map[string]ProviderConfig{},
+			Timeout:   90 * time.Second,
+		},
+		Search:    SearchConfig{Timeout: 5 * time.Second, MaxResults: 3},
+		RateLimit: RateLimitConfig{Requests: 5, Window: 60 * time.Second}, // 5 requêtes/minute/utilisateur
+		History:   HistoryConfig{Depth: 20},
+		Bots:      BotsConfig{Loop: RateLimitConfig{Requests: 3, Window: 5 * time.Minute}},
+		Persona:   DefaultPersona,
+		Personas:  map[string]PersonaConfig{},
+		Cassette:  CassetteConfig{Dir: "cassettes"},
+		Timezone:  "Europe/Paris",
+		DataDir:   "data",
+		Fetch: FetchConfig{
+			AllowedDomains: []string{"dofus.com", "ankama.com", "dofuspourlesnoobs.com", "dofusbook.net", "dofusdb.fr", "dofus.fandom.com"},
+			MaxKB:          2048,
+			MaxTokens:      2000,
+			Timeout:        10 * time.Second,
+			CacheTTL:       time.Hour,
+		},
+		Knowledge: KnowledgeConfig{
+			DocsDir:    "knowledge",
+			IndexFile:  "data/knowledge.json",
+			TopK:       4,
+			Embeddings: EmbeddingsConfig{Timeout: 30 * time.Second},
+		},
+		LFG: LFGConfig{Reminder: 30 * time.Minute},
+		DM: DMConfig{
+			RateLimit:          RateLimitConfig{Requests: 3, Window: 60 * time.Second},
+			DailyQuota:         30,
+			Memory:             10,
+			MemoryTTL:          7 * 24 * time.Hour,
+			RequireSharedGuild: true,
+		},
+		Feedback: FeedbackConfig{Retention: 30 * 24 * time.Hour, Max: 5000},
+		Moderation: ModerationConfig{
+			Input:      ModerationRefuse,
+			Output:     ModerationRedact,
+			Classifier: ClassifierConfig{Timeout: 10 * time.Second},
+		},
+		Audit: AuditConfig{
+			Events:        slices.Clone(AuditEvents),
+			Content:       false,
+			CacheTTL:      24 * time.Hour,
+			CacheSize:     5000,
+			Retention:     30 * 24 * time.Hour,
+			AbuseCooldown: 10 * time.Minute,
+		},
+		Logging: LoggingConfig{
+			Format: "text",
+			Level:  "info",
+			File:   LogFileConfig{MaxSizeMB: 10, MaxBackups: 5},
+		},
+	}
+}
+
+// envRef repère les références ${VAR} à interpoler dans le fichier de configuration.
+var envRef = regexp.MustCompile(`\$\{([A-Za-z_][A-Za-z0-9_]*)\}`)
+
+// loadFile lit le fichier YAML, interpole les ${VAR} et résout les prompts en fichier.
+func (c *Config) loadFile(path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("lecture du fichier de configuration: %w", err)
+	}
+
+	var root yaml.Node
+	if err := yaml.Unmarshal(data, &root); err != nil {
+		return fmt.Errorf("%s: YAML invalide: %w", path, err)
+	}
+	if missing := interpolate(&root); len(missing) > 0 {
+		return fmt.Errorf("%s: variables d'environnement référencées mais non définies: %v", path, missing)
+	}
+	if root.Kind == 0 { // Fichier vide
+		c.path = path
+		return nil
+	}
+
+	// Le document interpolé est réencodé : Node.Decode ne sait pas refuser les clés inconnues
+	var expanded bytes.Buffer
+	enc := yaml.NewEncoder(&expanded)
+	enc.SetIndent(2)
+	if err := enc.Encode(&root); err != nil {
+		return fmt.Errorf("%s: YAML invalide: %w", path, err)
+	}
+	dec := yaml.NewDecoder(&expanded)
+	dec.KnownFields(true) // Une faute de frappe dans une clé doit être signalée, pas ignorée
+	if err := dec.Decode(c); err != nil && !errors.Is(err, io.EOF) {
+		return fmt.Errorf("%s: YAML invalide: %w", path, err)
+	}
+
+	for name, p := range c.Personas {
+		if p.PromptFile == "" {
+			continue
+		}
+		promptPath := p.PromptFile
+		if !filepath.IsAbs(promptPath) {
+			promptPath = filepath.Join(filepath.Dir(path), promptPath)
+		}
+		prompt, err := os.ReadFile(promptPath)
+		if err != nil {
+			return fmt.Errorf("persona %q: lecture du prompt: %w", name, err)
+		}
+		p.Prompt = string(prompt)
+		c.Personas[name] = p
+	}
+
+	c.path = path
+	return nil
+}
+
+// interpolate remplace les ${VAR} dans les valeurs du document YAML et retourne les variables
+// non définies. Une valeur d'environnement reste une valeur : elle ne peut pas ajouter de clé
+// ni changer la structure du fichier. Sans guillemets, son type est déduit après interpolation
+// (port: ${PORT} reste un nombre).
+func interpolate(n *yaml.Node) []string {
+	var missing []string
+	if n.Kind == yaml.ScalarNode && envRef.MatchString(n.Value) {
+		n.Value = envRef.ReplaceAllStringFunc(n.Value, func(ref string) string {
+			name := envRef.FindStringSubmatch(ref)[1]
+			value, ok, err := lookupEnv(name)
+			if err != nil || !ok {
+				missing = append(missing, name)
+			}
+			return value
+		})
+		if n.Style == 0 {
+			n.Tag = ""
+		}
+	}
+	for _, child := range n.Content {
+		missing = append(missing, interpolate(child)...)
+	}
+	return missing
+}
+
+// applyEnv applique les variables d'environnement historiques, prioritaires sur le fichier.
+// Chaque secret peut aussi être fourni via un fichier (variante *_FILE, ex: Docker secrets).
+func (c *Config) applyEnv() error {
+	p := c.AI.Providers["deepseek"]
+	errs := []error{
+		setSecret(&c.Discord.Token, "DISCORD_TOKEN"),
+		setSecret(&c.Search.TavilyKey, "TAVILY_API_KEY"),
+		// Les variables DEEPSEEK_* alimentent le fournisseur "deepseek"
+		setSecret(&p.APIKey, "DEEPSEEK_API_KEY"),
+		setSecret(&c.Knowledge.Embeddings.APIKey, "EMBEDDINGS_API_KEY"),
+		setSecret(&c.Moderation.Classifier.APIKey, "MODERATION_API_KEY"),
+	}
+	setString(&p.URL, "DEEPSEEK_URL")
+	setString(&p.Model, "DEEPSEEK_MODEL")
+	if p != (ProviderConfig{}) {
+		c.AI.Providers["deepseek"] = p
+	}
+
+	setString(&c.Cassette.Mode, "HTTP_CASSETTE_MODE")
+	setString(&c.Cassette.Dir, "HTTP_CASSETTE_DIR")
+	setString(&c.AI.Provider, "AI_PROVIDER")
+	setString(&c.Persona, "PERSONA")
+	setString(&c.Logging.Format, "LOG_FORMAT")
+	setString(&c.Logging.Level, "LOG_LEVEL")
+	setString(&c.Items.File, "ITEMS_FILE")
+	setString(&c.Almanax.File, "ALMANAX_FILE")
+	setString(&c.XP.File, "XP_FILE")
+	setString(&c.DataDir, "DATA_DIR")
+	setString(&c.Knowledge.DocsDir, "KNOWLEDGE_DOCS_DIR")
+	setString(&c.Knowledge.Embeddings.URL, "EMBEDDINGS_URL")
+	setString(&c.Knowledge.Embeddings.Model, "EMBEDDINGS_MODEL")
+
+	// Heure d'annonce de l'Almanax et rythme des interventions spontanées par défaut
+	for id, g := range c.Guilds {
+		if g.Almanax.Channel != "" && g.Almanax.Time == "" {
+			g.Almanax.Time = "08:00"
+		}
+		// Un 0 explicitement configuré (pas de délai, pas de minimum) est conservé
+		if g.Channels.Lurk.Cooldown == nil {
+			cooldown := 30 * time.Minute
+			g.Channels.Lurk.Cooldown = &cooldown
+		}
+		if g.Channels.Lurk.MinWords == nil {
+			minWords := 5
+			g.Channels.Lurk.MinWords = &minWords
+		}
+		if g.Channels.ThreadIdle == 0 {
+			g.Channels.ThreadIdle = time.Hour
+		}
+		c.Guilds[id] = g
+	}
+
+	// Valeurs par défaut des fournisseurs déclarés sans température ni timeout
+	// (une température de 0 explicitement configurée est conservée)
+	for name, p := range c.AI.Providers {
+		if p.Temperature == nil {
+			temperature := 0.2
+			p.Temperature = &temperature
+		}
+		if p.Timeout == 0 {
+			p.Timeout = 60 * time.Second // Timeout généreux pour les réponses LLM
+		}
+		c.AI.Providers[name] = p
+	}
+
+	return errors.Join(errs...)
+}
+
+// validateModeration vérifie les expressions régulières et les actions d'une section de modération
+// (actions facultatives pour un serveur, qui hérite alors des actions globales).
+func validateModeration(section string, patterns []string, input, output string, optional bool) []error {
+	var errs []error
+	for _, p := range patterns {
+		if _, err := regexp.Compile(p); err != nil {
+			errs = append(errs, fmt.Errorf("%s.patterns: expression régulière %q invalide: %w", section, p, err))
+		}
+	}
+	actions := []string{ModerationRefuse, ModerationRedact, ModerationLog}
+	for _, a := range [][2]string{{"input", input}, {"output", output}} {
+		if a[1] == "" && optional {
+			continue
+		}
+		if !slices.Contains(actions, a[1]) {
+			errs = append(errs, fmt.Errorf("%s.%s invalide %q (refuse, redact ou log)", section, a[0], a[1]))
+		}
+	}
+	return errs
+}
+
+// validateAuditEvents vérifie les noms d'événements d'une liste du journal d'audit.
+func validateAuditEvents(section string, events []string) []error {
+	var errs []error
+	for _, e := range events {
+		if !slices.Contains(AuditEvents, e) {
+			errs = append(errs, fmt.Errorf("%s: événement inconnu %q (%s)", section, e, strings.Join(AuditEvents, ", ")))
+		}
+	}
+	return errs
+}
+
+// setString remplace *dst par la variable d'environnement name si elle est définie.
+func setString(dst *string, name string) {
+	if v := os.Getenv(name); v != "" {
+		*dst = v
+	}
+}
+
+// setSecret remplace *dst par la variable name, ou par le contenu du fichier name_FILE.
+func setSecret(dst *Secret, name string) error {
+	v, ok, err := lookupEnv(name)
+	if err != nil {
+		return err
+	}
+	if ok && v != "" {
+		*dst = Secret(v)
+	}
+	return nil
+}
+
+// lookupEnv lit la variable name, ou à défaut le fichier désigné par name_FILE
+// (espaces et retour à la ligne final supprimés).
+func lookupEnv(name string) (string, bool, error) {
+	if v, ok := os.LookupEnv(name); ok && v != "" {
+		return v, true, nil
+	}
+	path := os.Getenv(name + "_FILE")
+	if path == "" {
+		return "", false, nil
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", false, fmt.Errorf("%s_FILE: lecture du secret: %w", name, err)
+	}
+	return strings.TrimSpace(string(data)), true, nil
+}
+
+// validate vérifie la cohérence de la configuration et retourne toutes les erreurs d'un coup.
+func (c *Config) validate() error {
+	var errs []error
 
 	// Validation stricte des clés obligatoires
-	if cfg.DiscordToken == "" {
-		return nil, fmt.Errorf("DISCORD_TOKEN manquant dans l'environnement")
+	if c.Discord.Token == "" {
+		errs = append(errs, fmt.Errorf("DISCORD_TOKEN manquant (discord.token)"))
+	}
+	if c.Search.TavilyKey == "" {
+		errs = append(errs, fmt.Errorf("TAVILY_API_KEY manquant (search.tavily_api_key)"))
+	}
+
+	p, ok := c.AI.Providers[c.AI.Provider]
+	if !ok {
+		errs = append(errs, fmt.Errorf("ai.provider: fournisseur %q non déclaré dans ai.providers", c.AI.Provider))
+	} else {
+		if p.APIKey == "" {
+			errs = append(errs, fmt.Errorf("ai.providers.%s.api_key manquant", c.AI.Provider))
+		}
+		if p.URL == "" {
+			errs = append(errs, fmt.Errorf("ai.providers.%s.url manquant", c.AI.Provider))
+		}
+		if p.Model == "" {
+			errs = append(errs, fmt.Errorf("ai.providers.%s.model manquant", c.AI.Provider))
+		}
+	}
+	for name, p := range c.AI.Providers {
+		if p.Temperature != nil && (*p.Temperature < 0 || *p.Temperature > 1.5) {
+			errs = append(errs, fmt.Errorf("ai.providers.%s.temperature doit être entre 0.0 et 1.5 (actuel: %.2f)", name, *p.Temperature))
+		}
+	}
+
+	if c.AI.Timeout <= 0 {
+		errs = append(errs, fmt.Errorf("ai.timeout doit être positif"))
+	}
+	if c.Search.Timeout <= 0 {
+		errs = append(errs, fmt.Errorf("search.timeout doit être positif"))
+	}
+	if c.Search.MaxResults < 1 {
+		errs = append(errs, fmt.Errorf("search.max_results doit être au moins 1"))
 	}
-	if cfg.DeepSeekKey == "" {
-		return nil, fmt.Errorf("DEEPSEEK_API_KEY manquant dans l'environnement")
+	if c.Fetch.Enabled() {
+		if c.Fetch.MaxKB < 1 || c.Fetch.MaxTokens < 100 {
+			errs = append(errs, fmt.Errorf("fetch: max_kb (≥ 1) et max_tokens (≥ 100) sont obligatoires"))
+		}
+		if c.Fetch.Timeout <= 0 || c.Fetch.CacheTTL < 0 {
+			errs = append(errs, fmt.Errorf("fetch: timeout doit être positif et cache_ttl ne peut pas être négatif"))
+		}
 	}
-	if cfg.TavilyKey == "" {
-		return nil, fmt.Errorf("TAVILY_API_KEY manquant dans l'environnement")
+	if c.RateLimit.Requests < 1 || c.RateLimit.Window <= 0 {
+		errs = append(errs, fmt.Errorf("rate_limit: requests (≥ 1) et window (> 0) sont obligatoires"))
 	}
-	if cfg.DeepSeekURL == "" {
-		return nil, fmt.Errorf("DEEPSEEK_URL manquant dans l'environnement")
+	if c.Bots.Loop.Requests < 1 || c.Bots.Loop.Window <= 0 {
+		errs = append(errs, fmt.Errorf("bots.loop: requests (≥ 1) et window (> 0) sont obligatoires"))
 	}
-	if cfg.DeepSeekModel == "" {
-		return nil, fmt.Errorf("DEEPSEEK_MODEL manquant dans l'environnement")
+	if c.Feedback.Enabled && (c.Feedback.Retention <= 0 || c.Feedback.Max < 1) {
+		errs = append(errs, fmt.Errorf("feedback: retention (> 0) et max (≥ 1) sont obligatoires"))
+	}
+	if c.DM.Enabled {
+		if c.DM.RateLimit.Requests < 1 || c.DM.RateLimit.Window <= 0 {
+			errs = append(errs, fmt.Errorf("dm.rate_limit: requests (≥ 1) et window (> 0) sont obligatoires"))
+		}
+		if c.DM.DailyQuota < 1 {
+			errs = append(errs, fmt.Errorf("dm.daily_quota doit être positif"))
+		}
+		if c.DM.Memory < 0 || c.DM.Memory > 50 {
+			errs = append(errs, fmt.Errorf("dm.memory doit être entre 0 et 50"))
+		}
+		if c.DM.MemoryTTL <= 0 {
+			errs = append(errs, fmt.Errorf("dm.memory_ttl doit être positif"))
+		}
+	}
+	if c.History.Depth < 0 || c.History.Depth > 100 {
+		errs = append(errs, fmt.Errorf("history.depth doit être entre 0 et 100 (limite Discord)"))
 	}
 
-	return cfg, nil
+	if c.Persona != DefaultPersona {
+		if p, ok := c.Personas[c.Persona]; !ok || p.Prompt == "" {
+			errs = append(errs, fmt.Errorf("persona %q inconnu ou sans prompt", c.Persona))
+		}
+	}
+
+	switch c.Cassette.Mode {
+	case "", "record", "replay":
+	default:
+		errs = append(errs, fmt.Errorf("cassette.mode invalide: %q (attendu: record ou replay)", c.Cassette.Mode))
+	}
+
+	if c.LFG.Reminder < 0 {
+		errs = append(errs, fmt.Errorf("lfg.reminder ne peut pas être négatif"))
+	}
+	if c.DataDir == "" {
+		errs = append(errs, fmt.Errorf("data_dir manquant"))
+	}
+
+	if _, err := time.LoadLocation(c.Timezone); err != nil {
+		errs = append(errs, fmt.Errorf("timezone invalide %q: %w", c.Timezone, err))
+	}
+	for id, g := range c.Guilds {
+		if lurk := g.Channels.Lurk; lurk.Probability < 0 || lurk.Probability > 1 {
+			errs = append(errs, fmt.Errorf("guilds.%s.channels.lurk.probability doit être entre 0 et 1", id))
+		}
+		if lurk := g.Channels.Lurk; (lurk.Cooldown != nil && *lurk.Cooldown < 0) || (lurk.MinWords != nil && *lurk.MinWords < 0) {
+			errs = append(errs, fmt.Errorf("guilds.%s.channels.lurk: cooldown et min_words ne peuvent pas être négatifs", id))
+		}
+		if !slices.Contains([]int{60, 1440, 4320, 10080}, g.Channels.ThreadArchiveMinutes()) {
+			errs = append(errs, fmt.Errorf("guilds.%s.channels.thread_idle invalide %s (1h, 24h, 72h ou 168h)", id, g.Channels.ThreadIdle))
+		}
+		errs = append(errs, validateModeration("guilds."+id+".moderation", g.Moderation.Patterns, g.Moderation.Input, g.Moderation.Output, true)...)
+		errs = append(errs, validateAuditEvents("guilds."+id+".audit.events", g.Audit.Events)...)
+		if g.Almanax.Channel == "" {
+			continue
+		}
+		if _, err := time.Parse("15:04", g.Almanax.Time); err != nil {
+			errs = append(errs, fmt.Errorf("guilds.%s.almanax.time invalide %q (format attendu HH:MM)", id, g.Almanax.Time))
+		}
+	}
+
+	errs = append(errs, validateModeration("moderation", c.Moderation.Patterns, c.Moderation.Input, c.Moderation.Output, false)...)
+	switch cl := c.Moderation.Classifier; cl.Type {
+	case "", "llm":
+	case "endpoint":
+		if cl.URL == "" {
+			errs = append(errs, fmt.Errorf("moderation.classifier.url manquant"))
+		}
+	default:
+		errs = append(errs, fmt.Errorf("moderation.classifier.type invalide %q (llm ou endpoint)", cl.Type))
+	}
+	if c.Moderation.Classifier.Type != "" && c.Moderation.Classifier.Timeout <= 0 {
+		errs = append(errs, fmt.Errorf("moderation.classifier.timeout doit être positif"))
+	}
+
+	errs = append(errs, validateAuditEvents("audit.events", c.Audit.Events)...)
+	if c.Audit.CacheTTL <= 0 || c.Audit.CacheSize < 0 {
+		errs = append(errs, fmt.Errorf("audit: cache_ttl doit être positif et cache_size ne peut pas être négatif"))
+	}
+	if c.Audit.Retention < 0 || c.Audit.AbuseCooldown <= 0 {
+		errs = append(errs, fmt.Errorf("audit: retention ne peut pas être négatif et abuse_cooldown doit être positif"))
+	}
+
+	if c.Knowledge.Enabled() {
+		if c.Knowledge.Embeddings.Model == "" {
+			errs = append(errs, fmt.Errorf("knowledge.embeddings.model manquant"))
+		}
+		if c.Knowledge.IndexFile == "" {
+			errs = append(errs, fmt.Errorf("knowledge.index_file manquant"))
+		}
+		if c.Knowledge.TopK < 1 || c.Knowledge.TopK > 20 {
+			errs = append(errs, fmt.Errorf("knowledge.top_k doit être entre 1 et 20"))
+		}
+		if c.Knowledge.Embeddings.Timeout <= 0 {
+			errs = append(errs, fmt.Errorf("knowledge.embeddings.timeout doit être positif"))
+		}
+	}
+
+	if c.Logging.Format != "text" && c.Logging.Format != "json" {
+		errs = append(errs, fmt.Errorf("logging.format invalide: %q (attendu: text ou json)", c.Logging.Format))
+	}
+	if _, err := logging.ParseLevel(c.Logging.Level); err != nil {
+		errs = append(errs, fmt.Errorf("logging.level: %w", err))
+	}
+	for subsystem, level := range c.Logging.Levels {
+		if _, err := logging.ParseLevel(level); err != nil {
+			errs = append(errs, fmt.Errorf("logging.levels.%s: %w", subsystem, err))
+		}
+	}
+	for _, pattern := range c.Logging.RedactPatterns {
+		if _, err := regexp.Compile(pattern); err != nil {
+			errs = append(errs, fmt.Errorf("logging.redact_patterns: regex invalide %q: %w", pattern, err))
+		}
+	}
+
+	if len(errs) > 0 {
+		return fmt.Errorf("configuration invalide:\n%w", errors.Join(errs...))
+	}
+	return nil
 }
@@ -0,0 +1,47 @@
+package config
+
+import (
+	"encoding/json"
+	"log/slog"
+)
+
+// redactedSecret est affiché à la place de la valeur d'un Secret.
+const redactedSecret = "[REDACTED]"
+
+// Secret est une chaîne sensible (token, clé d'API) qui ne s'affiche jamais en clair :
+// fmt (%v, %s, %q, %#v), slog, JSON et YAML n'en montrent qu'un masque.
+// La valeur réelle n'est accessible qu'explicitement via Value.
+type Secret string
+
+// Value retourne la valeur en clair du secret.
+func (s Secret) Value() string {
+	return string(s)
+}
+
+// String implémente fmt.Stringer.
+func (s Secret) String() string {
+	if s == "" {
+		return ""
+	}
+	return redactedSecret
+}
+
+// GoString implémente fmt.GoStringer (%#v).
+func (s Secret) GoString() string {
+	return `config.Secret("` + s.String() + `")`
+}
+
+// LogValue implémente slog.LogValuer.
+func (s Secret) LogValue() slog.Value {
+	return slog.StringValue(s.String())
+}
+
+// MarshalJSON masque le secret lors d'une sérialisation JSON.
+func (s Secret) MarshalJSON() ([]byte, error) {
+	return json.Marshal(s.String())
+}
+
+// MarshalYAML masque le secret lors d'une sérialisation YAML.
+func (s Secret) MarshalYAML() (any, error) {
+	return s.String(), nil
+}
@@ -0,0 +1,34 @@
+package config
+
+import (
+	"context"
+	"os"
+	"time"
+)
+
+// Watch surveille le fichier de configuration et appelle onChange à chaque modification
+// (date de modification ou taille différente). Un simple polling suffit ici : le fichier
+// change rarement et cela évite une dépendance à inotify/fsevents.
+// Watch bloque jusqu'à l'annulation du contexte.
+func Watch(ctx context.Context, path string, interval time.Duration, onChange func()) {
+	last, _ := os.Stat(path)
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			info, err := os.Stat(path)
+			if err != nil {
+				continue // Fichier en cours de remplacement (éditeur, déploiement...)
+			}
+			if last == nil || !info.ModTime().Equal(last.ModTime()) || info.Size() != last.Size() {
+				last = info
+				onChange()
+			}
+		}
+	}
+}
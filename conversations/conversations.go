@@ -0,0 +1,116 @@
+// Package conversations mémorise les échanges entre le bot et les utilisateurs (question,
+// réponse), persistés dans un fichier JSON, pour donner de la mémoire aux conversations
+// qui n'ont pas d'historique exploitable côté Discord. Il conserve aussi les réponses
+// soumises à l'avis des utilisateurs (FeedbackStore), exportables pour ajuster le persona.
+package conversations
+
+import (
+	"otom-ai/storage"
+	"slices"
+	"sync"
+	"time"
+)
+
+// Exchange est une question d'un utilisateur et la réponse du bot.
+type Exchange struct {
+	Prompt string    `json:"prompt"`
+	Reply  string    `json:"reply"`
+	At     time.Time `json:"at"`
+}
+
+// Store conserve les derniers échanges par conversation (clé libre, ex: ID utilisateur).
+type Store struct {
+	mu    sync.Mutex
+	file  *storage.JSONFile
+	convs map[string][]Exchange // Du plus ancien au plus récent
+}
+
+// Open charge les conversations (aucune si le fichier n'existe pas encore).
+func Open(path string) (*Store, error) {
+	s := &Store{file: storage.NewJSONFile(path), convs: map[string][]Exchange{}}
+	if err := s.file.Load(&s.convs); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// Add ajoute un échange à une conversation en ne conservant que les keep derniers.
+func (s *Store) Add(key string, e Exchange, keep int) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	old := s.convs[key]
+	conv := append(slices.Clone(old), e)
+	if len(conv) > keep {
+		conv = conv[len(conv)-keep:]
+	}
+	s.convs[key] = conv
+	if len(conv) == 0 {
+		delete(s.convs, key)
+	}
+	if err := s.save(); err != nil {
+		s.convs[key] = old
+		return err
+	}
+	return nil
+}
+
+// Recent retourne les échanges d'une conversation postérieurs à since, du plus ancien au plus récent.
+func (s *Store) Recent(key string, since time.Time) []Exchange {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var out []Exchange
+	for _, e := range s.convs[key] {
+		if e.At.After(since) {
+			out = append(out, e)
+		}
+	}
+	return out
+}
+
+// Forget efface une conversation.
+func (s *Store) Forget(key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	old, ok := s.convs[key]
+	if !ok {
+		return nil
+	}
+	delete(s.convs, key)
+	if err := s.save(); err != nil {
+		s.convs[key] = old
+		return err
+	}
+	return nil
+}
+
+// Prune efface les échanges antérieurs à before.
+func (s *Store) Prune(before time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	removed := false
+	for key, conv := range s.convs {
+		kept := slices.DeleteFunc(slices.Clone(conv), func(e Exchange) bool { return e.At.Before(before) })
+		if len(kept) == len(conv) {
+			continue
+		}
+		removed = true
+		if len(kept) == 0 {
+			delete(s.convs, key)
+		} else {
+			s.convs[key] = kept
+		}
+	}
+	if !removed {
+		return nil
+	}
+	return s.save()
+}
+
+// save persiste toutes les conversations (appelé verrou pris).
+func (s *Store) save() error {
+	return s.file.Save(s.convs)
+}
@@ -0,0 +1,296 @@
+package conversations
+
+import (
+	"maps"
+	"otom-ai/storage"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+)
+
+// compactEvery est le nombre de votes journalisés au-delà duquel le journal est intégré
+// au fichier des réponses.
+const compactEvery = 200
+
+// Answer est une réponse du bot publiée sur Discord, conservée avec le contexte qui l'a
+// produite pour recueillir l'avis des utilisateurs (réactions 👍/👎).
+type Answer struct {
+	MessageID      string         `json:"message_id"` // Message Discord de la réponse
+	GuildID        string         `json:"guild_id,omitempty"`
+	ChannelID      string         `json:"channel_id"`
+	Author         string         `json:"author"`              // Pseudo de l'auteur de la question
+	AuthorID       string         `json:"author_id,omitempty"` // ID Discord de l'auteur (données personnelles)
+	History        []Turn         `json:"history,omitempty"`
+	Question       string         `json:"question"`
+	Reply          string         `json:"reply"`
+	Tools          []string       `json:"tools,omitempty"` // Outils appelés, dans l'ordre
+	Model          string         `json:"model"`
+	Persona        string         `json:"persona"`
+	PersonaVersion string         `json:"persona_version"`
+	At             time.Time      `json:"at"`
+	Votes          map[string]int `json:"votes,omitempty"` // +1 ou -1 par ID d'utilisateur
+}
+
+// Turn est un message de l'historique transmis au LLM avec la question
+// (mêmes champs que l'historique des scénarios d'évaluation).
+type Turn struct {
+	Author   string `json:"author,omitempty"`
+	AuthorID string `json:"author_id,omitempty"` // ID Discord de l'auteur (données personnelles)
+	Content  string `json:"content"`
+	Bot      bool   `json:"bot,omitempty"` // true pour une réponse du bot
+}
+
+// Score retourne le nombre de votes positifs et négatifs.
+func (a Answer) Score() (up, down int) {
+	for _, v := range a.Votes {
+		if v > 0 {
+			up++
+		} else {
+			down++
+		}
+	}
+	return up, down
+}
+
+// forgottenTurn remplace dans l'historique conservé les messages d'un utilisateur qui a
+// demandé la suppression de ses données.
+const forgottenTurn = "[message retiré à la demande de son auteur]"
+
+// voteEntry est une ligne du journal des votes.
+type voteEntry struct {
+	Seq       uint64    `json:"seq"` // Numéro croissant, pour ignorer au rejeu les votes déjà intégrés
+	MessageID string    `json:"message_id"`
+	UserID    string    `json:"user_id"`
+	Vote      int       `json:"vote"` // +1, -1, ou 0 si l'avis est retiré
+	At        time.Time `json:"at"`
+}
+
+// feedbackSnapshot est le contenu du fichier des réponses.
+type feedbackSnapshot struct {
+	Seq     uint64            `json:"seq"` // Dernier vote du journal intégré aux réponses
+	Answers map[string]Answer `json:"answers"`
+}
+
+// FeedbackStore conserve les réponses soumises à l'avis des utilisateurs. Les votes, fréquents,
+// sont ajoutés à un journal plutôt que de réécrire toutes les réponses à chaque réaction ; le
+// journal est intégré au fichier des réponses à chaque sauvegarde de celui-ci, ou tous les
+// compactEvery votes.
+type FeedbackStore struct {
+	mu      sync.Mutex
+	file    *storage.JSONFile
+	votes   *storage.JSONLog
+	pending int               // Votes journalisés depuis la dernière compaction
+	seq     uint64            // Numéro du dernier vote journalisé
+	answers map[string]Answer // Clé : ID du message de la réponse
+}
+
+// OpenFeedback charge les réponses (aucune si le fichier n'existe pas encore) et rejoue le
+// journal des votes, rangé à côté ("feedback.json" → "feedback-votes.jsonl"). Les votes déjà
+// intégrés au fichier des réponses (journal non vidé) sont ignorés.
+func OpenFeedback(path string) (*FeedbackStore, error) {
+	s := &FeedbackStore{
+		file:  storage.NewJSONFile(path),
+		votes: storage.NewJSONLog(strings.TrimSuffix(path, ".json") + "-votes.jsonl"),
+	}
+	snapshot := feedbackSnapshot{Answers: map[string]Answer{}}
+	if err := s.file.Load(&snapshot); err != nil {
+		return nil, err
+	}
+	s.answers, s.seq = snapshot.Answers, snapshot.Seq
+	if s.answers == nil {
+		s.answers = map[string]Answer{}
+	}
+	_, err := storage.Replay(s.votes, func(e voteEntry) {
+		if e.Seq <= snapshot.Seq {
+			return
+		}
+		s.seq = max(s.seq, e.Seq)
+		s.pending++
+		if a, ok := s.answers[e.MessageID]; ok {
+			s.answers[e.MessageID] = a.withVote(e.UserID, e.Vote)
+		}
+	})
+	if err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// withVote retourne une copie de la réponse avec l'avis d'un utilisateur remplacé (0 = retiré).
+func (a Answer) withVote(userID string, vote int) Answer {
+	a.Votes = maps.Clone(a.Votes)
+	if vote == 0 {
+		delete(a.Votes, userID)
+		return a
+	}
+	if a.Votes == nil {
+		a.Votes = map[string]int{}
+	}
+	a.Votes[userID] = vote
+	return a
+}
+
+// Record enregistre une réponse, en ne conservant que les keep plus récentes.
+func (s *FeedbackStore) Record(a Answer, keep int) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	old := maps.Clone(s.answers)
+	s.answers[a.MessageID] = a
+	if excess := len(s.answers) - keep; excess > 0 {
+		for _, oldest := range s.sorted()[:excess] {
+			delete(s.answers, oldest.MessageID)
+		}
+	}
+	if err := s.save(); err != nil {
+		s.answers = old
+		return err
+	}
+	return nil
+}
+
+// Vote enregistre l'avis d'un utilisateur (+1 ou -1) sur une réponse, en remplaçant le précédent.
+// Il retourne false si le message n'est pas une réponse suivie.
+func (s *FeedbackStore) Vote(messageID, userID string, vote int) (bool, error) {
+	return s.update(messageID, userID, func(current int) (int, bool) {
+		return vote, current != vote
+	})
+}
+
+// Unvote retire l'avis d'un utilisateur s'il correspond à vote (réaction retirée).
+func (s *FeedbackStore) Unvote(messageID, userID string, vote int) (bool, error) {
+	return s.update(messageID, userID, func(current int) (int, bool) {
+		return 0, current == vote
+	})
+}
+
+// Answers retourne les réponses suivies, de la plus ancienne à la plus récente.
+func (s *FeedbackStore) Answers() []Answer {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.sorted()
+}
+
+// Authored retourne les réponses aux questions d'un utilisateur, de la plus ancienne à la plus récente.
+func (s *FeedbackStore) Authored(userID string) []Answer {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return slices.DeleteFunc(s.sorted(), func(a Answer) bool { return a.AuthorID != userID })
+}
+
+// VotesBy retourne les avis d'un utilisateur, par ID de message de réponse.
+func (s *FeedbackStore) VotesBy(userID string) map[string]int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	votes := map[string]int{}
+	for id, a := range s.answers {
+		if v, ok := a.Votes[userID]; ok {
+			votes[id] = v
+		}
+	}
+	return votes
+}
+
+// ForgetUser efface les réponses aux questions d'un utilisateur et ses avis sur les autres
+// réponses, et retire ses messages de l'historique conservé avec les autres réponses. Seul l'ID
+// Discord fait foi : un pseudo libéré peut être repris par quelqu'un d'autre.
+func (s *FeedbackStore) ForgetUser(userID string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	old := maps.Clone(s.answers)
+	changed := false
+	for id, a := range s.answers {
+		if a.AuthorID == userID {
+			delete(s.answers, id)
+			changed = true
+			continue
+		}
+		if _, ok := a.Votes[userID]; ok {
+			a = a.withVote(userID, 0)
+			changed = true
+		}
+		if slices.ContainsFunc(a.History, func(t Turn) bool { return t.AuthorID == userID }) {
+			a.History = slices.Clone(a.History)
+			for i, t := range a.History {
+				if t.AuthorID == userID {
+					a.History[i] = Turn{Content: forgottenTurn}
+				}
+			}
+			changed = true
+		}
+		s.answers[id] = a
+	}
+	// Le journal peut encore contenir ses votes (vidage précédent en échec) : il est vidé dans tous les cas,
+	// pour ne pas les garder sur disque même s'ils ne seraient plus rejoués
+	if !changed && s.pending == 0 {
+		return nil
+	}
+	if err := s.save(); err != nil {
+		s.answers = old
+		return err
+	}
+	return nil
+}
+
+// Prune oublie les réponses antérieures à before.
+func (s *FeedbackStore) Prune(before time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	old := maps.Clone(s.answers)
+	maps.DeleteFunc(s.answers, func(_ string, a Answer) bool { return a.At.Before(before) })
+	if len(s.answers) == len(old) {
+		return nil
+	}
+	if err := s.save(); err != nil {
+		s.answers = old
+		return err
+	}
+	return nil
+}
+
+// update calcule le nouvel avis d'un utilisateur sur une réponse à partir de l'avis actuel
+// (0 si aucun) et, s'il change, l'ajoute au journal des votes.
+func (s *FeedbackStore) update(messageID, userID string, change func(current int) (vote int, changed bool)) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	a, ok := s.answers[messageID]
+	if !ok {
+		return false, nil
+	}
+	vote, changed := change(a.Votes[userID])
+	if !changed {
+		return true, nil
+	}
+	if err := s.votes.Append(voteEntry{Seq: s.seq + 1, MessageID: messageID, UserID: userID, Vote: vote, At: time.Now()}); err != nil {
+		return true, err
+	}
+	s.seq++
+	s.answers[messageID] = a.withVote(userID, vote)
+	if s.pending++; s.pending >= compactEvery {
+		return true, s.save()
+	}
+	return true, nil
+}
+
+// sorted retourne les réponses de la plus ancienne à la plus récente (appelé verrou pris).
+func (s *FeedbackStore) sorted() []Answer {
+	return slices.SortedFunc(maps.Values(s.answers), func(a, b Answer) int { return a.At.Compare(b.At) })
+}
+
+// save persiste toutes les réponses, votes compris, puis vide le journal des votes (appelé
+// verrou pris). Une erreur signifie que rien n'a été écrit. Une fois les réponses écrites,
+// un journal qui ne peut pas être vidé n'est pas une erreur : ses votes, numérotés, sont
+// ignorés au rejeu, et le vidage est retenté à la sauvegarde suivante.
+func (s *FeedbackStore) save() error {
+	if err := s.file.Save(feedbackSnapshot{Seq: s.seq, Answers: s.answers}); err != nil {
+		return err
+	}
+	if err := s.votes.Truncate(); err == nil {
+		s.pending = 0
+	}
+	return nil
+}
@@ -0,0 +1,168 @@
+package conversations
+
+import (
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+)
+
+// openFeedback ouvre un store d'avis avec trois réponses : une question d'Alice, une question
+// d'un autre joueur qui a repris son ancien pseudo, et une question de Bob dont l'historique
+// cite Alice et cet homonyme.
+func openFeedback(t *testing.T) (*FeedbackStore, string) {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "feedback.json")
+	s, err := OpenFeedback(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	at := time.Date(2026, 10, 18, 20, 0, 0, 0, time.UTC)
+	answers := []Answer{
+		{MessageID: "m1", Author: "alice", AuthorID: "1", Question: "meilleur spot xp ?", At: at},
+		{MessageID: "m2", Author: "alice", AuthorID: "4", Question: "stuff Iop 200 ?", At: at.Add(time.Minute)},
+		{MessageID: "m3", Author: "bob", AuthorID: "2", Question: "tu confirmes ?", At: at.Add(2 * time.Minute), History: []Turn{
+			{Author: "alice", AuthorID: "1", Content: "les Champs de Cania c'est top"},
+			{Content: "Oui, en groupe surtout.", Bot: true},
+			{Author: "alice", AuthorID: "4", Content: "pas faux"},
+			{Author: "bob", AuthorID: "2", Content: "ok merci"},
+		}},
+	}
+	for _, a := range answers {
+		if err := s.Record(a, 10); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return s, path
+}
+
+func TestFeedbackAuthored(t *testing.T) {
+	s, _ := openFeedback(t)
+	tests := []struct {
+		name   string
+		userID string
+		want   []string
+	}{
+		{"auteur", "1", []string{"m1"}},
+		{"pseudo repris par un autre joueur", "4", []string{"m2"}},
+		{"autre joueur", "2", []string{"m3"}},
+		{"inconnu", "3", nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []string
+			for _, a := range s.Authored(tt.userID) {
+				got = append(got, a.MessageID)
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("Authored(%q) = %v, attendu %v", tt.userID, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFeedbackVotesBy(t *testing.T) {
+	s, _ := openFeedback(t)
+	for _, v := range []struct {
+		message string
+		vote    int
+	}{{"m1", 1}, {"m3", -1}, {"m3", 1}} {
+		if _, err := s.Vote(v.message, "2", v.vote); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if _, err := s.Vote("m1", "1", -1); err != nil {
+		t.Fatal(err)
+	}
+	got := s.VotesBy("2")
+	if len(got) != 2 || got["m1"] != 1 || got["m3"] != 1 {
+		t.Errorf("VotesBy(2) = %v, attendu map[m1:1 m3:1]", got)
+	}
+	if _, err := s.Unvote("m1", "2", 1); err != nil {
+		t.Fatal(err)
+	}
+	if got := s.VotesBy("2"); len(got) != 1 {
+		t.Errorf("VotesBy(2) après retrait = %v, attendu un seul avis", got)
+	}
+}
+
+func TestFeedbackForgetUser(t *testing.T) {
+	s, path := openFeedback(t)
+	if _, err := s.Vote("m3", "1", 1); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.Vote("m3", "2", -1); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.ForgetUser("1"); err != nil {
+		t.Fatal(err)
+	}
+
+	// Les votes journalisés et l'instantané doivent être d'accord après rechargement
+	reopened, err := OpenFeedback(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for name, store := range map[string]*FeedbackStore{"en mémoire": s, "rechargé": reopened} {
+		t.Run(name, func(t *testing.T) {
+			answers := store.Answers()
+			if len(answers) != 2 || answers[0].MessageID != "m2" || answers[1].MessageID != "m3" {
+				t.Fatalf("Answers() = %v, attendu les réponses m2 et m3", answers)
+			}
+			a := answers[1]
+			if _, ok := a.Votes["1"]; ok || a.Votes["2"] != -1 {
+				t.Errorf("Votes = %v, attendu le seul avis de bob", a.Votes)
+			}
+			want := []Turn{
+				{Content: forgottenTurn},
+				{Content: "Oui, en groupe surtout.", Bot: true},
+				{Author: "alice", AuthorID: "4", Content: "pas faux"},
+				{Author: "bob", AuthorID: "2", Content: "ok merci"},
+			}
+			if !slices.Equal(a.History, want) {
+				t.Errorf("History = %v, attendu %v", a.History, want)
+			}
+			if got := store.Authored("1"); len(got) != 0 {
+				t.Errorf("Authored après oubli = %v", got)
+			}
+		})
+	}
+}
+
+func TestFeedbackReplaySkipsCompactedVotes(t *testing.T) {
+	s, path := openFeedback(t)
+	if _, err := s.Vote("m3", "1", 1); err != nil {
+		t.Fatal(err)
+	}
+	journal := strings.TrimSuffix(path, ".json") + "-votes.jsonl"
+	stale, err := os.ReadFile(journal)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := s.ForgetUser("1"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.Vote("m3", "2", -1); err != nil {
+		t.Fatal(err)
+	}
+
+	// Journal non vidé après l'oubli : le vote d'alice, déjà intégré, ne doit pas revenir
+	current, err := os.ReadFile(journal)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(journal, append(stale, current...), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	reopened, err := OpenFeedback(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := reopened.Answers()
+	if len(got) != 2 || !maps.Equal(got[1].Votes, map[string]int{"2": -1}) {
+		t.Errorf("Answers() après rechargement = %+v, attendu le seul avis de bob sur m3", got)
+	}
+}
@@ -0,0 +1,89 @@
+package eval
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// CheckResult est le résultat d'une vérification sur une réponse.
+type CheckResult struct {
+	Name   string `json:"name"`
+	Passed bool   `json:"passed"`
+	Detail string `json:"detail,omitempty"`
+}
+
+// Marqueurs du tutoiement et du vouvoiement (mots entiers, en minuscules).
+var (
+	tuWords   = map[string]bool{"tu": true, "te": true, "toi": true, "ton": true, "ta": true, "tes": true, "t": true}
+	vousWords = map[string]bool{"vous": true, "votre": true, "vos": true}
+)
+
+// runChecks applique les règles déterministes d'un scénario à la réponse obtenue.
+func runChecks(exp Expectations, reply string, searchUsed bool) []CheckResult {
+	var results []CheckResult
+	lower := strings.ToLower(reply)
+
+	if exp.Tutoiement {
+		results = append(results, checkTutoiement(lower))
+	}
+
+	for _, term := range exp.Mentions {
+		found := strings.Contains(lower, strings.ToLower(term))
+		results = append(results, CheckResult{
+			Name:   "mentionne " + term,
+			Passed: found,
+		})
+	}
+
+	for _, term := range exp.Forbidden {
+		found := strings.Contains(lower, strings.ToLower(term))
+		results = append(results, CheckResult{
+			Name:   "évite " + term,
+			Passed: !found,
+		})
+	}
+
+	if exp.UsesSearch != nil {
+		results = append(results, CheckResult{
+			Name:   "recherche web",
+			Passed: searchUsed == *exp.UsesSearch,
+			Detail: fmt.Sprintf("attendu=%t, obtenu=%t", *exp.UsesSearch, searchUsed),
+		})
+	}
+
+	if exp.MaxChars > 0 {
+		n := utf8.RuneCountInString(reply)
+		results = append(results, CheckResult{
+			Name:   fmt.Sprintf("≤ %d caractères", exp.MaxChars),
+			Passed: n <= exp.MaxChars,
+			Detail: fmt.Sprintf("%d caractères", n),
+		})
+	}
+
+	return results
+}
+
+// checkTutoiement vérifie que la réponse tutoie l'utilisateur sans jamais le vouvoyer.
+func checkTutoiement(lower string) CheckResult {
+	words := strings.FieldsFunc(lower, func(r rune) bool {
+		return !unicode.IsLetter(r)
+	})
+
+	var tu, vous int
+	for _, w := range words {
+		switch {
+		case tuWords[w]:
+			tu++
+		case vousWords[w]:
+			vous++
+		}
+	}
+
+	return CheckResult{
+		Name:   "tutoiement",
+		Passed: tu > 0 && vous == 0,
+		Detail: fmt.Sprintf("tu=%d, vous=%d", tu, vous),
+	}
+}
@@ -0,0 +1,53 @@
+package eval
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"otom-ai/ai"
+	"strings"
+)
+
+// judgePrompt cadre le LLM juge : il note une réponse selon un critère et répond en JSON strict.
+const judgePrompt = `Tu es un évaluateur impartial de réponses d'un chatbot Discord francophone spécialisé dans Dofus.
+On te donne la question posée, la réponse du chatbot et un critère d'évaluation.
+Réponds UNIQUEMENT avec un objet JSON de la forme {"score": <entier de 0 à 10>, "pass": <true|false>, "reason": "<une phrase>"}.
+"pass" vaut true si la réponse satisfait le critère.`
+
+// judgeVerdict est la réponse JSON attendue du LLM juge.
+type judgeVerdict struct {
+	Score  int    `json:"score"`
+	Pass   bool   `json:"pass"`
+	Reason string `json:"reason"`
+}
+
+// judge demande au LLM juge d'évaluer une réponse selon le critère donné.
+func judge(ctx context.Context, client *ai.Client, question, reply, criterion string) (CheckResult, error) {
+	messages := []ai.Message{
+		{Role: "system", Content: judgePrompt},
+		{Role: "user", Content: fmt.Sprintf("Question :\n%s\n\nRéponse du chatbot :\n%s\n\nCritère :\n%s", question, reply, criterion)},
+	}
+
+	result, err := client.Complete(ctx, messages, nil)
+	if err != nil {
+		return CheckResult{}, fmt.Errorf("appel du juge: %w", err)
+	}
+
+	// Le modèle entoure parfois le JSON de texte ou d'un bloc de code : on isole l'objet
+	raw := result.Reply
+	start, end := strings.Index(raw, "{"), strings.LastIndex(raw, "}")
+	if start < 0 || end < start {
+		return CheckResult{}, fmt.Errorf("réponse du juge non JSON: %q", raw)
+	}
+
+	var v judgeVerdict
+	if err := json.Unmarshal([]byte(raw[start:end+1]), &v); err != nil {
+		return CheckResult{}, fmt.Errorf("décodage du verdict du juge: %w", err)
+	}
+
+	return CheckResult{
+		Name:   "juge",
+		Passed: v.Pass,
+		Detail: fmt.Sprintf("%d/10 — %s", v.Score, v.Reason),
+	}, nil
+}
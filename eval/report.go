@@ -0,0 +1,130 @@
+package eval
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+)
+
+// Report est le rapport complet d'une exécution de suite.
+type Report struct {
+	Suite         string           `json:"suite"`
+	PromptVersion string           `json:"prompt_version"` // Empreinte du prompt système évalué
+	Model         string           `json:"model"`
+	Temperature   *float64         `json:"temperature,omitempty"`
+	StartedAt     time.Time        `json:"started_at"`
+	Score         float64          `json:"score"`  // Moyenne des scores des scénarios (0 à 1)
+	Passed        int              `json:"passed"` // Nombre de scénarios dont toutes les vérifications passent
+	Total         int              `json:"total"`
+	Scenarios     []ScenarioResult `json:"scenarios"`
+}
+
+// ScenarioResult est le résultat d'un scénario.
+type ScenarioResult struct {
+	Name       string        `json:"name"`
+	Reply      string        `json:"reply"`
+	SearchUsed bool          `json:"search_used"`
+	ToolCalls  []string      `json:"tool_calls,omitempty"` // "nom arguments" de chaque appel d'outil
+	Checks     []CheckResult `json:"checks"`
+	Score      float64       `json:"score"` // Part des vérifications réussies (0 à 1)
+	Duration   time.Duration `json:"duration_ns"`
+	Error      string        `json:"error,omitempty"`
+}
+
+// score calcule la part des vérifications réussies. Un scénario en erreur vaut 0.
+func (r *ScenarioResult) score() {
+	if r.Error != "" || len(r.Checks) == 0 {
+		r.Score = 0
+		return
+	}
+	passed := 0
+	for _, c := range r.Checks {
+		if c.Passed {
+			passed++
+		}
+	}
+	r.Score = float64(passed) / float64(len(r.Checks))
+}
+
+// summarize calcule les agrégats du rapport.
+func (r *Report) summarize() {
+	r.Total = len(r.Scenarios)
+	if r.Total == 0 {
+		return
+	}
+	var sum float64
+	for _, s := range r.Scenarios {
+		sum += s.Score
+		if s.Error == "" && s.Score == 1 {
+			r.Passed++
+		}
+	}
+	r.Score = sum / float64(r.Total)
+}
+
+// WriteJSON écrit le rapport au format JSON indenté.
+func (r *Report) WriteJSON(w io.Writer) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(r)
+}
+
+// WriteMarkdown écrit le rapport au format Markdown (tableau récapitulatif + détail des échecs).
+func (r *Report) WriteMarkdown(w io.Writer) error {
+	var b strings.Builder
+
+	fmt.Fprintf(&b, "# Évaluation « %s »\n\n", r.Suite)
+	fmt.Fprintf(&b, "- Prompt : `%s`\n- Modèle : `%s`\n", r.PromptVersion, r.Model)
+	if r.Temperature != nil {
+		fmt.Fprintf(&b, "- Température : %.2f\n", *r.Temperature)
+	}
+	fmt.Fprintf(&b, "- Date : %s\n", r.StartedAt.Format(time.RFC3339))
+	fmt.Fprintf(&b, "- Score global : **%.0f%%** (%d/%d scénarios parfaits)\n\n", r.Score*100, r.Passed, r.Total)
+
+	b.WriteString("| Scénario | Score | Recherche | Échecs |\n|---|---|---|---|\n")
+	for _, s := range r.Scenarios {
+		var failed []string
+		if s.Error != "" {
+			failed = append(failed, "erreur: "+s.Error)
+		}
+		for _, c := range s.Checks {
+			if !c.Passed {
+				failed = append(failed, c.Name)
+			}
+		}
+		search := "non"
+		if s.SearchUsed {
+			search = "oui"
+		}
+		fmt.Fprintf(&b, "| %s | %.0f%% | %s | %s |\n", s.Name, s.Score*100, search, escapeCell(strings.Join(failed, ", ")))
+	}
+
+	b.WriteString("\n## Réponses\n")
+	for _, s := range r.Scenarios {
+		fmt.Fprintf(&b, "\n### %s\n\n", s.Name)
+		if s.Reply != "" {
+			fmt.Fprintf(&b, "> %s\n\n", strings.ReplaceAll(s.Reply, "\n", "\n> "))
+		}
+		for _, c := range s.Checks {
+			mark := "✅"
+			if !c.Passed {
+				mark = "❌"
+			}
+			fmt.Fprintf(&b, "- %s %s", mark, c.Name)
+			if c.Detail != "" {
+				fmt.Fprintf(&b, " (%s)", c.Detail)
+			}
+			b.WriteString("\n")
+		}
+	}
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// escapeCell échappe les caractères qui casseraient un tableau Markdown.
+func escapeCell(s string) string {
+	return strings.NewReplacer("|", "\\|", "\n", " ").Replace(s)
+}
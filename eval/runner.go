@@ -0,0 +1,123 @@
+package eval
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"otom-ai/ai"
+	"otom-ai/persona"
+	"otom-ai/untrusted"
+	"time"
+)
+
+// Runner joue une suite d'évaluation contre un client LLM.
+type Runner struct {
+	client *ai.Client
+	tools  []ai.Tool // Outils de production (la recherche web peut être simulée par scénario)
+	prompt string    // Prompt système utilisé quand la suite n'en fournit pas
+	logger *slog.Logger
+}
+
+// NewRunner crée un Runner utilisant les mêmes outils que le bot.
+func NewRunner(client *ai.Client, tools []ai.Tool, prompt string, logger *slog.Logger) *Runner {
+	return &Runner{client: client, tools: tools, prompt: prompt, logger: logger}
+}
+
+// Run exécute tous les scénarios de la suite et retourne le rapport.
+func (r *Runner) Run(ctx context.Context, suite *Suite) *Report {
+	prompt := suite.systemPrompt
+	if prompt == "" {
+		prompt = r.prompt
+	}
+	if suite.Temperature != nil {
+		r.client.SetTemperature(*suite.Temperature)
+	}
+
+	report := &Report{
+		Suite:         suite.Name,
+		PromptVersion: persona.Version(prompt),
+		Model:         r.client.Model(),
+		Temperature:   suite.Temperature,
+		StartedAt:     time.Now(),
+	}
+
+	for _, sc := range suite.Scenarios {
+		res := r.runScenario(ctx, prompt, suite.Judge, sc)
+		r.logger.Info("Scénario évalué",
+			slog.String("scenario", sc.Name),
+			slog.Float64("score", res.Score),
+		)
+		report.Scenarios = append(report.Scenarios, res)
+	}
+
+	report.summarize()
+	return report
+}
+
+// runScenario joue un scénario et note la réponse obtenue.
+func (r *Runner) runScenario(ctx context.Context, prompt string, judgeCfg JudgeConfig, sc Scenario) ScenarioResult {
+	res := ScenarioResult{Name: sc.Name}
+
+	author := sc.Author
+	if author == "" {
+		author = "Joueur"
+	}
+
+	// Même mise en forme que le contexte construit par le bot ("[pseudo] message", historique
+	// dans des blocs de données non fiables)
+	messages := []ai.Message{{Role: "system", Content: prompt}, {Role: "system", Content: untrusted.Notice}}
+	for _, h := range sc.History {
+		if h.Bot {
+			messages = append(messages, ai.Message{Role: "assistant", Content: h.Content})
+			continue
+		}
+		messages = append(messages, ai.Message{Role: "user", Content: fmt.Sprintf("[%s] %s", h.Author, untrusted.Wrap("message", h.Content))})
+	}
+	messages = append(messages, ai.Message{Role: "user", Content: fmt.Sprintf("[%s] %s", author, sc.Question)})
+
+	tools := r.tools
+	if sc.SearchResult != nil {
+		tools = withCannedSearch(tools, *sc.SearchResult)
+	}
+
+	start := time.Now()
+	result, err := r.client.Complete(ctx, messages, tools)
+	res.Duration = time.Since(start)
+	if err != nil {
+		res.Error = err.Error()
+		return res
+	}
+
+	res.Reply = result.Reply
+	res.SearchUsed = result.Used(searchToolName)
+	for _, use := range result.ToolUses {
+		res.ToolCalls = append(res.ToolCalls, use.Name+" "+use.Arguments)
+	}
+	res.Checks = runChecks(sc.Expect, result.Reply, res.SearchUsed)
+
+	if judgeCfg.Enabled && sc.Expect.Judge != "" {
+		check, err := judge(ctx, r.client, sc.Question, result.Reply, sc.Expect.Judge)
+		if err != nil {
+			check = CheckResult{Name: "juge", Detail: err.Error()}
+		}
+		res.Checks = append(res.Checks, check)
+	}
+
+	res.score()
+	return res
+}
+
+// searchToolName est le nom de l'outil de recherche web, simulable par scénario.
+var searchToolName = ai.SearchToolDef().Function.Name
+
+// withCannedSearch remplace l'outil de recherche web par un résultat fixe (scénario déterministe).
+func withCannedSearch(tools []ai.Tool, canned string) []ai.Tool {
+	out := make([]ai.Tool, 0, len(tools))
+	for _, t := range tools {
+		if t.Def.Function.Name == searchToolName {
+			t = ai.SearchTool(func(context.Context, string) (string, error) { return canned, nil })
+		}
+		out = append(out, t)
+	}
+	return out
+}
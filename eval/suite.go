@@ -0,0 +1,99 @@
+// Package eval implémente le harnais d'évaluation du persona : une suite YAML de
+// conversations "golden" est jouée contre le LLM configuré, chaque réponse est notée
+// par des règles déterministes (et optionnellement par un LLM juge), puis un rapport
+// JSON/Markdown permet de comparer deux versions de prompt ou de température.
+package eval
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Suite est une suite de scénarios d'évaluation chargée depuis un fichier YAML.
+type Suite struct {
+	Name             string      `yaml:"name"`
+	SystemPromptFile string      `yaml:"system_prompt_file"` // Prompt à évaluer (défaut : persona configuré)
+	Temperature      *float64    `yaml:"temperature"`        // Surcharge de la température du client
+	Judge            JudgeConfig `yaml:"judge"`
+	Scenarios        []Scenario  `yaml:"scenarios"`
+
+	systemPrompt string // Contenu de SystemPromptFile, résolu au chargement
+}
+
+// JudgeConfig active la notation par un LLM juge.
+type JudgeConfig struct {
+	Enabled bool `yaml:"enabled"`
+}
+
+// Scenario décrit une conversation à rejouer et les propriétés attendues de la réponse.
+type Scenario struct {
+	Name         string        `yaml:"name"`
+	History      []HistoryLine `yaml:"history"`
+	Author       string        `yaml:"author"`        // Pseudo de l'auteur de la question (défaut : "Joueur")
+	Question     string        `yaml:"question"`      // Message adressé au bot
+	SearchResult *string       `yaml:"search_result"` // Résultat simulé de la recherche web (sinon recherche réelle)
+	Expect       Expectations  `yaml:"expect"`
+}
+
+// HistoryLine est un message de l'historique du channel précédant la question.
+type HistoryLine struct {
+	Author  string `yaml:"author"`
+	Content string `yaml:"content"`
+	Bot     bool   `yaml:"bot"` // true si le message vient du bot (rôle "assistant")
+}
+
+// Expectations liste les propriétés vérifiées sur la réponse.
+type Expectations struct {
+	Tutoiement bool     `yaml:"tutoiement"`  // La réponse tutoie et ne vouvoie pas
+	Mentions   []string `yaml:"mentions"`    // Termes devant apparaître (insensible à la casse)
+	Forbidden  []string `yaml:"forbidden"`   // Termes interdits (insensible à la casse)
+	UsesSearch *bool    `yaml:"uses_search"` // Appel (ou non) attendu de la recherche web
+	MaxChars   int      `yaml:"max_chars"`   // Longueur maximale de la réponse (0 = pas de limite)
+	Judge      string   `yaml:"judge"`       // Critère évalué par le LLM juge (si activé)
+}
+
+// LoadSuite lit et valide une suite d'évaluation YAML.
+// Le chemin de SystemPromptFile est résolu relativement au fichier de la suite.
+func LoadSuite(path string) (*Suite, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("lecture de la suite: %w", err)
+	}
+
+	var s Suite
+	if err := yaml.Unmarshal(data, &s); err != nil {
+		return nil, fmt.Errorf("décodage YAML de la suite: %w", err)
+	}
+
+	if len(s.Scenarios) == 0 {
+		return nil, fmt.Errorf("la suite %s ne contient aucun scénario", path)
+	}
+	for i, sc := range s.Scenarios {
+		if sc.Name == "" {
+			return nil, fmt.Errorf("scénario #%d: champ \"name\" manquant", i+1)
+		}
+		if sc.Question == "" {
+			return nil, fmt.Errorf("scénario %q: champ \"question\" manquant", sc.Name)
+		}
+	}
+
+	if s.SystemPromptFile != "" {
+		promptPath := s.SystemPromptFile
+		if !filepath.IsAbs(promptPath) {
+			promptPath = filepath.Join(filepath.Dir(path), promptPath)
+		}
+		prompt, err := os.ReadFile(promptPath)
+		if err != nil {
+			return nil, fmt.Errorf("lecture du prompt système: %w", err)
+		}
+		s.systemPrompt = string(prompt)
+	}
+
+	if s.Name == "" {
+		s.Name = filepath.Base(path)
+	}
+	return &s, nil
+}
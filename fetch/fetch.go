@@ -0,0 +1,300 @@
+// Package fetch télécharge des pages web (notes de patch, guides) pour le LLM : liste
+// blanche de domaines, respect du robots.txt, limites de taille et de durée, extraction
+// du contenu principal en Markdown borné et cache en mémoire.
+package fetch
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"mime"
+	"net/http"
+	"net/url"
+	"otom-ai/readable"
+	"strings"
+	"sync"
+	"time"
+)
+
+// UserAgent identifie le bot auprès des sites (et dans leurs robots.txt).
+const UserAgent = "OtomAI-Bot/1.0 (+bot Discord Dofus)"
+
+// maxCacheEntries borne le nombre de pages gardées en cache.
+const maxCacheEntries = 200
+
+// ErrNotAllowed est retournée pour un domaine hors liste blanche ou une page interdite par robots.txt.
+var ErrNotAllowed = errors.New("page non autorisée")
+
+// Options paramètre le client.
+type Options struct {
+	AllowedDomains []string      // Domaines autorisés (sous-domaines compris)
+	MaxBytes       int64         // Taille maximale téléchargée
+	MaxChars       int           // Longueur maximale du Markdown transmis au LLM
+	CacheTTL       time.Duration // Durée de conservation d'une page en cache
+}
+
+// cacheEntry est une page (ou un robots.txt) en cache.
+type cacheEntry struct {
+	content string
+	robots  robotsRules
+	expires time.Time
+}
+
+// Client télécharge et convertit des pages web.
+type Client struct {
+	opts       Options
+	httpClient *http.Client
+	logger     *slog.Logger
+
+	mu     sync.Mutex
+	pages  map[string]cacheEntry // Clé : URL
+	robots map[string]cacheEntry // Clé : schéma://hôte
+}
+
+// NewClient crée un client avec un timeout HTTP de 10 secondes.
+func NewClient(opts Options) *Client {
+	c := &Client{
+		opts:   opts,
+		logger: slog.New(slog.DiscardHandler),
+		pages:  map[string]cacheEntry{},
+		robots: map[string]cacheEntry{},
+	}
+	c.httpClient = &http.Client{
+		Timeout: 10 * time.Second,
+		CheckRedirect: func(req *http.Request, via []*http.Request) error {
+			if len(via) >= 5 {
+				return errors.New("trop de redirections")
+			}
+			// Une redirection ne doit pas permettre de sortir de la liste blanche
+			if !c.allowedHost(req.URL.Hostname()) {
+				return fmt.Errorf("%w: redirection vers %s", ErrNotAllowed, req.URL.Hostname())
+			}
+			// ...ni de contourner le robots.txt du site de destination (sauf pour lire ce robots.txt lui-même)
+			if req.URL.Path == "/robots.txt" {
+				return nil
+			}
+			robots, err := c.robotsFor(req.Context(), req.URL)
+			if err != nil {
+				return err
+			}
+			if !robots.allows(req.URL.EscapedPath()) {
+				return fmt.Errorf("%w: redirection vers %s, interdite aux robots par le site", ErrNotAllowed, req.URL.Redacted())
+			}
+			return nil
+		},
+	}
+	return c
+}
+
+// SetLogger définit le logger utilisé pour tracer les téléchargements (niveau Debug).
+func (c *Client) SetLogger(logger *slog.Logger) {
+	c.logger = logger
+}
+
+// SetTimeout modifie le timeout HTTP d'un téléchargement.
+func (c *Client) SetTimeout(d time.Duration) {
+	c.httpClient.Timeout = d
+}
+
+// SetTransport remplace le transport HTTP du client (ex: enregistrement ou rejeu de cassettes).
+func (c *Client) SetTransport(rt http.RoundTripper) {
+	c.httpClient.Transport = rt
+}
+
+// Fetch retourne le contenu principal de la page en Markdown, tronqué à MaxChars.
+func (c *Client) Fetch(ctx context.Context, rawURL string) (string, error) {
+	u, err := url.Parse(strings.TrimSpace(rawURL))
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return "", fmt.Errorf("URL invalide %q", rawURL)
+	}
+	u.Fragment = ""
+	key := u.String()
+
+	if !c.allowedHost(u.Hostname()) {
+		return "", fmt.Errorf("%w: le domaine %s n'est pas dans la liste blanche (%s)",
+			ErrNotAllowed, u.Hostname(), strings.Join(c.opts.AllowedDomains, ", "))
+	}
+
+	if content, ok := c.cached(c.pages, key); ok {
+		return content.content, nil
+	}
+
+	robots, err := c.robotsFor(ctx, u)
+	if err != nil {
+		return "", err
+	}
+	if !robots.allows(u.EscapedPath()) {
+		return "", fmt.Errorf("%w: %s est interdit aux robots par le site", ErrNotAllowed, key)
+	}
+
+	start := time.Now()
+	body, contentType, err := c.get(ctx, key)
+	if err != nil {
+		return "", err
+	}
+
+	content, err := toMarkdown(body, contentType)
+	if err != nil {
+		return "", err
+	}
+	content = fmt.Sprintf("Source : %s\n\n%s", key, truncate(content, c.opts.MaxChars))
+
+	c.logger.Debug("Page téléchargée",
+		slog.String("url", key),
+		slog.Int("bytes", len(body)),
+		slog.Int("chars", len(content)),
+		slog.Duration("duration", time.Since(start)),
+	)
+
+	c.store(c.pages, key, cacheEntry{content: content})
+	return content, nil
+}
+
+// allowedHost indique si l'hôte appartient à un domaine autorisé (ou à l'un de ses sous-domaines).
+func (c *Client) allowedHost(host string) bool {
+	host = strings.ToLower(strings.TrimSuffix(host, "."))
+	for _, d := range c.opts.AllowedDomains {
+		d = strings.ToLower(d)
+		if host == d || strings.HasSuffix(host, "."+d) {
+			return true
+		}
+	}
+	return false
+}
+
+// robotsFor retourne les règles robots.txt du site (en cache). Un robots.txt absent autorise tout.
+func (c *Client) robotsFor(ctx context.Context, u *url.URL) (robotsRules, error) {
+	origin := u.Scheme + "://" + u.Host
+	if entry, ok := c.cached(c.robots, origin); ok {
+		return entry.robots, nil
+	}
+
+	var rules robotsRules
+	body, _, err := c.get(ctx, origin+"/robots.txt")
+	var status *statusError
+	switch {
+	case err == nil:
+		rules = parseRobots(bytes.NewReader(body), UserAgent)
+	case errors.As(err, &status) && status.code >= 400 && status.code < 500:
+		// Pas de robots.txt : tout est autorisé
+	default:
+		return rules, fmt.Errorf("lecture du robots.txt de %s: %w", u.Host, err)
+	}
+
+	c.store(c.robots, origin, cacheEntry{robots: rules})
+	return rules, nil
+}
+
+// statusError est retournée pour une réponse HTTP non 2xx.
+type statusError struct {
+	code int
+}
+
+func (e *statusError) Error() string {
+	return fmt.Sprintf("statut HTTP %d", e.code)
+}
+
+// get télécharge une ressource en respectant la taille maximale.
+func (c *Client) get(ctx context.Context, rawURL string) ([]byte, string, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, rawURL, nil)
+	if err != nil {
+		return nil, "", fmt.Errorf("erreur de création de requête: %w", err)
+	}
+	req.Header.Set("User-Agent", UserAgent)
+	req.Header.Set("Accept", "text/html,text/plain;q=0.9,*/*;q=0.1")
+	req.Header.Set("Accept-Language", "fr,en;q=0.5")
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, "", fmt.Errorf("erreur réseau: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return nil, "", &statusError{code: resp.StatusCode}
+	}
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, c.opts.MaxBytes+1))
+	if err != nil {
+		return nil, "", fmt.Errorf("erreur de lecture: %w", err)
+	}
+	if int64(len(body)) > c.opts.MaxBytes {
+		return nil, "", fmt.Errorf("page trop volumineuse (plus de %d Ko)", c.opts.MaxBytes/1024)
+	}
+	return body, resp.Header.Get("Content-Type"), nil
+}
+
+// toMarkdown convertit la réponse selon son type : contenu principal d'une page HTML, ou texte brut.
+func toMarkdown(body []byte, contentType string) (string, error) {
+	mediaType, _, _ := mime.ParseMediaType(contentType)
+	switch {
+	case mediaType == "text/html" || mediaType == "application/xhtml+xml" || mediaType == "":
+		doc, err := readable.Extract(bytes.NewReader(body))
+		if err != nil {
+			return "", err
+		}
+		if doc.Title != "" {
+			return "# " + doc.Title + "\n\n" + doc.Markdown, nil
+		}
+		return doc.Markdown, nil
+	case strings.HasPrefix(mediaType, "text/"):
+		return string(body), nil
+	default:
+		return "", fmt.Errorf("type de contenu non pris en charge: %s", mediaType)
+	}
+}
+
+// truncate coupe le Markdown à limit caractères, de préférence en fin de paragraphe.
+func truncate(s string, limit int) string {
+	if len(s) <= limit {
+		return s
+	}
+	cut := strings.LastIndex(s[:limit], "\n\n")
+	if cut < limit/2 {
+		cut = strings.LastIndex(s[:limit], " ")
+	}
+	if cut <= 0 {
+		cut = limit
+	}
+	return strings.ToValidUTF8(s[:cut], "") + "\n\n[… page tronquée]"
+}
+
+// cached retourne une entrée encore valide du cache.
+func (c *Client) cached(cache map[string]cacheEntry, key string) (cacheEntry, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	entry, ok := cache[key]
+	if !ok || time.Now().After(entry.expires) {
+		return cacheEntry{}, false
+	}
+	return entry, true
+}
+
+// store ajoute une entrée au cache, en purgeant les entrées expirées (puis les plus anciennes) si plein.
+func (c *Client) store(cache map[string]cacheEntry, key string, entry cacheEntry) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	now := time.Now()
+	entry.expires = now.Add(c.opts.CacheTTL)
+	if len(cache) >= maxCacheEntries {
+		var oldestKey string
+		var oldest time.Time
+		for k, e := range cache {
+			if now.After(e.expires) {
+				delete(cache, k)
+				continue
+			}
+			if oldestKey == "" || e.expires.Before(oldest) {
+				oldestKey, oldest = k, e.expires
+			}
+		}
+		if len(cache) >= maxCacheEntries {
+			delete(cache, oldestKey)
+		}
+	}
+	cache[key] = entry
+}
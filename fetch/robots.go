@@ -0,0 +1,123 @@
+package fetch
+
+import (
+	"bufio"
+	"io"
+	"strings"
+)
+
+// robotsRules contient les règles robots.txt applicables à notre user-agent.
+type robotsRules struct {
+	allow    []string
+	disallow []string
+}
+
+// parseRobots extrait d'un robots.txt les règles du groupe correspondant à agent,
+// ou à défaut celles du groupe "*".
+func parseRobots(r io.Reader, agent string) robotsRules {
+	var (
+		specific, generic robotsRules
+		hasSpecific       bool
+		groupAgents       []string
+		inRules           bool // Les lignes User-agent consécutives forment un même groupe
+	)
+	agent = strings.ToLower(agent)
+
+	apply := func(field, value string) {
+		for _, a := range groupAgents {
+			var dst *robotsRules
+			switch {
+			case a == "*":
+				dst = &generic
+			case strings.Contains(agent, a):
+				dst = &specific
+				hasSpecific = true
+			default:
+				continue
+			}
+			if value == "" { // "Disallow:" vide : le groupe existe mais n'interdit rien
+				continue
+			}
+			if field == "allow" {
+				dst.allow = append(dst.allow, value)
+			} else {
+				dst.disallow = append(dst.disallow, value)
+			}
+		}
+	}
+
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if i := strings.IndexByte(line, '#'); i >= 0 {
+			line = line[:i]
+		}
+		field, value, ok := strings.Cut(line, ":")
+		if !ok {
+			continue
+		}
+		field = strings.ToLower(strings.TrimSpace(field))
+		value = strings.TrimSpace(value)
+
+		switch field {
+		case "user-agent":
+			if inRules {
+				groupAgents = nil
+				inRules = false
+			}
+			groupAgents = append(groupAgents, strings.ToLower(value))
+		case "allow", "disallow":
+			inRules = true
+			apply(field, value)
+		}
+	}
+
+	if hasSpecific {
+		return specific
+	}
+	return generic
+}
+
+// allows applique la règle la plus spécifique (préfixe le plus long) ; Allow l'emporte à égalité.
+func (r robotsRules) allows(path string) bool {
+	best, allowed := -1, true
+	for _, p := range r.disallow {
+		if matchRobots(p, path) && len(p) > best {
+			best, allowed = len(p), false
+		}
+	}
+	for _, p := range r.allow {
+		if matchRobots(p, path) && len(p) >= best {
+			best, allowed = len(p), true
+		}
+	}
+	return allowed
+}
+
+// matchRobots teste un motif robots.txt (préfixe, avec jokers * et ancre $ finale).
+func matchRobots(pattern, path string) bool {
+	anchored := strings.HasSuffix(pattern, "$")
+	pattern = strings.TrimSuffix(pattern, "$")
+
+	parts := strings.Split(pattern, "*")
+	if !strings.HasPrefix(path, parts[0]) {
+		return false
+	}
+	rest := path[len(parts[0]):]
+	if len(parts) == 1 {
+		return !anchored || rest == ""
+	}
+
+	last := parts[len(parts)-1]
+	for _, part := range parts[1 : len(parts)-1] {
+		i := strings.Index(rest, part)
+		if i < 0 {
+			return false
+		}
+		rest = rest[i+len(part):]
+	}
+	if anchored {
+		return strings.HasSuffix(rest, last)
+	}
+	return strings.Contains(rest, last)
+}
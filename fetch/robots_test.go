@@ -0,0 +1,103 @@
+package fetch
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestMatchRobots(t *testing.T) {
+	tests := []struct {
+		pattern, path string
+		want          bool
+	}{
+		{"/", "/guides/iop", true},
+		{"/guides", "/guides/iop", true},
+		{"/guides", "/forum", false},
+		{"/*.pdf", "/patch/notes.pdf", true},
+		{"/*.pdf", "/patch/notes.pdf?v=2", true},
+		{"/*.pdf$", "/patch/notes.pdf?v=2", false},
+		{"/*.pdf$", "/patch/notes.pdf", true},
+		{"/guides$", "/guides", true},
+		{"/guides$", "/guides/iop", false},
+		{"/*/edit", "/wiki/iop/edit", true},
+		{"/*/edit", "/wiki/iop", false},
+		{"/a*b*c", "/axxbyyc", true},
+		{"/a*b*c", "/axxcyyb", false},
+	}
+	for _, tt := range tests {
+		if got := matchRobots(tt.pattern, tt.path); got != tt.want {
+			t.Errorf("matchRobots(%q, %q) = %v, attendu %v", tt.pattern, tt.path, got, tt.want)
+		}
+	}
+}
+
+func TestParseRobots(t *testing.T) {
+	tests := []struct {
+		name    string
+		robots  string
+		allowed map[string]bool // Chemin → autorisé
+	}{
+		{
+			name: "groupe générique",
+			robots: `User-agent: *
+Disallow: /admin # zone privée
+Disallow: /*.pdf$
+Allow: /admin/public`,
+			allowed: map[string]bool{"/guides": true, "/admin/users": false, "/admin/public/faq": true, "/notes.pdf": false},
+		},
+		{
+			name: "groupe spécifique prioritaire",
+			robots: `User-agent: *
+Disallow: /
+
+User-agent: OtomAI-Bot
+Disallow: /forum`,
+			allowed: map[string]bool{"/guides": true, "/forum/topic": false},
+		},
+		{
+			name: "groupe spécifique sans interdiction",
+			robots: `User-agent: *
+Disallow: /
+
+User-agent: otomai-bot
+Disallow:`,
+			allowed: map[string]bool{"/guides": true},
+		},
+		{
+			name: "agents groupés",
+			robots: `User-agent: Googlebot
+User-agent: OtomAI-Bot
+Disallow: /private
+
+User-agent: Bingbot
+Disallow: /`,
+			allowed: map[string]bool{"/guides": true, "/private/x": false},
+		},
+		{
+			name: "autre robot seulement",
+			robots: `User-agent: Bingbot
+Disallow: /`,
+			allowed: map[string]bool{"/guides": true},
+		},
+		{
+			name: "préfixe le plus long, Allow à égalité",
+			robots: `User-agent: *
+Disallow: /wiki/
+Allow: /wiki/iop
+Disallow: /wiki/iop/edit
+Allow: /page
+Disallow: /page`,
+			allowed: map[string]bool{"/wiki/cra": false, "/wiki/iop": true, "/wiki/iop/edit": false, "/page": true},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rules := parseRobots(strings.NewReader(tt.robots), UserAgent)
+			for path, want := range tt.allowed {
+				if got := rules.allows(path); got != want {
+					t.Errorf("allows(%q) = %v, attendu %v", path, got, want)
+				}
+			}
+		})
+	}
+}
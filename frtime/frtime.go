@@ -0,0 +1,23 @@
+// Package frtime formate les dates en français pour les messages du bot et le contexte du LLM.
+package frtime
+
+import (
+	"fmt"
+	"time"
+)
+
+// Weekdays contient les noms des jours de la semaine, indexés par time.Weekday.
+var Weekdays = [...]string{"dimanche", "lundi", "mardi", "mercredi", "jeudi", "vendredi", "samedi"}
+
+// Months contient les noms des mois, indexés par time.Month - 1.
+var Months = [...]string{"janvier", "février", "mars", "avril", "mai", "juin", "juillet", "août", "septembre", "octobre", "novembre", "décembre"}
+
+// Date formate une date : "samedi 18 octobre 2026".
+func Date(t time.Time) string {
+	return fmt.Sprintf("%s %d %s %d", Weekdays[t.Weekday()], t.Day(), Months[t.Month()-1], t.Year())
+}
+
+// DateTime formate une date et une heure : "samedi 18 octobre 2026 à 21h00".
+func DateTime(t time.Time) string {
+	return fmt.Sprintf("%s à %02dh%02d", Date(t), t.Hour(), t.Minute())
+}
@@ -0,0 +1,134 @@
+package frtime
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ErrNoTime est retournée quand l'expression ne contient pas d'heure.
+var ErrNoTime = errors.New("heure manquante")
+
+var (
+	isoLayouts = []string{"2006-01-02T15:04:05", "2006-01-02T15:04", "2006-01-02 15:04"}
+	relative   = regexp.MustCompile(`^dans\s+(?:(\d+)\s*(?:h|heures?)\s*(\d+)?)?\s*(?:(\d+)\s*(?:min|minutes?|mn))?$`)
+	clock      = regexp.MustCompile(`(?:^|\s|à)(\d{1,2})\s*(?:h|:)\s*(\d{2})?(?:\s|$)`)
+	dayMonth   = regexp.MustCompile(`\b(\d{1,2})/(\d{1,2})(?:/(\d{2,4}))?\b`)
+	dayNumber  = regexp.MustCompile(`\ble\s+(\d{1,2})(?:\s|$)`)
+	isoDate    = regexp.MustCompile(`\b(\d{4})-(\d{2})-(\d{2})\b`)
+)
+
+// Parse interprète une date et heure exprimée en français par rapport à now, dans le fuseau loc :
+// "samedi 21h", "demain 20h30", "ce soir 21h", "24/10 21h", "le 24 à 21h", "dans 2h",
+// "2026-10-24 21:00". Un jour de la semaine désigne sa prochaine occurrence (aujourd'hui si
+// l'heure n'est pas encore passée). L'heure est obligatoire, sauf pour "dans ...".
+func Parse(s string, now time.Time, loc *time.Location) (time.Time, error) {
+	now = now.In(loc)
+	s = strings.TrimSpace(s)
+	for _, layout := range isoLayouts {
+		if t, err := time.ParseInLocation(layout, s, loc); err == nil {
+			return t, nil
+		}
+	}
+
+	s = strings.ToLower(s)
+	s = strings.NewReplacer("’", "'", "à", " à ", ",", " ").Replace(s)
+	s = strings.Join(strings.Fields(s), " ")
+
+	if m := relative.FindStringSubmatch(s); m != nil && (m[1] != "" || m[3] != "") {
+		hours, _ := strconv.Atoi(m[1])
+		minutes, _ := strconv.Atoi(m[2])
+		if m[3] != "" {
+			minutes, _ = strconv.Atoi(m[3])
+		}
+		return now.Add(time.Duration(hours)*time.Hour + time.Duration(minutes)*time.Minute).Truncate(time.Minute), nil
+	}
+
+	// Heure
+	m := clock.FindStringSubmatch(s)
+	if m == nil {
+		return time.Time{}, fmt.Errorf("%w dans %q (ex: samedi 21h)", ErrNoTime, s)
+	}
+	hour, _ := strconv.Atoi(m[1])
+	minute, _ := strconv.Atoi(m[2])
+	if hour > 23 || minute > 59 {
+		return time.Time{}, fmt.Errorf("heure invalide dans %q", s)
+	}
+	rest := strings.Replace(s, strings.TrimSpace(m[0]), " ", 1)
+
+	// Jour
+	year, month, day := now.Date()
+	explicit := true // Jour explicite : pas de report au lendemain si l'heure est passée
+	numeric := false // Jour donné en chiffres, à valider
+	switch {
+	case isoDate.MatchString(rest):
+		numeric = true
+		d := isoDate.FindStringSubmatch(rest)
+		year, _ = strconv.Atoi(d[1])
+		mo, _ := strconv.Atoi(d[2])
+		month = time.Month(mo)
+		day, _ = strconv.Atoi(d[3])
+	case dayMonth.MatchString(rest):
+		numeric = true
+		d := dayMonth.FindStringSubmatch(rest)
+		day, _ = strconv.Atoi(d[1])
+		mo, _ := strconv.Atoi(d[2])
+		month = time.Month(mo)
+		if d[3] != "" {
+			year, _ = strconv.Atoi(d[3])
+			if year < 100 {
+				year += 2000
+			}
+		} else if time.Date(year, month, day, hour, minute, 0, 0, loc).Before(now) {
+			year++ // "05/01" en décembre désigne janvier prochain
+		}
+	case strings.Contains(rest, "après-demain") || strings.Contains(rest, "apres-demain") || strings.Contains(rest, "après demain"):
+		day += 2
+	case strings.Contains(rest, "demain"):
+		day++
+	case strings.Contains(rest, "aujourd'hui") || strings.Contains(rest, "ce soir") || strings.Contains(rest, "cet aprem") || strings.Contains(rest, "ce matin"):
+	case dayNumber.MatchString(rest):
+		numeric = true
+		d, _ := strconv.Atoi(dayNumber.FindStringSubmatch(rest)[1])
+		if d < day || (d == day && time.Date(year, month, d, hour, minute, 0, 0, loc).Before(now)) {
+			month++ // "le 3" le 20 du mois désigne le mois prochain
+		}
+		day = d
+	default:
+		explicit = false
+		if wd, ok := weekday(rest); ok {
+			offset := (int(wd) - int(now.Weekday()) + 7) % 7
+			if offset == 0 && !time.Date(year, month, day, hour, minute, 0, 0, loc).After(now) {
+				offset = 7
+			}
+			day += offset
+			explicit = true
+		}
+	}
+
+	// time.Date normalise le 31/02 en mars : on refuse une date numérique inexistante plutôt que de deviner
+	if numeric && time.Date(year, month, day, 0, 0, 0, 0, loc).Day() != day {
+		return time.Time{}, fmt.Errorf("date invalide dans %q", s)
+	}
+
+	t := time.Date(year, month, day, hour, minute, 0, 0, loc)
+	if !explicit && !t.After(now) {
+		t = t.AddDate(0, 0, 1) // Heure seule déjà passée : demain
+	}
+	return t, nil
+}
+
+// weekday cherche un nom de jour de la semaine dans le texte.
+func weekday(s string) (time.Weekday, bool) {
+	for _, word := range strings.Fields(s) {
+		for i, name := range Weekdays {
+			if word == name {
+				return time.Weekday(i), true
+			}
+		}
+	}
+	return 0, false
+}
@@ -0,0 +1,73 @@
+package frtime
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestParse(t *testing.T) {
+	loc := time.FixedZone("CEST", 2*60*60)
+	now := time.Date(2026, 10, 18, 20, 0, 0, 0, loc) // Dimanche 20h
+	at := func(month time.Month, day, hour, minute int) time.Time {
+		return time.Date(2026, month, day, hour, minute, 0, 0, loc)
+	}
+
+	tests := []struct {
+		in   string
+		want time.Time
+	}{
+		{"samedi 21h", at(10, 24, 21, 0)},
+		{"Dimanche 21h", at(10, 18, 21, 0)}, // Aujourd'hui, l'heure n'est pas passée
+		{"dimanche 19h", at(10, 25, 19, 0)}, // Heure passée : dimanche prochain
+		{"demain 20h30", at(10, 19, 20, 30)},
+		{"après-demain 9h", at(10, 20, 9, 0)},
+		{"ce soir 21h", at(10, 18, 21, 0)},
+		{"21h", at(10, 18, 21, 0)},
+		{"19h", at(10, 19, 19, 0)}, // Heure seule passée : demain
+		{"24/10 21h", at(10, 24, 21, 0)},
+		{"05/01 21h", time.Date(2027, 1, 5, 21, 0, 0, 0, loc)},
+		{"le 24 à 21h", at(10, 24, 21, 0)},
+		{"le 3 à 21h", at(11, 3, 21, 0)},
+		{"dans 2h", at(10, 18, 22, 0)},
+		{"dans 1h30", at(10, 18, 21, 30)},
+		{"dans 45 min", at(10, 18, 20, 45)},
+		{"2026-10-24 21:00", at(10, 24, 21, 0)},
+	}
+	for _, tt := range tests {
+		got, err := Parse(tt.in, now, loc)
+		if err != nil {
+			t.Errorf("Parse(%q): %v", tt.in, err)
+			continue
+		}
+		if !got.Equal(tt.want) {
+			t.Errorf("Parse(%q) = %s, attendu %s", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestParseInvalid(t *testing.T) {
+	loc := time.FixedZone("CEST", 2*60*60)
+	now := time.Date(2026, 10, 18, 20, 0, 0, 0, loc)
+
+	tests := []struct {
+		in     string
+		noTime bool // Erreur attendue : ErrNoTime
+	}{
+		{"samedi", true},
+		{"demain soir", true},
+		{"samedi 25h", false},
+		{"31/02 21h", false},
+		{"le 31/11 à 20h", false},
+	}
+	for _, tt := range tests {
+		_, err := Parse(tt.in, now, loc)
+		if err == nil {
+			t.Errorf("Parse(%q) accepté, attendu une erreur", tt.in)
+			continue
+		}
+		if errors.Is(err, ErrNoTime) != tt.noTime {
+			t.Errorf("Parse(%q) = %v, ErrNoTime attendue : %v", tt.in, err, tt.noTime)
+		}
+	}
+}
@@ -0,0 +1,407 @@
+// Package guildkb gère la fiche de connaissances propre à chaque serveur Discord (règlement,
+// conditions de recrutement, private jokes...) : des entrées texte associées à des mots-clés,
+// injectées dans le contexte du LLM quand un message les évoque, avec historique des versions.
+package guildkb
+
+import (
+	"errors"
+	"fmt"
+	"otom-ai/items"
+	"otom-ai/storage"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+)
+
+const (
+	// MaxEntries est le nombre d'entrées par serveur.
+	MaxEntries = 50
+	// MaxText est la longueur maximale du texte d'une entrée.
+	MaxText = 1500
+	// MaxTotal est la longueur cumulée maximale des textes d'un serveur.
+	MaxTotal = 20000
+	// MaxKeywords est le nombre de mots-clés par entrée.
+	MaxKeywords = 10
+	// maxHistory est le nombre de révisions conservées par serveur.
+	maxHistory = 200
+	// Always est le mot-clé d'une entrée injectée dans toutes les conversations.
+	Always = "*"
+)
+
+// ErrNotFound est retournée quand une entrée ou une révision n'existe pas.
+var ErrNotFound = errors.New("entrée introuvable")
+
+// Entry est une entrée de la fiche d'un serveur.
+type Entry struct {
+	ID          int       `json:"id"`
+	Title       string    `json:"title"`
+	Keywords    []string  `json:"keywords"`
+	Text        string    `json:"text"`
+	Version     int       `json:"version"` // Numéro de la révision qui a produit ce contenu
+	UpdatedBy   string    `json:"updated_by"`
+	UpdatedByID string    `json:"updated_by_id,omitempty"` // ID Discord de l'auteur (données personnelles)
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+// Editor est l'administrateur à l'origine d'une modification.
+type Editor struct {
+	ID   string // ID Discord
+	Name string // Pseudo affiché dans l'historique
+}
+
+// Action est le type d'une révision.
+type Action string
+
+const (
+	Added    Action = "ajout"
+	Edited   Action = "modification"
+	Deleted  Action = "suppression"
+	Restored Action = "restauration"
+)
+
+// Revision est une modification de la fiche. Entry est le contenu après la modification
+// (avant, pour une suppression).
+type Revision struct {
+	Version int       `json:"version"`
+	Action  Action    `json:"action"`
+	Entry   Entry     `json:"entry"`
+	By      string    `json:"by"`
+	ByID    string    `json:"by_id,omitempty"` // ID Discord de l'auteur (données personnelles)
+	At      time.Time `json:"at"`
+}
+
+// sheet est la fiche d'un serveur.
+type sheet struct {
+	Entries []Entry    `json:"entries"`
+	History []Revision `json:"history"`
+	Version int        `json:"version"` // Dernier numéro de révision attribué
+	NextID  int        `json:"next_id"`
+}
+
+// Store est la base des fiches, persistée dans un fichier JSON.
+type Store struct {
+	mu     sync.Mutex
+	file   *storage.JSONFile
+	sheets map[string]*sheet // Serveur Discord → fiche
+}
+
+// Open charge les fiches (aucune si le fichier n'existe pas encore).
+func Open(path string) (*Store, error) {
+	s := &Store{file: storage.NewJSONFile(path), sheets: map[string]*sheet{}}
+	if err := s.file.Load(&s.sheets); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// ParseKeywords découpe une liste de mots-clés séparés par des virgules.
+func ParseKeywords(s string) []string {
+	var out []string
+	for _, k := range strings.Split(s, ",") {
+		if k = strings.TrimSpace(k); k != "" && !slices.Contains(out, k) {
+			out = append(out, k)
+		}
+	}
+	return out
+}
+
+// Add ajoute une entrée à la fiche d'un serveur.
+func (s *Store) Add(guildID, title, keywords, text string, by Editor, now time.Time) (Entry, error) {
+	e := Entry{Title: title, Keywords: ParseKeywords(keywords), Text: text}
+	return s.change(guildID, Added, by, now, func(sh *sheet) (Entry, error) {
+		if len(sh.Entries) >= MaxEntries {
+			return Entry{}, fmt.Errorf("%d entrées maximum par serveur", MaxEntries)
+		}
+		sh.NextID++
+		e.ID = sh.NextID
+		sh.Entries = append(sh.Entries, e)
+		return e, nil
+	})
+}
+
+// Edit modifie une entrée ; les champs vides sont conservés.
+func (s *Store) Edit(guildID string, id int, title, keywords, text string, by Editor, now time.Time) (Entry, error) {
+	return s.change(guildID, Edited, by, now, func(sh *sheet) (Entry, error) {
+		i := sh.index(id)
+		if i < 0 {
+			return Entry{}, ErrNotFound
+		}
+		e := sh.Entries[i]
+		if title != "" {
+			e.Title = title
+		}
+		if keywords != "" {
+			e.Keywords = ParseKeywords(keywords)
+		}
+		if text != "" {
+			e.Text = text
+		}
+		sh.Entries[i] = e
+		return e, nil
+	})
+}
+
+// Delete supprime une entrée (restaurable depuis l'historique).
+func (s *Store) Delete(guildID string, id int, by Editor, now time.Time) (Entry, error) {
+	return s.change(guildID, Deleted, by, now, func(sh *sheet) (Entry, error) {
+		i := sh.index(id)
+		if i < 0 {
+			return Entry{}, ErrNotFound
+		}
+		e := sh.Entries[i]
+		sh.Entries = slices.Delete(sh.Entries, i, i+1)
+		return e, nil
+	})
+}
+
+// Restore rétablit le contenu d'une entrée tel qu'il était à une révision (y compris
+// une entrée supprimée depuis).
+func (s *Store) Restore(guildID string, version int, by Editor, now time.Time) (Entry, error) {
+	return s.change(guildID, Restored, by, now, func(sh *sheet) (Entry, error) {
+		j := slices.IndexFunc(sh.History, func(r Revision) bool { return r.Version == version })
+		if j < 0 {
+			return Entry{}, fmt.Errorf("révision %d introuvable", version)
+		}
+		e := sh.History[j].Entry
+		if i := sh.index(e.ID); i >= 0 {
+			sh.Entries[i] = e
+			return e, nil
+		}
+		if len(sh.Entries) >= MaxEntries {
+			return Entry{}, fmt.Errorf("%d entrées maximum par serveur", MaxEntries)
+		}
+		sh.Entries = append(sh.Entries, e)
+		slices.SortFunc(sh.Entries, func(a, b Entry) int { return a.ID - b.ID })
+		return e, nil
+	})
+}
+
+// Entries retourne les entrées d'un serveur.
+func (s *Store) Entries(guildID string) []Entry {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	sh, ok := s.sheets[guildID]
+	if !ok {
+		return nil
+	}
+	return cloneEntries(sh.Entries)
+}
+
+// History retourne les révisions d'un serveur (toutes si id vaut 0), de la plus récente à la plus ancienne.
+func (s *Store) History(guildID string, id int) []Revision {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	sh, ok := s.sheets[guildID]
+	if !ok {
+		return nil
+	}
+	var out []Revision
+	for _, r := range slices.Backward(sh.History) {
+		if id == 0 || r.Entry.ID == id {
+			r.Entry.Keywords = slices.Clone(r.Entry.Keywords)
+			out = append(out, r)
+		}
+	}
+	return out
+}
+
+// ByEditor retourne, par serveur, les révisions faites par un administrateur, désigné par son
+// ID Discord (de la plus ancienne à la plus récente).
+func (s *Store) ByEditor(editorID string) map[string][]Revision {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	out := map[string][]Revision{}
+	for guildID, sh := range s.sheets {
+		for _, r := range sh.History {
+			if r.ByID == editorID {
+				r.Entry.Keywords = slices.Clone(r.Entry.Keywords)
+				out[guildID] = append(out[guildID], r)
+			}
+		}
+	}
+	return out
+}
+
+// ForgetEditor anonymise les entrées et révisions d'un administrateur : son nom et son ID
+// sont effacés, le contenu, qui appartient à la fiche du serveur, est conservé.
+func (s *Store) ForgetEditor(editorID string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	old := s.sheets
+	work := make(map[string]*sheet, len(s.sheets))
+	changed := false
+	for guildID, sh := range s.sheets {
+		c := *sh
+		c.Entries = cloneEntries(sh.Entries)
+		c.History = slices.Clone(sh.History)
+		for i, e := range c.Entries {
+			if e.UpdatedByID == editorID {
+				c.Entries[i].UpdatedBy, c.Entries[i].UpdatedByID = "", ""
+				changed = true
+			}
+		}
+		for i, r := range c.History {
+			if r.Entry.UpdatedByID == editorID {
+				c.History[i].Entry.UpdatedBy, c.History[i].Entry.UpdatedByID = "", ""
+				changed = true
+			}
+			if r.ByID == editorID {
+				c.History[i].By, c.History[i].ByID = "", ""
+				changed = true
+			}
+		}
+		work[guildID] = &c
+	}
+	if !changed {
+		return nil
+	}
+	s.sheets = work
+	if err := s.file.Save(s.sheets); err != nil {
+		s.sheets = old
+		return err
+	}
+	return nil
+}
+
+// Relevant retourne les entrées évoquées par un message (mots-clés ou titre), les entrées
+// permanentes (mot-clé "*") en premier, dans la limite de maxChars de texte.
+func (s *Store) Relevant(guildID, message string, maxChars int) []Entry {
+	msg := " " + items.Normalize(message) + " "
+
+	type scored struct {
+		e     Entry
+		score int
+	}
+	var found []scored
+	for _, e := range s.Entries(guildID) {
+		score := 0
+		for _, k := range e.Keywords {
+			switch {
+			case k == Always:
+				score += 100
+			case strings.Contains(msg, " "+items.Normalize(k)+" "):
+				score += 10
+			}
+		}
+		if t := items.Normalize(e.Title); t != "" && strings.Contains(msg, " "+t+" ") {
+			score += 5
+		}
+		if score > 0 {
+			found = append(found, scored{e, score})
+		}
+	}
+	slices.SortStableFunc(found, func(a, b scored) int { return b.score - a.score })
+
+	var out []Entry
+	total := 0
+	for _, f := range found {
+		if total+len(f.e.Text) > maxChars {
+			continue
+		}
+		total += len(f.e.Text)
+		out = append(out, f.e)
+	}
+	return out
+}
+
+// change applique fn à la fiche d'un serveur, vérifie les limites, enregistre la révision
+// puis persiste. En cas d'échec la fiche est laissée intacte.
+func (s *Store) change(guildID string, action Action, by Editor, now time.Time, fn func(sh *sheet) (Entry, error)) (Entry, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	old := s.sheets[guildID]
+	work := &sheet{}
+	if old != nil {
+		*work = *old
+		work.Entries = cloneEntries(old.Entries)
+		work.History = slices.Clone(old.History)
+	}
+
+	e, err := fn(work)
+	if err != nil {
+		return Entry{}, err
+	}
+	work.Version++
+	if action != Deleted {
+		e.Version = work.Version
+		e.UpdatedBy, e.UpdatedByID = by.Name, by.ID
+		e.UpdatedAt = now
+		if err := e.validate(); err != nil {
+			return Entry{}, err
+		}
+		work.Entries[work.index(e.ID)] = e
+		if err := work.checkTotal(); err != nil {
+			return Entry{}, err
+		}
+	}
+	work.History = append(work.History, Revision{Version: work.Version, Action: action, Entry: e, By: by.Name, ByID: by.ID, At: now})
+	if len(work.History) > maxHistory {
+		work.History = work.History[len(work.History)-maxHistory:]
+	}
+
+	s.sheets[guildID] = work
+	if err := s.file.Save(s.sheets); err != nil {
+		if old == nil {
+			delete(s.sheets, guildID)
+		} else {
+			s.sheets[guildID] = old
+		}
+		return Entry{}, err
+	}
+	return e, nil
+}
+
+// validate vérifie une entrée.
+func (e *Entry) validate() error {
+	e.Title = strings.TrimSpace(e.Title)
+	e.Text = strings.TrimSpace(e.Text)
+	switch {
+	case e.Title == "":
+		return errors.New("titre manquant")
+	case e.Text == "":
+		return errors.New("texte manquant")
+	case utf8.RuneCountInString(e.Text) > MaxText:
+		return fmt.Errorf("texte trop long (%d caractères maximum)", MaxText)
+	case len(e.Keywords) == 0:
+		return fmt.Errorf("au moins un mot-clé est nécessaire (%q pour toujours injecter l'entrée)", Always)
+	case len(e.Keywords) > MaxKeywords:
+		return fmt.Errorf("%d mots-clés maximum", MaxKeywords)
+	}
+	return nil
+}
+
+// checkTotal vérifie la taille cumulée des entrées.
+func (sh *sheet) checkTotal() error {
+	total := 0
+	for _, e := range sh.Entries {
+		total += utf8.RuneCountInString(e.Text)
+	}
+	if total > MaxTotal {
+		return fmt.Errorf("la fiche dépasserait %d caractères au total (%d) : raccourcis ou supprime des entrées", MaxTotal, total)
+	}
+	return nil
+}
+
+// index retourne la position d'une entrée, -1 si absente.
+func (sh *sheet) index(id int) int {
+	return slices.IndexFunc(sh.Entries, func(e Entry) bool { return e.ID == id })
+}
+
+// Format présente une entrée en une ligne d'en-tête ("#3 Recrutement [recrutement, postuler] v7").
+func (e Entry) Format() string {
+	return "#" + strconv.Itoa(e.ID) + " " + e.Title + " [" + strings.Join(e.Keywords, ", ") + "] v" + strconv.Itoa(e.Version)
+}
+
+// cloneEntries copie des entrées (et leurs mots-clés).
+func cloneEntries(entries []Entry) []Entry {
+	out := slices.Clone(entries)
+	for i := range out {
+		out[i].Keywords = slices.Clone(out[i].Keywords)
+	}
+	return out
+}
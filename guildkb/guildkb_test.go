@@ -0,0 +1,83 @@
+package guildkb
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestByEditorForgetEditor(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "guildkb.json")
+	s, err := Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	now := time.Date(2026, 10, 18, 20, 0, 0, 0, time.UTC)
+	alice := Editor{ID: "1", Name: "alice"}
+	bob := Editor{ID: "2", Name: "bob"}
+
+	rules, err := s.Add("g1", "Règlement", "règles", "Pas de spam.", alice, now)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.Add("g2", "Recrutement", "recrutement", "Niveau 150 minimum.", alice, now); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.Edit("g1", rules.ID, "", "", "Pas de spam ni de pub.", bob, now); err != nil {
+		t.Fatal(err)
+	}
+	// Un autre administrateur qui a repris l'ancien pseudo d'alice : seul l'ID fait foi
+	if _, err := s.Add("g1", "Blagues", "*", "Le Bouftou royal est notre mascotte.", Editor{ID: "4", Name: "alice"}, now); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name     string
+		editorID string
+		want     map[string]int // Serveur → nombre de révisions
+	}{
+		{"auteur", "1", map[string]int{"g1": 1, "g2": 1}},
+		{"même pseudo, autre ID", "4", map[string]int{"g1": 1}},
+		{"autre administrateur", "2", map[string]int{"g1": 1}},
+		{"inconnu", "3", map[string]int{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := s.ByEditor(tt.editorID)
+			if len(got) != len(tt.want) {
+				t.Errorf("ByEditor() = %v, attendu %v", got, tt.want)
+			}
+			for guild, n := range tt.want {
+				if len(got[guild]) != n {
+					t.Errorf("ByEditor()[%s] = %d révision(s), attendu %d", guild, len(got[guild]), n)
+				}
+			}
+		})
+	}
+
+	if err := s.ForgetEditor(alice.ID); err != nil {
+		t.Fatal(err)
+	}
+	reopened, err := Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, store := range []*Store{s, reopened} {
+		if got := store.ByEditor(alice.ID); len(got) != 0 {
+			t.Errorf("ByEditor(alice) après oubli = %v", got)
+		}
+		if got := store.ByEditor(bob.ID); len(got["g1"]) != 1 {
+			t.Errorf("ByEditor(bob) après oubli d'alice = %v, attendu sa révision", got)
+		}
+		// Le contenu de la fiche est conservé, sans le nom de son auteur ; l'homonyme garde le sien
+		entries := store.Entries("g1")
+		if len(entries) != 2 || entries[0].Text != "Pas de spam ni de pub." || entries[0].UpdatedBy != "bob" || entries[1].UpdatedBy != "alice" {
+			t.Errorf("Entries(g1) = %+v", entries)
+		}
+		for _, r := range store.History("g2", 0) {
+			if r.By != "" || r.ByID != "" || r.Entry.UpdatedBy != "" {
+				t.Errorf("révision %d non anonymisée : %+v", r.Version, r)
+			}
+		}
+	}
+}
@@ -0,0 +1,103 @@
+package items
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Lookup recherche un objet ou une panoplie par nom et retourne une fiche texte destinée au LLM.
+// Si rien ne correspond, le texte indique de se rabattre sur la recherche web.
+func (db *Database) Lookup(name string) string {
+	if it, ok := db.Item(name); ok {
+		return db.describeItem(it)
+	}
+	if s, ok := db.Set(name); ok {
+		return describeSet(s)
+	}
+
+	candidates := db.Search(name, 5)
+	switch len(candidates) {
+	case 0:
+		return fmt.Sprintf("INCONNU: aucun objet nommé %q dans la base locale. "+
+			"Utilise l'outil search_internet pour trouver l'information.", name)
+	case 1:
+		return db.describeItem(candidates[0])
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "Plusieurs objets correspondent à %q, demande à l'utilisateur de préciser ou choisis le bon :\n", name)
+	for _, c := range candidates {
+		fmt.Fprintf(&b, "- %s (%s", c.Name, c.Type)
+		if c.Level > 0 {
+			fmt.Fprintf(&b, ", niveau %d", c.Level)
+		}
+		b.WriteString(")\n")
+	}
+	return b.String()
+}
+
+// describeItem formate la fiche complète d'un objet.
+func (db *Database) describeItem(it Item) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "SOURCE: base de données locale des objets Dofus\n%s — %s", it.Name, it.Type)
+	if it.Level > 0 {
+		fmt.Fprintf(&b, " de niveau %d", it.Level)
+	}
+	b.WriteString("\n")
+	if it.Description != "" {
+		fmt.Fprintf(&b, "Description : %s\n", it.Description)
+	}
+	if len(it.Effects) > 0 {
+		b.WriteString("Effets :\n")
+		writeEffects(&b, it.Effects)
+	}
+	if len(it.Conditions) > 0 {
+		fmt.Fprintf(&b, "Conditions : %s\n", strings.Join(it.Conditions, ", "))
+	}
+	if len(it.Recipe) > 0 {
+		b.WriteString("Recette")
+		if it.Job != "" {
+			fmt.Fprintf(&b, " (%s)", it.Job)
+		}
+		b.WriteString(" :\n")
+		for _, ing := range it.Recipe {
+			fmt.Fprintf(&b, "- %d x %s\n", ing.Quantity, ing.Name)
+		}
+	}
+	if it.Set != "" {
+		fmt.Fprintf(&b, "Fait partie de la panoplie : %s\n", it.Set)
+		if s, ok := db.Set(it.Set); ok && len(s.Bonuses) > 0 {
+			b.WriteString(describeSetBonuses(s))
+		}
+	}
+	return b.String()
+}
+
+// describeSet formate la fiche d'une panoplie.
+func describeSet(s Set) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "SOURCE: base de données locale des objets Dofus\nPanoplie %s\nObjets : %s\n", s.Name, strings.Join(s.Items, ", "))
+	b.WriteString(describeSetBonuses(s))
+	return b.String()
+}
+
+// describeSetBonuses formate les bonus d'une panoplie par nombre d'objets.
+func describeSetBonuses(s Set) string {
+	var b strings.Builder
+	for _, bonus := range s.Bonuses {
+		fmt.Fprintf(&b, "Bonus %d objets :\n", bonus.Pieces)
+		writeEffects(&b, bonus.Effects)
+	}
+	return b.String()
+}
+
+// writeEffects écrit une liste d'effets, avec leur plage éventuelle.
+func writeEffects(b *strings.Builder, effects []Effect) {
+	for _, e := range effects {
+		if e.Max > e.Min {
+			fmt.Fprintf(b, "- %d à %d %s\n", e.Min, e.Max, e.Name)
+		} else {
+			fmt.Fprintf(b, "- %d %s\n", e.Min, e.Name)
+		}
+	}
+}
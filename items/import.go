@@ -0,0 +1,205 @@
+package items
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+)
+
+// Import convertit un dump communautaire (ex: export de l'API DofusDB) au format de la base.
+//
+// Le format source est toléré assez largement : tableau d'objets ou objet {"data": [...]},
+// noms localisés ({"fr": "..."}) ou simples, effets avec "from"/"to" ou "min"/"max",
+// recette avec "name" ou "itemName". Les objets sans nom sont ignorés.
+func Import(r io.Reader) (Dump, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return Dump{}, fmt.Errorf("lecture du dump: %w", err)
+	}
+
+	var raw []rawItem
+	if err := json.Unmarshal(data, &raw); err != nil {
+		var wrapped struct {
+			Data  []rawItem `json:"data"`
+			Items []rawItem `json:"items"`
+			Sets  []rawSet  `json:"sets"`
+		}
+		if err2 := json.Unmarshal(data, &wrapped); err2 != nil {
+			return Dump{}, fmt.Errorf("format de dump non reconnu: %w", err)
+		}
+		raw = append(wrapped.Data, wrapped.Items...)
+		dump := convertItems(raw)
+		for _, s := range wrapped.Sets {
+			dump.Sets = append(dump.Sets, s.convert())
+		}
+		return dump, nil
+	}
+	return convertItems(raw), nil
+}
+
+// convertItems convertit les objets bruts en ignorant ceux sans nom.
+func convertItems(raw []rawItem) Dump {
+	var dump Dump
+	for _, r := range raw {
+		it := r.convert()
+		if it.Name == "" {
+			continue
+		}
+		dump.Items = append(dump.Items, it)
+	}
+	return dump
+}
+
+// localized accepte une chaîne simple ou un objet de traductions ({"fr": "...", "en": "..."}).
+type localized string
+
+// UnmarshalJSON implémente json.Unmarshaler en privilégiant le français.
+func (l *localized) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err == nil {
+		*l = localized(s)
+		return nil
+	}
+	var m map[string]string
+	if err := json.Unmarshal(data, &m); err != nil {
+		return nil // Champ inattendu : ignoré plutôt que de rejeter tout le dump
+	}
+	for _, lang := range []string{"fr", "en"} {
+		if v, ok := m[lang]; ok {
+			*l = localized(v)
+			return nil
+		}
+	}
+	return nil
+}
+
+// typeRef accepte un type sous forme de chaîne ou d'objet {"name": ...}.
+type typeRef string
+
+// UnmarshalJSON implémente json.Unmarshaler.
+func (t *typeRef) UnmarshalJSON(data []byte) error {
+	var l localized
+	if err := l.UnmarshalJSON(data); err == nil && l != "" {
+		*t = typeRef(l)
+		return nil
+	}
+	var obj struct {
+		Name localized `json:"name"`
+	}
+	if err := json.Unmarshal(data, &obj); err == nil {
+		*t = typeRef(obj.Name)
+	}
+	return nil
+}
+
+type rawEffect struct {
+	Name           localized `json:"name"`
+	Characteristic localized `json:"characteristic"`
+	Description    localized `json:"description"`
+	From           *int      `json:"from"`
+	To             *int      `json:"to"`
+	Min            *int      `json:"min"`
+	Max            *int      `json:"max"`
+}
+
+func (r rawEffect) convert() Effect {
+	e := Effect{Name: string(firstNonEmpty(r.Name, r.Characteristic, r.Description))}
+	switch {
+	case r.From != nil:
+		e.Min = *r.From
+		if r.To != nil {
+			e.Max = *r.To
+		}
+	case r.Min != nil:
+		e.Min = *r.Min
+		if r.Max != nil {
+			e.Max = *r.Max
+		}
+	}
+	return e
+}
+
+type rawIngredient struct {
+	Name     localized `json:"name"`
+	ItemName localized `json:"itemName"`
+	Quantity int       `json:"quantity"`
+}
+
+type rawItem struct {
+	ID          int             `json:"id"`
+	Name        localized       `json:"name"`
+	Type        typeRef         `json:"type"`
+	Level       int             `json:"level"`
+	Set         localized       `json:"set"`
+	SetName     localized       `json:"setName"`
+	Description localized       `json:"description"`
+	Effects     []rawEffect     `json:"effects"`
+	Conditions  []localized     `json:"conditions"`
+	Recipe      []rawIngredient `json:"recipe"`
+	Job         localized       `json:"job"`
+}
+
+func (r rawItem) convert() Item {
+	it := Item{
+		ID:          r.ID,
+		Name:        string(r.Name),
+		Type:        string(r.Type),
+		Level:       r.Level,
+		Set:         string(firstNonEmpty(r.Set, r.SetName)),
+		Description: string(r.Description),
+		Job:         string(r.Job),
+	}
+	for _, e := range r.Effects {
+		if eff := e.convert(); eff.Name != "" {
+			it.Effects = append(it.Effects, eff)
+		}
+	}
+	for _, c := range r.Conditions {
+		if c != "" {
+			it.Conditions = append(it.Conditions, string(c))
+		}
+	}
+	for _, ing := range r.Recipe {
+		name := firstNonEmpty(ing.Name, ing.ItemName)
+		if name != "" && ing.Quantity > 0 {
+			it.Recipe = append(it.Recipe, Ingredient{Name: string(name), Quantity: ing.Quantity})
+		}
+	}
+	return it
+}
+
+type rawSetBonus struct {
+	Pieces  int         `json:"pieces"`
+	Effects []rawEffect `json:"effects"`
+}
+
+type rawSet struct {
+	Name    localized     `json:"name"`
+	Items   []localized   `json:"items"`
+	Bonuses []rawSetBonus `json:"bonuses"`
+}
+
+func (r rawSet) convert() Set {
+	s := Set{Name: string(r.Name)}
+	for _, it := range r.Items {
+		s.Items = append(s.Items, string(it))
+	}
+	for _, b := range r.Bonuses {
+		bonus := SetBonus{Pieces: b.Pieces}
+		for _, e := range b.Effects {
+			bonus.Effects = append(bonus.Effects, e.convert())
+		}
+		s.Bonuses = append(s.Bonuses, bonus)
+	}
+	return s
+}
+
+// firstNonEmpty retourne la première valeur non vide.
+func firstNonEmpty(values ...localized) localized {
+	for _, v := range values {
+		if v != "" {
+			return v
+		}
+	}
+	return ""
+}
@@ -0,0 +1,214 @@
+// Package items implémente la base locale des objets Dofus (équipements, ressources,
+// panoplies) et son index de recherche par nom. Les données viennent d'un fichier JSON
+// embarqué, remplaçable par un export importé depuis les dumps communautaires.
+package items
+
+import (
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+)
+
+//go:embed data/items.json
+var bundled []byte
+
+// Item est un objet Dofus (équipement, arme, consommable, ressource...).
+type Item struct {
+	ID          int          `json:"id"`
+	Name        string       `json:"name"`
+	Type        string       `json:"type"`            // Ex: Chapeau, Amulette, Ressource
+	Level       int          `json:"level,omitempty"` // 0 si inconnu
+	Set         string       `json:"set,omitempty"`   // Nom de la panoplie éventuelle
+	Description string       `json:"description,omitempty"`
+	Effects     []Effect     `json:"effects,omitempty"`
+	Conditions  []string     `json:"conditions,omitempty"`
+	Recipe      []Ingredient `json:"recipe,omitempty"`
+	Job         string       `json:"job,omitempty"` // Métier qui fabrique l'objet
+}
+
+// Effect est une caractéristique apportée par un objet (ex: Vitalité 150 à 200).
+type Effect struct {
+	Name string `json:"name"`
+	Min  int    `json:"min"`
+	Max  int    `json:"max,omitempty"` // 0 si l'effet est fixe
+}
+
+// Ingredient est une ligne de recette.
+type Ingredient struct {
+	Name     string `json:"name"`
+	Quantity int    `json:"quantity"`
+}
+
+// Set est une panoplie et ses bonus selon le nombre d'objets équipés.
+type Set struct {
+	Name    string     `json:"name"`
+	Items   []string   `json:"items"`
+	Bonuses []SetBonus `json:"bonuses,omitempty"`
+}
+
+// SetBonus est le bonus d'une panoplie pour un nombre d'objets équipés.
+type SetBonus struct {
+	Pieces  int      `json:"pieces"`
+	Effects []Effect `json:"effects"`
+}
+
+// Dump est le format JSON de la base (fichier embarqué ou importé).
+type Dump struct {
+	Items []Item `json:"items"`
+	Sets  []Set  `json:"sets"`
+}
+
+// Database est la base indexée, en lecture seule après chargement.
+type Database struct {
+	items      []Item
+	sets       []Set
+	byName     map[string]int   // Nom normalisé → index dans items
+	setsByName map[string]int   // Nom normalisé → index dans sets
+	tokens     map[string][]int // Mot normalisé → objets contenant ce mot
+	withStats  int              // Nombre d'objets avec des effets
+	recipes    int              // Nombre d'objets avec une recette
+}
+
+// Load charge la base depuis un fichier JSON, ou la base embarquée si path est vide.
+func Load(path string) (*Database, error) {
+	data := bundled
+	if path != "" {
+		var err error
+		data, err = os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("lecture de la base d'objets: %w", err)
+		}
+	}
+
+	var dump Dump
+	if err := json.Unmarshal(data, &dump); err != nil {
+		return nil, fmt.Errorf("décodage de la base d'objets: %w", err)
+	}
+	return New(dump), nil
+}
+
+// New indexe les objets et panoplies d'un dump.
+func New(dump Dump) *Database {
+	db := &Database{
+		items:      dump.Items,
+		sets:       dump.Sets,
+		byName:     make(map[string]int, len(dump.Items)),
+		setsByName: make(map[string]int, len(dump.Sets)),
+		tokens:     make(map[string][]int),
+	}
+	for i, it := range db.items {
+		if len(it.Effects) > 0 {
+			db.withStats++
+		}
+		if len(it.Recipe) > 0 {
+			db.recipes++
+		}
+		key := Normalize(it.Name)
+		db.byName[key] = i
+		for _, tok := range strings.Fields(key) {
+			db.tokens[tok] = append(db.tokens[tok], i)
+		}
+	}
+	for i, s := range db.sets {
+		db.setsByName[Normalize(s.Name)] = i
+	}
+	return db
+}
+
+// Len retourne le nombre d'objets de la base.
+func (db *Database) Len() int {
+	return len(db.items)
+}
+
+// HasStats indique si la base contient des caractéristiques d'objets ou de panoplies. La base
+// embarquée n'en a pas : elle ne liste que quelques ressources, sans effets ni recettes.
+func (db *Database) HasStats() bool {
+	return db.withStats > 0 || len(db.sets) > 0
+}
+
+// HasRecipes indique si la base contient des recettes de fabrication.
+func (db *Database) HasRecipes() bool {
+	return db.recipes > 0
+}
+
+// Item retourne l'objet portant exactement ce nom (à la casse et aux accents près).
+func (db *Database) Item(name string) (Item, bool) {
+	i, ok := db.byName[Normalize(name)]
+	if !ok {
+		return Item{}, false
+	}
+	return db.items[i], true
+}
+
+// Set retourne la panoplie portant exactement ce nom (à la casse et aux accents près).
+func (db *Database) Set(name string) (Set, bool) {
+	i, ok := db.setsByName[Normalize(name)]
+	if !ok {
+		return Set{}, false
+	}
+	return db.sets[i], true
+}
+
+// Search retourne au plus limit objets dont le nom contient le plus de mots de la requête.
+// Seuls les objets contenant tous les mots significatifs (≥ 3 lettres) sont retenus.
+func (db *Database) Search(query string, limit int) []Item {
+	var words []string
+	for _, w := range strings.Fields(Normalize(query)) {
+		if len([]rune(w)) >= 3 {
+			words = append(words, w)
+		}
+	}
+	if len(words) == 0 {
+		return nil
+	}
+
+	hits := make(map[int]int)
+	for _, w := range words {
+		for _, idx := range db.tokens[w] {
+			hits[idx]++
+		}
+	}
+
+	var matches []int
+	for idx, n := range hits {
+		if n == len(words) {
+			matches = append(matches, idx)
+		}
+	}
+	// Les noms les plus courts (donc les plus proches de la requête) d'abord
+	sort.Slice(matches, func(a, b int) bool {
+		na, nb := db.items[matches[a]].Name, db.items[matches[b]].Name
+		if len(na) != len(nb) {
+			return len(na) < len(nb)
+		}
+		return na < nb
+	})
+
+	results := make([]Item, 0, min(limit, len(matches)))
+	for _, idx := range matches[:min(limit, len(matches))] {
+		results = append(results, db.items[idx])
+	}
+	return results
+}
+
+// accents remplace les lettres accentuées françaises par leur équivalent sans accent.
+var accents = strings.NewReplacer(
+	"à", "a", "â", "a", "ä", "a", "á", "a",
+	"ç", "c",
+	"é", "e", "è", "e", "ê", "e", "ë", "e",
+	"î", "i", "ï", "i", "í", "i",
+	"ô", "o", "ö", "o", "ó", "o",
+	"ù", "u", "û", "u", "ü", "u", "ú", "u",
+	"ÿ", "y", "œ", "oe", "æ", "ae",
+)
+
+// Normalize met un nom en minuscules, sans accents ni ponctuation, pour la recherche.
+func Normalize(s string) string {
+	s = accents.Replace(strings.ToLower(s))
+	return strings.Join(strings.FieldsFunc(s, func(r rune) bool {
+		return !(r >= 'a' && r <= 'z' || r >= '0' && r <= '9')
+	}), " ")
+}
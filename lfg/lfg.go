@@ -0,0 +1,296 @@
+// Package lfg gère les sorties de groupe (donjons, boss) organisées sur Discord :
+// créneau, places par rôle, inscriptions des joueurs, persistées dans un fichier JSON.
+package lfg
+
+import (
+	"errors"
+	"fmt"
+	"otom-ai/storage"
+	"slices"
+	"sync"
+	"time"
+)
+
+// Role est le rôle d'un joueur dans le groupe.
+type Role string
+
+const (
+	Tank Role = "tank"
+	Heal Role = "heal"
+	DPS  Role = "dps"
+)
+
+// Roles liste les rôles dans l'ordre d'affichage.
+var Roles = []Role{Tank, Heal, DPS}
+
+// Label retourne le libellé français d'un rôle.
+func (r Role) Label() string {
+	switch r {
+	case Tank:
+		return "Tank"
+	case Heal:
+		return "Soin"
+	case DPS:
+		return "DPS"
+	}
+	return string(r)
+}
+
+// Emoji retourne l'emoji associé à un rôle.
+func (r Role) Emoji() string {
+	switch r {
+	case Tank:
+		return "🛡️"
+	case Heal:
+		return "💚"
+	}
+	return "⚔️"
+}
+
+// ParseRole valide un rôle.
+func ParseRole(s string) (Role, error) {
+	r := Role(s)
+	if !slices.Contains(Roles, r) {
+		return "", fmt.Errorf("rôle inconnu %q", s)
+	}
+	return r, nil
+}
+
+// Erreurs retournées lors des inscriptions.
+var (
+	ErrNotFound = errors.New("sortie introuvable")
+	ErrFull     = errors.New("plus de place pour ce rôle")
+	ErrStarted  = errors.New("la sortie a déjà commencé")
+)
+
+// Signup est l'inscription d'un joueur.
+type Signup struct {
+	UserID string    `json:"user_id"`
+	Role   Role      `json:"role"`
+	At     time.Time `json:"at"`
+}
+
+// Event est une sortie de groupe.
+type Event struct {
+	ID        string       `json:"id"`
+	GuildID   string       `json:"guild_id"`
+	ChannelID string       `json:"channel_id"`
+	MessageID string       `json:"message_id"` // Message d'inscription (boutons)
+	CreatorID string       `json:"creator_id"`
+	Dungeon   string       `json:"dungeon"` // Donjon ou boss
+	Start     time.Time    `json:"start"`
+	Slots     map[Role]int `json:"slots"`   // Places par rôle
+	Classes   string       `json:"classes"` // Classes souhaitées (texte libre)
+	Note      string       `json:"note"`
+	Signups   []Signup     `json:"signups"`
+}
+
+// Count retourne le nombre d'inscrits pour un rôle.
+func (e Event) Count(role Role) int {
+	n := 0
+	for _, s := range e.Signups {
+		if s.Role == role {
+			n++
+		}
+	}
+	return n
+}
+
+// Members retourne les inscrits d'un rôle, dans l'ordre d'inscription.
+func (e Event) Members(role Role) []string {
+	var ids []string
+	for _, s := range e.Signups {
+		if s.Role == role {
+			ids = append(ids, s.UserID)
+		}
+	}
+	return ids
+}
+
+// Size retourne le nombre total de places.
+func (e Event) Size() int {
+	n := 0
+	for _, v := range e.Slots {
+		n += v
+	}
+	return n
+}
+
+// clone copie un événement pour le rendre hors du verrou du Store.
+func (e *Event) clone() Event {
+	c := *e
+	c.Slots = make(map[Role]int, len(e.Slots))
+	for k, v := range e.Slots {
+		c.Slots[k] = v
+	}
+	c.Signups = slices.Clone(e.Signups)
+	return c
+}
+
+// Store est la base des sorties, persistée dans un fichier JSON.
+type Store struct {
+	mu     sync.Mutex
+	file   *storage.JSONFile
+	events map[string]*Event
+}
+
+// Open charge les sorties (aucune si le fichier n'existe pas encore).
+func Open(path string) (*Store, error) {
+	s := &Store{file: storage.NewJSONFile(path), events: map[string]*Event{}}
+	if err := s.file.Load(&s.events); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// Create enregistre une nouvelle sortie et lui attribue un identifiant court.
+func (s *Store) Create(e Event) (Event, error) {
+	if e.Size() == 0 {
+		return Event{}, errors.New("la sortie doit comporter au moins une place")
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for {
+		e.ID = storage.ShortID()
+		if _, exists := s.events[e.ID]; !exists {
+			break
+		}
+	}
+	s.events[e.ID] = &e
+	if err := s.save(); err != nil {
+		delete(s.events, e.ID)
+		return Event{}, err
+	}
+	return e.clone(), nil
+}
+
+// Get retourne une sortie.
+func (s *Store) Get(id string) (Event, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	e, ok := s.events[id]
+	if !ok {
+		return Event{}, false
+	}
+	return e.clone(), true
+}
+
+// SetMessage associe le message d'inscription à la sortie.
+func (s *Store) SetMessage(id, messageID string) error {
+	return s.update(id, func(e *Event) error {
+		e.MessageID = messageID
+		return nil
+	})
+}
+
+// Join inscrit (ou change le rôle d') un joueur.
+func (s *Store) Join(id, userID string, role Role, now time.Time) (Event, error) {
+	var out Event
+	err := s.update(id, func(e *Event) error {
+		if !now.Before(e.Start) {
+			return ErrStarted
+		}
+		e.Signups = slices.DeleteFunc(e.Signups, func(su Signup) bool { return su.UserID == userID })
+		if e.Count(role) >= e.Slots[role] {
+			return ErrFull
+		}
+		e.Signups = append(e.Signups, Signup{UserID: userID, Role: role, At: now})
+		out = e.clone()
+		return nil
+	})
+	return out, err
+}
+
+// Leave désinscrit un joueur.
+func (s *Store) Leave(id, userID string) (Event, error) {
+	var out Event
+	err := s.update(id, func(e *Event) error {
+		e.Signups = slices.DeleteFunc(e.Signups, func(su Signup) bool { return su.UserID == userID })
+		out = e.clone()
+		return nil
+	})
+	return out, err
+}
+
+// Delete supprime une sortie.
+func (s *Store) Delete(id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.events[id]; !ok {
+		return ErrNotFound
+	}
+	delete(s.events, id)
+	return s.save()
+}
+
+// Upcoming retourne les sorties à venir d'un serveur ("" = tous), de la plus proche à la plus lointaine.
+func (s *Store) Upcoming(guildID string, now time.Time) []Event {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var out []Event
+	for _, e := range s.events {
+		if e.Start.After(now) && (guildID == "" || e.GuildID == guildID) {
+			out = append(out, e.clone())
+		}
+	}
+	slices.SortFunc(out, func(a, b Event) int { return a.Start.Compare(b.Start) })
+	return out
+}
+
+// ForUser retourne les sorties créées par un joueur ou auxquelles il est inscrit, triées par date.
+func (s *Store) ForUser(userID string) []Event {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var out []Event
+	for _, e := range s.events {
+		if e.CreatorID == userID || slices.ContainsFunc(e.Signups, func(su Signup) bool { return su.UserID == userID }) {
+			out = append(out, e.clone())
+		}
+	}
+	slices.SortFunc(out, func(a, b Event) int { return a.Start.Compare(b.Start) })
+	return out
+}
+
+// Prune supprime les sorties commencées depuis plus de keep.
+func (s *Store) Prune(now time.Time, keep time.Duration) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	removed := false
+	for id, e := range s.events {
+		if now.Sub(e.Start) > keep {
+			delete(s.events, id)
+			removed = true
+		}
+	}
+	if !removed {
+		return nil
+	}
+	return s.save()
+}
+
+// update applique fn à une sortie puis persiste, sauf si fn échoue (modification annulée).
+func (s *Store) update(id string, fn func(e *Event) error) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	e, ok := s.events[id]
+	if !ok {
+		return ErrNotFound
+	}
+	work := e.clone()
+	if err := fn(&work); err != nil {
+		return err
+	}
+	s.events[id] = &work
+	return s.save()
+}
+
+// save persiste toutes les sorties (appelé verrou pris).
+func (s *Store) save() error {
+	return s.file.Save(s.events)
+}
@@ -0,0 +1,100 @@
+package lfg
+
+import (
+	"errors"
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestForUser(t *testing.T) {
+	s, err := Open(filepath.Join(t.TempDir(), "lfg.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	now := time.Date(2026, 10, 18, 20, 0, 0, 0, time.UTC)
+	slots := map[Role]int{Tank: 1, Heal: 1, DPS: 2}
+	late, err := s.Create(Event{GuildID: "g", CreatorID: "1", Dungeon: "Comte Harebourg", Start: now.Add(48 * time.Hour), Slots: slots})
+	if err != nil {
+		t.Fatal(err)
+	}
+	early, err := s.Create(Event{GuildID: "g", CreatorID: "2", Dungeon: "Bworker", Start: now.Add(24 * time.Hour), Slots: slots})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.Join(early.ID, "1", DPS, now); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		user string
+		want []string
+	}{
+		{"1", []string{early.ID, late.ID}}, // Inscrit à l'une, organisateur de l'autre
+		{"2", []string{early.ID}},
+		{"3", nil},
+	}
+	for _, tt := range tests {
+		var got []string
+		for _, e := range s.ForUser(tt.user) {
+			got = append(got, e.ID)
+		}
+		if !slices.Equal(got, tt.want) {
+			t.Errorf("ForUser(%s) = %v, attendu %v", tt.user, got, tt.want)
+		}
+	}
+}
+
+func TestJoin(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "lfg.json")
+	s, err := Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	now := time.Date(2026, 10, 18, 20, 0, 0, 0, time.UTC)
+	e, err := s.Create(Event{GuildID: "g", CreatorID: "1", Dungeon: "Bworker", Start: now.Add(time.Hour), Slots: map[Role]int{Tank: 1, DPS: 2}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	steps := []struct {
+		name    string
+		id      string
+		user    string
+		role    Role
+		at      time.Time
+		wantErr error
+	}{
+		{"inscription", e.ID, "1", Tank, now, nil},
+		{"rôle complet", e.ID, "2", Tank, now, ErrFull},
+		{"changement de rôle", e.ID, "1", DPS, now, nil},
+		{"place libérée", e.ID, "2", Tank, now, nil},
+		{"dernière place", e.ID, "3", DPS, now, nil},
+		{"sortie complète", e.ID, "4", DPS, now, ErrFull},
+		{"réinscription au même rôle", e.ID, "1", DPS, now, nil},
+		{"changement vers un rôle complet", e.ID, "3", Tank, now, ErrFull},
+		{"heure de départ", e.ID, "4", Heal, e.Start, ErrStarted},
+		{"sortie inconnue", "inconnue", "4", DPS, now, ErrNotFound},
+	}
+	for _, step := range steps {
+		if _, err := s.Join(step.id, step.user, step.role, step.at); !errors.Is(err, step.wantErr) {
+			t.Fatalf("%s : Join(%s, %s) = %v, attendu %v", step.name, step.user, step.role, err, step.wantErr)
+		}
+	}
+
+	// Une inscription refusée ne retire pas le joueur de son rôle actuel
+	reopened, err := Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, store := range []*Store{s, reopened} {
+		got, ok := store.Get(e.ID)
+		if !ok {
+			t.Fatal("sortie introuvable")
+		}
+		if tanks, dps := got.Members(Tank), got.Members(DPS); !slices.Equal(tanks, []string{"2"}) || !slices.Equal(dps, []string{"3", "1"}) {
+			t.Errorf("Members() = tanks %v, dps %v, attendu tanks [2], dps [3 1]", tanks, dps)
+		}
+	}
+}
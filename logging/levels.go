@@ -0,0 +1,131 @@
+package logging
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+	"time"
+)
+
+// SubsystemKey est la clé d'attribut identifiant le sous-système émetteur d'un log.
+const SubsystemKey = "subsystem"
+
+// For retourne un logger dérivé rattaché au sous-système donné (bot, ai, search...).
+func For(logger *slog.Logger, subsystem string) *slog.Logger {
+	return logger.With(slog.String(SubsystemKey, subsystem))
+}
+
+// boost est une élévation temporaire du niveau de log.
+type boost struct {
+	level slog.Level
+	until time.Time
+}
+
+// Levels gère le niveau de log global, les surcharges par sous-système et les
+// élévations temporaires (ex: passage en Debug pendant 15 minutes). Modifiable à chaud.
+type Levels struct {
+	mu        sync.RWMutex
+	base      slog.Level
+	overrides map[string]slog.Level
+	boosts    map[string]boost // "" = tous les sous-systèmes
+}
+
+// NewLevels crée un gestionnaire de niveaux au niveau Info.
+func NewLevels() *Levels {
+	return &Levels{
+		base:      slog.LevelInfo,
+		overrides: make(map[string]slog.Level),
+		boosts:    make(map[string]boost),
+	}
+}
+
+// Set remplace le niveau global et les surcharges par sous-système.
+// Les élévations temporaires en cours sont conservées.
+func (l *Levels) Set(base slog.Level, overrides map[string]slog.Level) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.base = base
+	l.overrides = make(map[string]slog.Level, len(overrides))
+	for k, v := range overrides {
+		l.overrides[k] = v
+	}
+}
+
+// Boost remplace temporairement le seuil de log d'un sous-système ("" = tous) par le niveau donné.
+// Le retour au niveau configuré est automatique après d ; onRevert est alors appelé (si non nil).
+func (l *Levels) Boost(subsystem string, level slog.Level, d time.Duration, onRevert func()) {
+	until := time.Now().Add(d)
+
+	l.mu.Lock()
+	l.boosts[subsystem] = boost{level: level, until: until}
+	l.mu.Unlock()
+
+	time.AfterFunc(d, func() {
+		l.mu.Lock()
+		b, ok := l.boosts[subsystem]
+		expired := ok && !b.until.After(time.Now())
+		if expired {
+			delete(l.boosts, subsystem)
+		}
+		l.mu.Unlock()
+
+		// Un Boost plus récent a pu prolonger l'élévation : on ne notifie que la vraie fin
+		if expired && onRevert != nil {
+			onRevert()
+		}
+	})
+}
+
+// Enabled indique si un log de ce niveau doit être émis pour le sous-système.
+func (l *Levels) Enabled(subsystem string, level slog.Level) bool {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+
+	threshold := l.base
+	if o, ok := l.overrides[subsystem]; ok {
+		threshold = o
+	}
+
+	// Une élévation ciblée sur le sous-système l'emporte sur une élévation globale
+	now := time.Now()
+	for _, key := range []string{"", subsystem} {
+		if b, ok := l.boosts[key]; ok && b.until.After(now) {
+			threshold = b.level
+		}
+	}
+	return level >= threshold
+}
+
+// levelHandler filtre les logs selon Levels, en fonction du sous-système du logger.
+type levelHandler struct {
+	next      slog.Handler
+	levels    *Levels
+	subsystem string
+}
+
+// Enabled implémente slog.Handler.
+func (h *levelHandler) Enabled(_ context.Context, level slog.Level) bool {
+	return h.levels.Enabled(h.subsystem, level)
+}
+
+// Handle implémente slog.Handler.
+func (h *levelHandler) Handle(ctx context.Context, record slog.Record) error {
+	return h.next.Handle(ctx, record)
+}
+
+// WithAttrs implémente slog.Handler et repère l'attribut de sous-système.
+func (h *levelHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	subsystem := h.subsystem
+	for _, a := range attrs {
+		if a.Key == SubsystemKey {
+			subsystem = a.Value.String()
+		}
+	}
+	return &levelHandler{next: h.next.WithAttrs(attrs), levels: h.levels, subsystem: subsystem}
+}
+
+// WithGroup implémente slog.Handler.
+func (h *levelHandler) WithGroup(name string) slog.Handler {
+	return &levelHandler{next: h.next.WithGroup(name), levels: h.levels, subsystem: h.subsystem}
+}
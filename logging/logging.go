@@ -0,0 +1,116 @@
+package logging
+
+import (
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+)
+
+// Config paramètre les logs du bot (section logging de la configuration).
+type Config struct {
+	Format         string            `yaml:"format"`          // "text" ou "json"
+	Level          string            `yaml:"level"`           // debug, info, warn ou error
+	Levels         map[string]string `yaml:"levels"`          // Surcharges par sous-système (bot, ai, search...)
+	File           FileConfig        `yaml:"file"`            // Sortie fichier (stdout si vide)
+	RedactPatterns []string          `yaml:"redact_patterns"` // Regex de données personnelles à masquer dans les logs
+}
+
+// FileConfig paramètre l'écriture des logs dans un fichier avec rotation.
+type FileConfig struct {
+	Path       string `yaml:"path"`
+	MaxSizeMB  int    `yaml:"max_size_mb"` // Taille déclenchant la rotation
+	MaxBackups int    `yaml:"max_backups"` // Nombre d'anciens fichiers conservés
+}
+
+// Logging regroupe le logger du bot et ses réglages modifiables à chaud.
+//
+// Chaîne des handlers : masquage des secrets → filtrage par niveau et sous-système →
+// sortie texte ou JSON (stdout ou fichier avec rotation).
+type Logging struct {
+	Logger   *slog.Logger
+	Levels   *Levels
+	redactor *RedactHandler
+	file     *RotatingFile
+	cfg      Config
+}
+
+// New construit le logger du bot à partir de la configuration.
+func New(cfg Config, secrets []string) (*Logging, error) {
+	var out io.Writer = os.Stdout
+	var file *RotatingFile
+	if cfg.File.Path != "" {
+		f, err := NewRotatingFile(cfg.File.Path, cfg.File.MaxSizeMB, cfg.File.MaxBackups)
+		if err != nil {
+			return nil, err
+		}
+		out, file = f, f
+	}
+
+	// Le handler final laisse tout passer : le filtrage est fait par levelHandler
+	opts := &slog.HandlerOptions{Level: slog.Level(-8)}
+	var base slog.Handler
+	switch cfg.Format {
+	case "json":
+		base = slog.NewJSONHandler(out, opts)
+	default:
+		base = slog.NewTextHandler(out, opts)
+	}
+
+	levels := NewLevels()
+	redactor := NewRedactHandler(&levelHandler{next: base, levels: levels})
+	l := &Logging{
+		Logger:   slog.New(redactor),
+		Levels:   levels,
+		redactor: redactor,
+		file:     file,
+	}
+	if err := l.Apply(cfg, secrets); err != nil {
+		return nil, err
+	}
+	return l, nil
+}
+
+// Apply applique à chaud les niveaux de log et les règles de masquage.
+// Le format et le fichier de sortie ne changent qu'au redémarrage.
+func (l *Logging) Apply(cfg Config, secrets []string) error {
+	base, err := ParseLevel(cfg.Level)
+	if err != nil {
+		return err
+	}
+	overrides := make(map[string]slog.Level, len(cfg.Levels))
+	for subsystem, s := range cfg.Levels {
+		lvl, err := ParseLevel(s)
+		if err != nil {
+			return fmt.Errorf("logging.levels.%s: %w", subsystem, err)
+		}
+		overrides[subsystem] = lvl
+	}
+	if err := l.redactor.SetRules(secrets, cfg.RedactPatterns); err != nil {
+		return err
+	}
+	l.Levels.Set(base, overrides)
+
+	if l.cfg.Format != "" && (l.cfg.Format != cfg.Format || l.cfg.File != cfg.File) {
+		l.Logger.Warn("Format ou fichier de logs modifié : redémarrage nécessaire pour l'appliquer")
+	}
+	l.cfg = cfg
+	return nil
+}
+
+// Close ferme le fichier de logs éventuel.
+func (l *Logging) Close() error {
+	if l.file == nil {
+		return nil
+	}
+	return l.file.Close()
+}
+
+// ParseLevel convertit un nom de niveau (debug, info, warn, error) en slog.Level.
+func ParseLevel(s string) (slog.Level, error) {
+	var lvl slog.Level
+	if err := lvl.UnmarshalText([]byte(s)); err != nil {
+		return 0, fmt.Errorf("niveau de log invalide %q (attendu: debug, info, warn ou error)", s)
+	}
+	return lvl, nil
+}
@@ -0,0 +1,139 @@
+// Package logging fournit les middlewares slog du bot, notamment le masquage
+// des secrets et des données personnelles dans chaque enregistrement de log.
+package logging
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"regexp"
+	"strings"
+	"sync/atomic"
+)
+
+// redacted remplace toute valeur masquée dans les logs.
+const redacted = "[REDACTED]"
+
+// pattern est un motif à masquer et son texte de remplacement.
+type pattern struct {
+	re   *regexp.Regexp
+	repl string
+}
+
+// builtinPatterns repère les formats de secrets connus, même s'ils ne sont pas dans la configuration.
+var builtinPatterns = []pattern{
+	{regexp.MustCompile(`(?i)bearer\s+[A-Za-z0-9._~+/=-]+`), "Bearer " + redacted},                            // En-tête Authorization
+	{regexp.MustCompile(`\bsk-[A-Za-z0-9]{16,}\b`), redacted},                                                 // Clés DeepSeek / OpenAI
+	{regexp.MustCompile(`\btvly-[A-Za-z0-9-]{16,}\b`), redacted},                                              // Clés Tavily
+	{regexp.MustCompile(`[MNO][A-Za-z\d_-]{23,27}\.[A-Za-z\d_-]{6}\.[A-Za-z\d_-]{27,40}`), redacted},          // Tokens de bot Discord
+	{regexp.MustCompile(`("(?:api_key|apiKey|token|access_token)"\s*:\s*)"[^"]*"`), `${1}"` + redacted + `"`}, // Clés dans un corps JSON
+}
+
+// rules est l'ensemble des règles de masquage actives.
+type rules struct {
+	secrets  []string
+	patterns []pattern
+}
+
+// RedactHandler est un slog.Handler qui masque secrets et données personnelles
+// (message, attributs et groupes) avant de déléguer au handler suivant.
+type RedactHandler struct {
+	next  slog.Handler
+	rules *atomic.Pointer[rules] // Partagé entre les handlers dérivés (WithAttrs, WithGroup)
+}
+
+// NewRedactHandler crée un RedactHandler appliquant uniquement les motifs intégrés.
+// Les secrets et motifs de configuration sont ajoutés via SetRules.
+func NewRedactHandler(next slog.Handler) *RedactHandler {
+	h := &RedactHandler{next: next, rules: &atomic.Pointer[rules]{}}
+	h.rules.Store(&rules{patterns: builtinPatterns})
+	return h
+}
+
+// SetRules remplace les secrets littéraux et les motifs (regex) de données personnelles à masquer.
+// Peut être appelé à chaud, par exemple au rechargement de la configuration.
+func (h *RedactHandler) SetRules(secrets, patterns []string) error {
+	r := &rules{patterns: append([]pattern{}, builtinPatterns...)}
+	for _, s := range secrets {
+		if s != "" {
+			r.secrets = append(r.secrets, s)
+		}
+	}
+	for _, p := range patterns {
+		re, err := regexp.Compile(p)
+		if err != nil {
+			return fmt.Errorf("motif de masquage invalide %q: %w", p, err)
+		}
+		r.patterns = append(r.patterns, pattern{re: re, repl: redacted})
+	}
+	h.rules.Store(r)
+	return nil
+}
+
+// Enabled implémente slog.Handler.
+func (h *RedactHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return h.next.Enabled(ctx, level)
+}
+
+// Handle implémente slog.Handler.
+func (h *RedactHandler) Handle(ctx context.Context, record slog.Record) error {
+	r := h.rules.Load()
+
+	clean := slog.NewRecord(record.Time, record.Level, r.redact(record.Message), record.PC)
+	record.Attrs(func(a slog.Attr) bool {
+		clean.AddAttrs(r.redactAttr(a))
+		return true
+	})
+	return h.next.Handle(ctx, clean)
+}
+
+// WithAttrs implémente slog.Handler.
+func (h *RedactHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	r := h.rules.Load()
+	clean := make([]slog.Attr, len(attrs))
+	for i, a := range attrs {
+		clean[i] = r.redactAttr(a)
+	}
+	return &RedactHandler{next: h.next.WithAttrs(clean), rules: h.rules}
+}
+
+// WithGroup implémente slog.Handler.
+func (h *RedactHandler) WithGroup(name string) slog.Handler {
+	return &RedactHandler{next: h.next.WithGroup(name), rules: h.rules}
+}
+
+// redactAttr masque récursivement la valeur d'un attribut.
+func (r *rules) redactAttr(a slog.Attr) slog.Attr {
+	v := a.Value.Resolve() // Applique les slog.LogValuer (ex: config.Secret)
+
+	switch v.Kind() {
+	case slog.KindString:
+		return slog.String(a.Key, r.redact(v.String()))
+	case slog.KindGroup:
+		group := v.Group()
+		clean := make([]any, len(group))
+		for i, ga := range group {
+			clean[i] = r.redactAttr(ga)
+		}
+		return slog.Group(a.Key, clean...)
+	case slog.KindAny:
+		// Erreurs, structures, slices... : on passe par leur représentation texte
+		if err, ok := v.Any().(error); ok {
+			return slog.String(a.Key, r.redact(err.Error()))
+		}
+		return slog.String(a.Key, r.redact(fmt.Sprintf("%+v", v.Any())))
+	default:
+		return slog.Attr{Key: a.Key, Value: v}
+	}
+}
+
+// redact masque les secrets littéraux puis les motifs dans une chaîne.
+func (r *rules) redact(s string) string {
+	for _, secret := range r.secrets {
+		s = strings.ReplaceAll(s, secret, redacted)
+	}
+	for _, p := range r.patterns {
+		s = p.re.ReplaceAllString(s, p.repl)
+	}
+	return s
+}
@@ -1,31 +1,59 @@
 package main
 
 import (
+	"context"
 	"log/slog"
 	"os"
 	"os/signal"
 	"otom-ai/bot"
 	"otom-ai/config"
+	"otom-ai/logging"
 	"syscall"
+	"time"
+	_ "time/tzdata" // Fuseaux horaires embarqués (l'image alpine n'en fournit pas)
 )
 
 func main() {
-	// Logger structuré (JSON en prod, texte en dev)
-	logger := slog.New(slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{
+	// Logger de démarrage (texte, niveau Info), remplacé par celui de la configuration une fois chargée
+	logger := slog.New(logging.NewRedactHandler(slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{
 		Level: slog.LevelInfo,
-	}))
+	})))
 	slog.SetDefault(logger)
 
+	// Sous-commandes (outillage hors Discord)
+	if len(os.Args) > 1 {
+		switch os.Args[1] {
+		case "eval":
+			os.Exit(runEval(logger, os.Args[2:]))
+		case "items":
+			os.Exit(runItems(logger, os.Args[2:]))
+		case "almanax":
+			os.Exit(runAlmanax(logger, os.Args[2:]))
+		case "feedback":
+			os.Exit(runFeedback(logger, os.Args[2:]))
+		}
+	}
+
 	// Chargement de la configuration
 	cfg, err := config.Load()
 	if err != nil {
 		logger.Error("Échec du chargement de la configuration", slog.String("error", err.Error()))
 		os.Exit(1)
 	}
+
+	// Logger structuré (JSON en prod, texte en dev), avec masquage des secrets et données personnelles
+	logs, err := logging.New(cfg.Logging, cfg.Secrets())
+	if err != nil {
+		logger.Error("Configuration des logs invalide", slog.String("error", err.Error()))
+		os.Exit(1)
+	}
+	defer logs.Close()
+	logger = logs.Logger
+	slog.SetDefault(logger)
 	logger.Info("Configuration chargée avec succès")
 
 	// Initialisation du bot avec toutes ses dépendances
-	b, err := bot.New(cfg, logger)
+	b, err := bot.New(cfg, logger, logs.Levels)
 	if err != nil {
 		logger.Error("Échec de l'initialisation du bot", slog.String("error", err.Error()))
 		os.Exit(1)
@@ -39,10 +67,51 @@ func main() {
 	}
 	logger.Info("✅ Bot démarré — en attente des messages...")
 
+	// Rechargement à chaud de la configuration (SIGHUP ou modification du fichier)
+	reload := func(source string) {
+		newCfg, err := config.Load()
+		if err != nil {
+			logger.Error("Rechargement de la configuration refusé, l'ancienne reste active",
+				slog.String("source", source),
+				slog.String("error", err.Error()),
+			)
+			return
+		}
+		if err := b.Reload(newCfg); err != nil {
+			logger.Error("Échec de l'application de la configuration", slog.String("error", err.Error()))
+			return
+		}
+		// Les logs ne suivent qu'une configuration effectivement appliquée par le bot
+		if err := logs.Apply(newCfg.Logging, newCfg.Secrets()); err != nil {
+			logger.Error("Configuration des logs invalide, les réglages de logs précédents restent actifs", slog.String("error", err.Error()))
+		}
+		logger.Info("🔄 Configuration rechargée", slog.String("source", source))
+	}
+
+	reloads := make(chan string, 1)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	if path := cfg.Path(); path != "" {
+		go config.Watch(ctx, path, 2*time.Second, func() { reloads <- "fichier" })
+	}
+
 	// Arrêt gracieux : attente d'un signal SIGINT (Ctrl+C) ou SIGTERM
 	stop := make(chan os.Signal, 1)
 	signal.Notify(stop, syscall.SIGINT, syscall.SIGTERM)
-	<-stop
+	hup := make(chan os.Signal, 1)
+	signal.Notify(hup, syscall.SIGHUP)
+
+wait:
+	for {
+		select {
+		case <-hup:
+			reload("SIGHUP")
+		case source := <-reloads:
+			reload(source)
+		case <-stop:
+			break wait
+		}
+	}
 
 	logger.Info("⏹️ Signal d'arrêt reçu, déconnexion en cours...")
 	if err := b.Stop(); err != nil {
@@ -0,0 +1,105 @@
+// Package moderation filtre les messages des utilisateurs et les réponses du bot : listes de
+// mots et d'expressions régulières, complétées par un classifieur optionnel (LLM ou endpoint
+// de modération compatible OpenAI).
+package moderation
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Mask remplace les passages masqués par Redact.
+const Mask = "▒▒▒"
+
+// Filter repère les mots interdits (mots entiers, insensibles à la casse) et les expressions régulières interdites.
+type Filter struct {
+	rules []rule
+}
+
+// rule est un mot ou une expression régulière de la liste.
+type rule struct {
+	re   *regexp.Regexp
+	word bool // Mot entier : la correspondance doit être bordée par autre chose qu'une lettre ou un chiffre
+}
+
+// NewFilter compile les listes de mots et d'expressions régulières.
+func NewFilter(words, patterns []string) (*Filter, error) {
+	f := &Filter{}
+	for _, w := range words {
+		if w == "" {
+			continue
+		}
+		f.rules = append(f.rules, rule{re: regexp.MustCompile("(?i)" + regexp.QuoteMeta(w)), word: true})
+	}
+	for _, p := range patterns {
+		re, err := regexp.Compile(p)
+		if err != nil {
+			return nil, fmt.Errorf("expression régulière %q invalide: %w", p, err)
+		}
+		f.rules = append(f.rules, rule{re: re})
+	}
+	return f, nil
+}
+
+// Match retourne les passages interdits trouvés dans le texte (sans doublon).
+func (f *Filter) Match(text string) []string {
+	var found []string
+	for _, span := range f.spans(text) {
+		if s := text[span[0]:span[1]]; !slices.Contains(found, s) {
+			found = append(found, s)
+		}
+	}
+	return found
+}
+
+// Redact masque les passages interdits du texte.
+func (f *Filter) Redact(text string) string {
+	spans := f.spans(text)
+	if len(spans) == 0 {
+		return text
+	}
+	slices.SortFunc(spans, func(a, b [2]int) int { return a[0] - b[0] })
+
+	out := make([]byte, 0, len(text))
+	pos := 0
+	for _, span := range spans {
+		if span[1] <= pos {
+			continue // Inclus dans un passage déjà masqué
+		}
+		if span[0] >= pos {
+			out = append(out, text[pos:span[0]]...)
+			out = append(out, Mask...)
+		}
+		pos = span[1]
+	}
+	return string(append(out, text[pos:]...))
+}
+
+// spans retourne les positions de tous les passages interdits.
+func (f *Filter) spans(text string) [][2]int {
+	var spans [][2]int
+	for _, r := range f.rules {
+		for _, loc := range r.re.FindAllStringIndex(text, -1) {
+			if loc[0] == loc[1] || r.word && !isWord(text, loc[0], loc[1]) {
+				continue
+			}
+			spans = append(spans, [2]int{loc[0], loc[1]})
+		}
+	}
+	return spans
+}
+
+// isWord indique si text[start:end] n'est pas collé à une lettre ou un chiffre.
+func isWord(text string, start, end int) bool {
+	before, _ := utf8.DecodeLastRuneInString(text[:start])
+	after, _ := utf8.DecodeRuneInString(text[end:])
+	return !wordRune(before) && !wordRune(after)
+}
+
+// wordRune indique si la rune fait partie d'un mot (utf8.RuneError en bord de texte n'en fait pas partie).
+func wordRune(r rune) bool {
+	return r != utf8.RuneError && (unicode.IsLetter(r) || unicode.IsDigit(r))
+}
@@ -0,0 +1,201 @@
+package prices
+
+import (
+	"fmt"
+	"otom-ai/frtime"
+	"otom-ai/items"
+	"strings"
+	"time"
+)
+
+// maxCraftDepth borne la profondeur des recettes imbriquées (et protège des cycles).
+const maxCraftDepth = 6
+
+// Line est le coût d'une ligne de recette (ou de l'objet demandé lui-même).
+type Line struct {
+	Name     string
+	Quantity int
+	Buy      *Quote  // Prix d'achat à l'HDV, nil si aucun relevé
+	Craft    float64 // Coût unitaire de fabrication, 0 si pas de recette ou prix incomplets
+	Children []Line  // Ingrédients de la recette
+	Missing  []string
+	NoRecipe bool // La base d'objets n'a aucune recette : fabrication impossible à estimer
+}
+
+// Unit retourne le coût unitaire le plus avantageux (achat ou fabrication), 0 si inconnu.
+func (l Line) Unit() float64 {
+	switch {
+	case l.Buy != nil && l.Craft > 0:
+		return min(l.Buy.Unit, l.Craft)
+	case l.Buy != nil:
+		return l.Buy.Unit
+	}
+	return l.Craft
+}
+
+// Crafted indique si la fabrication est la meilleure option pour cette ligne.
+func (l Line) Crafted() bool {
+	return l.Craft > 0 && (l.Buy == nil || l.Craft < l.Buy.Unit)
+}
+
+// Calculator calcule des coûts de fabrication à partir des recettes et des prix relevés.
+type Calculator struct {
+	db    *items.Database
+	store *Store
+}
+
+// NewCalculator crée un calculateur de coûts.
+func NewCalculator(db *items.Database, store *Store) *Calculator {
+	return &Calculator{db: db, store: store}
+}
+
+// Cost calcule, pour quantity exemplaires de l'objet, le prix d'achat et le coût de
+// fabrication (récursif : chaque ingrédient est acheté ou fabriqué, au moins cher).
+// Sans aucune recette dans la base, seul le prix d'achat est donné et la ligne le signale.
+func (c *Calculator) Cost(server, name string, quantity int, now time.Time) (Line, error) {
+	if !c.db.HasRecipes() {
+		l := c.line(server, name, quantity, now, 0)
+		l.NoRecipe = true
+		return l, nil
+	}
+	it, ok := c.db.Item(name)
+	if !ok {
+		candidates := c.db.Search(name, 2)
+		if len(candidates) != 1 {
+			return Line{}, fmt.Errorf("objet %q inconnu ou ambigu dans la base locale", name)
+		}
+		it = candidates[0]
+	}
+	return c.line(server, it.Name, quantity, now, 0), nil
+}
+
+// line calcule le coût d'une ligne et de ses ingrédients.
+func (c *Calculator) line(server, name string, quantity int, now time.Time, depth int) Line {
+	l := Line{Name: name, Quantity: quantity}
+	if q, ok := c.store.Quote(server, name, now); ok {
+		l.Buy = &q
+	}
+
+	it, ok := c.db.Item(name)
+	if !ok || len(it.Recipe) == 0 || depth >= maxCraftDepth {
+		if l.Buy == nil {
+			l.Missing = []string{name}
+		}
+		return l
+	}
+
+	var craft float64
+	complete := true
+	for _, ing := range it.Recipe {
+		child := c.line(server, ing.Name, ing.Quantity, now, depth+1)
+		l.Children = append(l.Children, child)
+		if child.Unit() == 0 {
+			complete = false
+		}
+		craft += child.Unit() * float64(ing.Quantity)
+	}
+	if complete {
+		l.Craft = craft
+	}
+	// Seuls les ingrédients sans aucun coût connu (ni achat ni fabrication) manquent au calcul
+	for _, child := range l.Children {
+		if child.Unit() == 0 {
+			l.Missing = append(l.Missing, child.Missing...)
+		}
+	}
+	return l
+}
+
+// Describe formate le calcul pour le LLM : total, achat contre fabrication, détail de la
+// recette et fraîcheur des prix utilisés.
+func (l Line) Describe(server string, now time.Time) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "%d x %s (serveur %s)\n", l.Quantity, l.Name, server)
+
+	qty := float64(l.Quantity)
+	if l.Buy != nil {
+		fmt.Fprintf(&b, "- Achat à l'HDV : %s kamas (%s l'unité, %s)\n",
+			FormatKamas(l.Buy.Unit*qty), FormatKamas(l.Buy.Unit), freshness(*l.Buy, now))
+	} else {
+		b.WriteString("- Achat à l'HDV : aucun prix relevé\n")
+	}
+	switch {
+	case l.Craft > 0:
+		fmt.Fprintf(&b, "- Fabrication : %s kamas (%s l'unité)\n", FormatKamas(l.Craft*qty), FormatKamas(l.Craft))
+	case len(l.Children) > 0:
+		b.WriteString("- Fabrication : coût incomplet, il manque des prix d'ingrédients\n")
+	case l.NoRecipe:
+		b.WriteString("- Fabrication : aucune donnée de recette (base d'objets non importée), coût de fabrication inconnu\n")
+	default:
+		b.WriteString("- Fabrication : pas de recette connue\n")
+	}
+	if l.Buy != nil && l.Craft > 0 {
+		if l.Crafted() {
+			fmt.Fprintf(&b, "=> Fabriquer fait économiser %s kamas.\n", FormatKamas((l.Buy.Unit-l.Craft)*qty))
+		} else {
+			fmt.Fprintf(&b, "=> Acheter fait économiser %s kamas.\n", FormatKamas((l.Craft-l.Buy.Unit)*qty))
+		}
+	}
+
+	if len(l.Children) > 0 {
+		b.WriteString("Recette pour une unité (meilleure option par ingrédient) :\n")
+		for _, child := range l.Children {
+			writeLine(&b, child, now, 1, 1)
+		}
+	}
+	if len(l.Missing) > 0 {
+		fmt.Fprintf(&b, "Prix manquants (à relever avec /prix ajouter) : %s\n", strings.Join(unique(l.Missing), ", "))
+	}
+	return b.String()
+}
+
+// writeLine écrit une ligne de recette et les ingrédients des sous-recettes fabriquées.
+// mult est le nombre d'exemplaires du parent à fabriquer.
+func writeLine(b *strings.Builder, l Line, now time.Time, indent, mult int) {
+	qty := l.Quantity * mult
+	fmt.Fprintf(b, "%s- %d x %s : ", strings.Repeat("  ", indent-1), qty, l.Name)
+	switch {
+	case l.Unit() == 0:
+		b.WriteString("prix inconnu\n")
+	case l.Crafted():
+		fmt.Fprintf(b, "%s kamas en fabriquant\n", FormatKamas(l.Craft*float64(qty)))
+	default:
+		fmt.Fprintf(b, "%s kamas à l'achat (%s)\n", FormatKamas(l.Buy.Unit*float64(qty)), freshness(*l.Buy, now))
+	}
+	if l.Crafted() || l.Unit() == 0 {
+		for _, child := range l.Children {
+			writeLine(b, child, now, indent+1, qty)
+		}
+	}
+}
+
+// freshness décrit l'âge et le nombre de relevés d'un prix.
+func freshness(q Quote, now time.Time) string {
+	age := now.Sub(q.Latest)
+	var when string
+	switch {
+	case age < time.Hour:
+		when = "relevé il y a moins d'une heure"
+	case age < 24*time.Hour:
+		when = fmt.Sprintf("relevé il y a %d h", int(age.Hours()))
+	default:
+		when = "relevé le " + frtime.Date(q.Latest.In(now.Location()))
+	}
+	if !q.Fresh {
+		when += ", PRIX ANCIEN"
+	}
+	return fmt.Sprintf("%s, %d relevé(s)", when, q.Samples)
+}
+
+// unique retire les doublons en conservant l'ordre.
+func unique(names []string) []string {
+	seen := map[string]bool{}
+	var out []string
+	for _, n := range names {
+		if !seen[n] {
+			seen[n] = true
+			out = append(out, n)
+		}
+	}
+	return out
+}
@@ -0,0 +1,218 @@
+// Package prices stocke les prix d'HDV (hôtel des ventes) relevés par les joueurs, par
+// serveur de jeu, et calcule le coût de fabrication d'un objet à partir de sa recette.
+package prices
+
+import (
+	"errors"
+	"fmt"
+	"otom-ai/items"
+	"otom-ai/storage"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// maxSubmissions est le nombre de relevés conservés par objet et par serveur.
+	maxSubmissions = 15
+	// FreshFor est l'âge au-delà duquel un relevé n'est plus considéré comme à jour.
+	FreshFor = 7 * 24 * time.Hour
+	// outlierFactor : un prix plus de outlierFactor fois au-dessus ou en dessous de la
+	// médiane des relevés à jour est rejeté (faute de frappe, troll).
+	outlierFactor = 4
+	// minSamples est le nombre de relevés à jour nécessaires pour détecter une valeur aberrante.
+	minSamples = 3
+)
+
+// ErrOutlier est retournée quand un prix s'écarte trop des relevés récents.
+var ErrOutlier = errors.New("prix aberrant")
+
+// Submission est un prix relevé par un joueur.
+type Submission struct {
+	Item   string    `json:"item"` // Nom tel que saisi (ou nom officiel de la base d'objets)
+	Unit   float64   `json:"unit"` // Prix unitaire en kamas
+	UserID string    `json:"user_id"`
+	At     time.Time `json:"at"`
+}
+
+// Quote est le prix retenu pour un objet sur un serveur.
+type Quote struct {
+	Item    string
+	Unit    float64   // Médiane des relevés à jour (ou de tous les relevés s'ils sont anciens)
+	Samples int       // Nombre de relevés utilisés
+	Latest  time.Time // Date du relevé le plus récent
+	Fresh   bool      // Au moins un relevé date de moins de FreshFor
+}
+
+// Store est la base des prix relevés, persistée dans un fichier JSON.
+type Store struct {
+	mu   sync.Mutex
+	file *storage.JSONFile
+	data map[string]map[string][]Submission // Serveur → nom normalisé → relevés (du plus ancien au plus récent)
+}
+
+// Open charge la base des prix (vide si le fichier n'existe pas encore).
+func Open(path string) (*Store, error) {
+	s := &Store{file: storage.NewJSONFile(path), data: map[string]map[string][]Submission{}}
+	if err := s.file.Load(&s.data); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// Submit enregistre le prix unitaire d'un objet relevé par un joueur. Un nouveau relevé
+// du même joueur remplace le sien (un joueur ne pèse qu'une fois dans la médiane).
+func (s *Store) Submit(server, item string, unit float64, userID string, now time.Time) (Quote, error) {
+	if unit <= 0 {
+		return Quote{}, fmt.Errorf("le prix doit être positif")
+	}
+	key, srv := items.Normalize(item), items.Normalize(server)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	subs := s.data[srv][key]
+	if q, ok := quote(subs, now); ok && q.Fresh && q.Samples >= minSamples {
+		if unit > q.Unit*outlierFactor || unit < q.Unit/outlierFactor {
+			return q, fmt.Errorf("%w: %s kamas contre une médiane de %s kamas", ErrOutlier, FormatKamas(unit), FormatKamas(q.Unit))
+		}
+	}
+
+	subs = slices.DeleteFunc(slices.Clone(subs), func(sub Submission) bool { return sub.UserID == userID })
+	subs = append(subs, Submission{Item: item, Unit: unit, UserID: userID, At: now})
+	if len(subs) > maxSubmissions {
+		subs = subs[len(subs)-maxSubmissions:]
+	}
+
+	if s.data[srv] == nil {
+		s.data[srv] = map[string][]Submission{}
+	}
+	s.data[srv][key] = subs
+	if err := s.file.Save(s.data); err != nil {
+		return Quote{}, err
+	}
+
+	q, _ := quote(subs, now)
+	return q, nil
+}
+
+// ForUser retourne les relevés d'un joueur, par serveur de jeu (nom normalisé).
+func (s *Store) ForUser(userID string) map[string][]Submission {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	out := map[string][]Submission{}
+	for srv, byItem := range s.data {
+		for _, subs := range byItem {
+			for _, sub := range subs {
+				if sub.UserID == userID {
+					out[srv] = append(out[srv], sub)
+				}
+			}
+		}
+	}
+	return out
+}
+
+// ForgetUser supprime les relevés d'un joueur. Les prix retenus sont recalculés sans eux.
+func (s *Store) ForgetUser(userID string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	type removed struct {
+		srv, key string
+		subs     []Submission
+	}
+	var old []removed
+	for srv, byItem := range s.data {
+		for key, subs := range byItem {
+			kept := slices.DeleteFunc(slices.Clone(subs), func(sub Submission) bool { return sub.UserID == userID })
+			if len(kept) == len(subs) {
+				continue
+			}
+			old = append(old, removed{srv, key, subs})
+			if len(kept) == 0 {
+				delete(byItem, key)
+			} else {
+				byItem[key] = kept
+			}
+		}
+	}
+	if len(old) == 0 {
+		return nil
+	}
+	if err := s.file.Save(s.data); err != nil {
+		for _, r := range old {
+			s.data[r.srv][r.key] = r.subs
+		}
+		return err
+	}
+	return nil
+}
+
+// Quote retourne le prix retenu pour un objet sur un serveur.
+func (s *Store) Quote(server, item string, now time.Time) (Quote, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return quote(s.data[items.Normalize(server)][items.Normalize(item)], now)
+}
+
+// quote calcule la médiane des relevés à jour, ou de tous les relevés s'ils sont tous anciens.
+func quote(subs []Submission, now time.Time) (Quote, bool) {
+	if len(subs) == 0 {
+		return Quote{}, false
+	}
+
+	var fresh, all []float64
+	var latest time.Time
+	for _, sub := range subs {
+		all = append(all, sub.Unit)
+		if now.Sub(sub.At) <= FreshFor {
+			fresh = append(fresh, sub.Unit)
+		}
+		if sub.At.After(latest) {
+			latest = sub.At
+		}
+	}
+
+	values := fresh
+	if len(values) == 0 {
+		values = all
+	}
+	return Quote{
+		Item:    subs[len(subs)-1].Item,
+		Unit:    median(values),
+		Samples: len(values),
+		Latest:  latest,
+		Fresh:   len(fresh) > 0,
+	}, true
+}
+
+// median retourne la médiane d'une liste non vide.
+func median(values []float64) float64 {
+	sorted := slices.Clone(values)
+	slices.Sort(sorted)
+	n := len(sorted)
+	if n%2 == 1 {
+		return sorted[n/2]
+	}
+	return (sorted[n/2-1] + sorted[n/2]) / 2
+}
+
+// FormatKamas formate un montant en kamas avec séparateur de milliers (ex: 1 250 000, 2,5).
+func FormatKamas(v float64) string {
+	if v < 10 && v != float64(int64(v)) {
+		return strings.Replace(fmt.Sprintf("%.1f", v), ".", ",", 1)
+	}
+	n := int64(v + 0.5)
+	s := fmt.Sprintf("%d", n)
+	var out []byte
+	for i := range len(s) {
+		if i > 0 && (len(s)-i)%3 == 0 {
+			out = append(out, ' ')
+		}
+		out = append(out, s[i])
+	}
+	return string(out)
+}
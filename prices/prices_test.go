@@ -0,0 +1,180 @@
+package prices
+
+import (
+	"errors"
+	"otom-ai/items"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"testing"
+	"time"
+)
+
+func TestForUserForgetUser(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "prices.json")
+	s, err := Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	now := time.Date(2026, 10, 18, 20, 0, 0, 0, time.UTC)
+	subs := []struct {
+		server, item string
+		unit         float64
+		user         string
+	}{
+		{"Draconiros", "Laine de Bouftou", 12, "1"},
+		{"Draconiros", "Blé", 4, "1"},
+		{"Ombre", "Blé", 5, "1"},
+		{"Draconiros", "Laine de Bouftou", 20, "2"},
+	}
+	for _, sub := range subs {
+		if _, err := s.Submit(sub.server, sub.item, sub.unit, sub.user, now); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	got := s.ForUser("1")
+	if len(got["draconiros"]) != 2 || len(got["ombre"]) != 1 {
+		t.Errorf("ForUser(1) = %v, attendu 2 relevés sur Draconiros et 1 sur Ombre", got)
+	}
+
+	if err := s.ForgetUser("1"); err != nil {
+		t.Fatal(err)
+	}
+	reopened, err := Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, store := range []*Store{s, reopened} {
+		if got := store.ForUser("1"); len(got) != 0 {
+			t.Errorf("ForUser(1) après oubli = %v", got)
+		}
+		// Le prix retenu est recalculé sans les relevés oubliés
+		if q, ok := store.Quote("Draconiros", "Laine de Bouftou", now); !ok || q.Unit != 20 || q.Samples != 1 {
+			t.Errorf("Quote(Laine de Bouftou) = %+v, %v, attendu le seul relevé de 2 (20 kamas)", q, ok)
+		}
+		if _, ok := store.Quote("Ombre", "Blé", now); ok {
+			t.Error("Quote(Blé, Ombre) : le seul relevé aurait dû être oublié")
+		}
+	}
+}
+
+func TestSubmitOutlier(t *testing.T) {
+	now := time.Date(2026, 10, 18, 20, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name    string
+		history []float64 // Relevés précédents, un par joueur
+		age     time.Duration
+		unit    float64
+		outlier bool
+	}{
+		{"dans la fourchette", []float64{10, 12, 11}, 0, 40, false},
+		{"trop cher", []float64{10, 12, 11}, 0, 50, true},
+		{"trop bon marché", []float64{10, 12, 11}, 0, 2, true},
+		{"pas assez de relevés", []float64{10, 12}, 0, 500, false},
+		{"relevés trop anciens", []float64{10, 12, 11}, FreshFor + time.Hour, 500, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, err := Open(filepath.Join(t.TempDir(), "prices.json"))
+			if err != nil {
+				t.Fatal(err)
+			}
+			for i, unit := range tt.history {
+				if _, err := s.Submit("Draconiros", "Blé", unit, strconv.Itoa(i), now.Add(-tt.age)); err != nil {
+					t.Fatal(err)
+				}
+			}
+			q, err := s.Submit("Draconiros", "Blé", tt.unit, "troll", now)
+			if errors.Is(err, ErrOutlier) != tt.outlier {
+				t.Fatalf("Submit(%v) = %v, valeur aberrante attendue : %v", tt.unit, err, tt.outlier)
+			}
+			if tt.outlier {
+				// Le prix rejeté n'est pas enregistré : le prix retenu ne bouge pas
+				if got, _ := s.Quote("Draconiros", "Blé", now); got.Unit != q.Unit || got.Samples != len(tt.history) {
+					t.Errorf("Quote() après rejet = %+v, attendu %+v", got, q)
+				}
+			}
+		})
+	}
+}
+
+func TestCalculatorCost(t *testing.T) {
+	now := time.Date(2026, 10, 18, 20, 0, 0, 0, time.UTC)
+	db := items.New(items.Dump{Items: []items.Item{
+		{Name: "Coiffe du Bouftou", Type: "Chapeau", Recipe: []items.Ingredient{{Name: "Laine de Bouftou", Quantity: 2}, {Name: "Cuir de Bouftou", Quantity: 1}}},
+		{Name: "Cuir de Bouftou", Type: "Ressource", Recipe: []items.Ingredient{{Name: "Blé", Quantity: 4}}},
+		{Name: "Laine de Bouftou", Type: "Ressource"},
+		{Name: "Blé", Type: "Ressource"},
+	}})
+	submit := func(t *testing.T, s *Store, prices map[string]float64) {
+		t.Helper()
+		for item, unit := range prices {
+			if _, err := s.Submit("Draconiros", item, unit, "1", now); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+
+	t.Run("achat ou fabrication au moins cher", func(t *testing.T) {
+		s, err := Open(filepath.Join(t.TempDir(), "prices.json"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		submit(t, s, map[string]float64{"Coiffe du Bouftou": 100, "Laine de Bouftou": 10, "Cuir de Bouftou": 20, "Blé": 3})
+		l, err := NewCalculator(db, s).Cost("Draconiros", "coiffe du bouftou", 2, now)
+		if err != nil {
+			t.Fatal(err)
+		}
+		// Cuir : fabriqué 4×3 = 12 plutôt qu'acheté 20 ; coiffe : 2×10 + 12 = 32 plutôt que 100
+		if l.Name != "Coiffe du Bouftou" || l.Quantity != 2 || l.Craft != 32 || !l.Crafted() || l.Unit() != 32 {
+			t.Errorf("Cost() = %+v, attendu une fabrication à 32 kamas", l)
+		}
+		if len(l.Children) != 2 || l.Children[1].Craft != 12 || !l.Children[1].Crafted() || l.Children[0].Crafted() {
+			t.Errorf("Children = %+v, attendu la laine achetée et le cuir fabriqué à 12 kamas", l.Children)
+		}
+		if len(l.Missing) != 0 {
+			t.Errorf("Missing = %v, attendu aucun prix manquant", l.Missing)
+		}
+	})
+
+	t.Run("prix manquant", func(t *testing.T) {
+		s, err := Open(filepath.Join(t.TempDir(), "prices.json"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		submit(t, s, map[string]float64{"Coiffe du Bouftou": 100, "Laine de Bouftou": 10})
+		l, err := NewCalculator(db, s).Cost("Draconiros", "Coiffe du Bouftou", 1, now)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if l.Craft != 0 || l.Crafted() || l.Unit() != 100 || !slices.Equal(l.Missing, []string{"Blé"}) {
+			t.Errorf("Cost() = %+v, attendu l'achat à 100 kamas et le blé manquant", l)
+		}
+	})
+
+	t.Run("base sans recettes", func(t *testing.T) {
+		s, err := Open(filepath.Join(t.TempDir(), "prices.json"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		submit(t, s, map[string]float64{"Coiffe du Bouftou": 100})
+		l, err := NewCalculator(items.New(items.Dump{}), s).Cost("Draconiros", "Coiffe du Bouftou", 1, now)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !l.NoRecipe || l.Unit() != 100 || len(l.Children) != 0 {
+			t.Errorf("Cost() = %+v, attendu le seul prix d'achat", l)
+		}
+	})
+
+	t.Run("objet inconnu", func(t *testing.T) {
+		s, err := Open(filepath.Join(t.TempDir(), "prices.json"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := NewCalculator(db, s).Cost("Draconiros", "Gelano", 1, now); err == nil {
+			t.Error("Cost(Gelano) accepté, attendu une erreur")
+		}
+	})
+}
@@ -0,0 +1,69 @@
+// Package privacy conserve les préférences de confidentialité des utilisateurs : les joueurs qui
+// refusent que le bot lise leurs messages pour construire son contexte (historique des channels,
+// mémoire, avis), persistés dans un fichier JSON.
+package privacy
+
+import (
+	"maps"
+	"otom-ai/storage"
+	"sync"
+	"time"
+)
+
+// Store est la liste des utilisateurs ayant refusé la lecture de leurs messages.
+type Store struct {
+	mu      sync.Mutex
+	file    *storage.JSONFile
+	optOuts map[string]time.Time // ID utilisateur → date du refus
+}
+
+// Open charge les préférences (aucune si le fichier n'existe pas encore).
+func Open(path string) (*Store, error) {
+	s := &Store{file: storage.NewJSONFile(path), optOuts: map[string]time.Time{}}
+	if err := s.file.Load(&s.optOuts); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// OptOut enregistre le refus d'un utilisateur. Il retourne false s'il était déjà enregistré.
+func (s *Store) OptOut(userID string, now time.Time) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.optOuts[userID]; ok {
+		return false, nil
+	}
+	old := maps.Clone(s.optOuts)
+	s.optOuts[userID] = now
+	if err := s.file.Save(s.optOuts); err != nil {
+		s.optOuts = old
+		return false, err
+	}
+	return true, nil
+}
+
+// OptIn retire le refus d'un utilisateur. Il retourne false s'il n'en avait pas.
+func (s *Store) OptIn(userID string) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	since, ok := s.optOuts[userID]
+	if !ok {
+		return false, nil
+	}
+	delete(s.optOuts, userID)
+	if err := s.file.Save(s.optOuts); err != nil {
+		s.optOuts[userID] = since
+		return false, err
+	}
+	return true, nil
+}
+
+// OptedOut indique si un utilisateur a refusé la lecture de ses messages, et depuis quand.
+func (s *Store) OptedOut(userID string) (time.Time, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	since, ok := s.optOuts[userID]
+	return since, ok
+}
@@ -0,0 +1,314 @@
+// Package profiles enregistre les personnages des joueurs (classe, niveau, serveur, build),
+// par serveur Discord, pour que le bot adapte ses conseils à chacun.
+package profiles
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"otom-ai/items"
+	"otom-ai/storage"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+)
+
+const (
+	// MaxLevel est le niveau maximum d'un personnage.
+	MaxLevel = 200
+	// maxCharacters est le nombre de personnages enregistrables par joueur.
+	maxCharacters = 10
+	// maxNameLen et maxServerLen bornent le nom du personnage et du serveur de jeu (en caractères),
+	// repris tels quels dans le contexte du LLM.
+	maxNameLen   = 32
+	maxServerLen = 32
+)
+
+// Classes liste les classes jouables de Dofus 3.
+var Classes = []string{
+	"Crâ", "Écaflip", "Eliotrope", "Eniripsa", "Enutrof", "Féca", "Forgelance", "Huppermage", "Iop", "Osamodas",
+	"Ouginak", "Pandawa", "Roublard", "Sacrieur", "Sadida", "Sram", "Steamer", "Xélor", "Zobal",
+}
+
+// ErrNotFound est retournée quand un personnage n'existe pas.
+var ErrNotFound = errors.New("personnage introuvable")
+
+// Character est un personnage d'un joueur.
+type Character struct {
+	Name   string `json:"name"`
+	Class  string `json:"class"`
+	Level  int    `json:"level"`
+	Server string `json:"server,omitempty"` // Serveur de jeu
+	Build  string `json:"build,omitempty"`  // Lien vers le build (DofusBook...)
+}
+
+// String décrit le personnage en une ligne ("Kiwi, Eniripsa niveau 180 (Draconiros), build : ...").
+func (c Character) String() string {
+	s := fmt.Sprintf("%s, %s niveau %d", c.Name, c.Class, c.Level)
+	if c.Server != "" {
+		s += " (" + c.Server + ")"
+	}
+	if c.Build != "" {
+		s += ", build : " + c.Build
+	}
+	return s
+}
+
+// Profile regroupe les personnages d'un joueur sur un serveur Discord.
+type Profile struct {
+	UserID     string      `json:"user_id"`
+	Username   string      `json:"username"` // Pseudo Discord au dernier enregistrement
+	Characters []Character `json:"characters"`
+	UpdatedAt  time.Time   `json:"updated_at"`
+}
+
+// Summary décrit le profil de façon compacte pour le contexte du LLM.
+func (p Profile) Summary() string {
+	parts := make([]string, len(p.Characters))
+	for i, c := range p.Characters {
+		parts[i] = c.String()
+	}
+	return fmt.Sprintf("%s joue : %s", p.Username, strings.Join(parts, " ; "))
+}
+
+// Store est la base des profils, persistée dans un fichier JSON.
+type Store struct {
+	mu   sync.Mutex
+	file *storage.JSONFile
+	data map[string]map[string]*Profile // Serveur Discord → ID utilisateur → profil
+}
+
+// Open charge les profils (aucun si le fichier n'existe pas encore).
+func Open(path string) (*Store, error) {
+	s := &Store{file: storage.NewJSONFile(path), data: map[string]map[string]*Profile{}}
+	if err := s.file.Load(&s.data); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// ParseClass retrouve le nom officiel d'une classe (casse et accents ignorés).
+func ParseClass(s string) (string, error) {
+	n := items.Normalize(s)
+	for _, c := range Classes {
+		if items.Normalize(c) == n {
+			return c, nil
+		}
+	}
+	return "", fmt.Errorf("classe inconnue %q", s)
+}
+
+// Validate vérifie et normalise un personnage.
+func (c *Character) Validate() error {
+	c.Name = strings.TrimSpace(c.Name)
+	c.Server = strings.TrimSpace(c.Server)
+	c.Build = strings.TrimSpace(c.Build)
+	if c.Name == "" {
+		return errors.New("nom du personnage manquant")
+	}
+	if utf8.RuneCountInString(c.Name) > maxNameLen {
+		return fmt.Errorf("nom du personnage trop long (%d caractères maximum)", maxNameLen)
+	}
+	if utf8.RuneCountInString(c.Server) > maxServerLen {
+		return fmt.Errorf("nom du serveur trop long (%d caractères maximum)", maxServerLen)
+	}
+	class, err := ParseClass(c.Class)
+	if err != nil {
+		return err
+	}
+	c.Class = class
+	if c.Level < 1 || c.Level > MaxLevel {
+		return fmt.Errorf("niveau invalide %d (1 à %d)", c.Level, MaxLevel)
+	}
+	if c.Build != "" {
+		u, err := url.Parse(c.Build)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return errors.New("le lien du build doit être une adresse http(s)")
+		}
+	}
+	return nil
+}
+
+// Set enregistre (ou met à jour, à nom égal) le personnage d'un joueur.
+func (s *Store) Set(guildID, userID, username string, c Character, now time.Time) (Profile, error) {
+	if err := c.Validate(); err != nil {
+		return Profile{}, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	p := Profile{UserID: userID}
+	if old, ok := s.data[guildID][userID]; ok {
+		p = old.clone()
+	}
+	i := slices.IndexFunc(p.Characters, func(o Character) bool { return sameName(o.Name, c.Name) })
+	switch {
+	case i >= 0:
+		p.Characters[i] = c
+	case len(p.Characters) >= maxCharacters:
+		return Profile{}, fmt.Errorf("%d personnages maximum par joueur", maxCharacters)
+	default:
+		p.Characters = append(p.Characters, c)
+	}
+	p.Username = username
+	p.UpdatedAt = now
+
+	if err := s.put(guildID, p); err != nil {
+		return Profile{}, err
+	}
+	return p, nil
+}
+
+// Remove supprime un personnage d'un joueur (et son profil s'il n'en reste aucun).
+func (s *Store) Remove(guildID, userID, name string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	old, ok := s.data[guildID][userID]
+	if !ok {
+		return ErrNotFound
+	}
+	p := old.clone()
+	n := len(p.Characters)
+	p.Characters = slices.DeleteFunc(p.Characters, func(c Character) bool { return sameName(c.Name, name) })
+	if len(p.Characters) == n {
+		return ErrNotFound
+	}
+	return s.put(guildID, p)
+}
+
+// Get retourne le profil d'un joueur.
+func (s *Store) Get(guildID, userID string) (Profile, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	p, ok := s.data[guildID][userID]
+	if !ok {
+		return Profile{}, false
+	}
+	return p.clone(), true
+}
+
+// Find cherche un joueur par pseudo Discord ou par nom de personnage. Si plusieurs joueurs
+// correspondent, le pseudo l'emporte sur le nom de personnage, puis l'ordre alphabétique des
+// pseudos (et des IDs) départage, pour que la réponse ne dépende pas de l'ordre de la map.
+func (s *Store) Find(guildID, query string) (Profile, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var best *Profile
+	bestByName := false
+	for _, p := range s.data[guildID] {
+		byName := sameName(p.Username, query)
+		if !byName && !slices.ContainsFunc(p.Characters, func(c Character) bool { return sameName(c.Name, query) }) {
+			continue
+		}
+		if best == nil || (byName && !bestByName) || (byName == bestByName && before(p, best)) {
+			best, bestByName = p, byName
+		}
+	}
+	if best == nil {
+		return Profile{}, false
+	}
+	return best.clone(), true
+}
+
+// before ordonne deux profils par pseudo normalisé puis par ID.
+func before(a, b *Profile) bool {
+	if c := strings.Compare(items.Normalize(a.Username), items.Normalize(b.Username)); c != 0 {
+		return c < 0
+	}
+	return a.UserID < b.UserID
+}
+
+// Guild retourne les profils d'un serveur, triés par pseudo.
+func (s *Store) Guild(guildID string) []Profile {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	out := make([]Profile, 0, len(s.data[guildID]))
+	for _, p := range s.data[guildID] {
+		out = append(out, p.clone())
+	}
+	slices.SortFunc(out, func(a, b Profile) int {
+		return strings.Compare(items.Normalize(a.Username), items.Normalize(b.Username))
+	})
+	return out
+}
+
+// ForUser retourne les profils d'un joueur, par serveur Discord.
+func (s *Store) ForUser(userID string) map[string]Profile {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	out := map[string]Profile{}
+	for guildID, guild := range s.data {
+		if p, ok := guild[userID]; ok {
+			out[guildID] = p.clone()
+		}
+	}
+	return out
+}
+
+// ForgetUser supprime les profils d'un joueur sur tous les serveurs.
+func (s *Store) ForgetUser(userID string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	old := map[string]*Profile{}
+	for guildID, guild := range s.data {
+		if p, ok := guild[userID]; ok {
+			old[guildID] = p
+			delete(guild, userID)
+		}
+	}
+	if len(old) == 0 {
+		return nil
+	}
+	if err := s.file.Save(s.data); err != nil {
+		for guildID, p := range old {
+			s.data[guildID][userID] = p
+		}
+		return err
+	}
+	return nil
+}
+
+// put remplace le profil puis persiste, en rétablissant l'ancien en cas d'échec (appelé verrou pris).
+func (s *Store) put(guildID string, p Profile) error {
+	guild := s.data[guildID]
+	if guild == nil {
+		guild = map[string]*Profile{}
+		s.data[guildID] = guild
+	}
+	old, existed := guild[p.UserID]
+	if len(p.Characters) == 0 {
+		delete(guild, p.UserID)
+	} else {
+		guild[p.UserID] = &p
+	}
+
+	if err := s.file.Save(s.data); err != nil {
+		if existed {
+			guild[p.UserID] = old
+		} else {
+			delete(guild, p.UserID)
+		}
+		return err
+	}
+	return nil
+}
+
+// clone copie un profil pour le rendre hors du verrou du Store.
+func (p *Profile) clone() Profile {
+	c := *p
+	c.Characters = slices.Clone(p.Characters)
+	return c
+}
+
+// sameName compare deux noms sans tenir compte de la casse ni des accents.
+func sameName(a, b string) bool {
+	return items.Normalize(a) == items.Normalize(b)
+}
@@ -0,0 +1,141 @@
+package profiles
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestForUserForgetUser(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "profiles.json")
+	s, err := Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	now := time.Date(2026, 10, 18, 20, 0, 0, 0, time.UTC)
+	sets := []struct {
+		guild, user, name string
+		c                 Character
+	}{
+		{"g1", "1", "alice", Character{Name: "Kiwi", Class: "Eniripsa", Level: 180}},
+		{"g1", "1", "alice", Character{Name: "Pomme", Class: "Iop", Level: 200}},
+		{"g2", "1", "alice", Character{Name: "Kiwi", Class: "Eniripsa", Level: 180}},
+		{"g1", "2", "bob", Character{Name: "Brindille", Class: "Sadida", Level: 120}},
+	}
+	for _, st := range sets {
+		if _, err := s.Set(st.guild, st.user, st.name, st.c, now); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	tests := []struct {
+		user string
+		want map[string]int // Serveur → nombre de personnages
+	}{
+		{"1", map[string]int{"g1": 2, "g2": 1}},
+		{"2", map[string]int{"g1": 1}},
+		{"3", map[string]int{}},
+	}
+	for _, tt := range tests {
+		got := s.ForUser(tt.user)
+		if len(got) != len(tt.want) {
+			t.Errorf("ForUser(%s) = %v, attendu %v serveur(s)", tt.user, got, len(tt.want))
+		}
+		for guild, n := range tt.want {
+			if len(got[guild].Characters) != n {
+				t.Errorf("ForUser(%s)[%s] = %d personnage(s), attendu %d", tt.user, guild, len(got[guild].Characters), n)
+			}
+		}
+	}
+
+	if err := s.ForgetUser("1"); err != nil {
+		t.Fatal(err)
+	}
+	reopened, err := Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, store := range []*Store{s, reopened} {
+		if got := store.ForUser("1"); len(got) != 0 {
+			t.Errorf("ForUser(1) après oubli = %v", got)
+		}
+		if got := store.ForUser("2"); len(got) != 1 {
+			t.Errorf("ForUser(2) après oubli de 1 = %v, attendu le profil de bob", got)
+		}
+	}
+	if err := s.ForgetUser("3"); err != nil {
+		t.Errorf("ForgetUser(inconnu) = %v", err)
+	}
+}
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		c       Character
+		wantErr bool
+	}{
+		{"valide", Character{Name: " Kiwi ", Class: "eniripsa", Level: 180, Server: "Draconiros"}, false},
+		{"nom manquant", Character{Name: " ", Class: "Iop", Level: 200}, true},
+		{"nom trop long", Character{Name: strings.Repeat("é", maxNameLen+1), Class: "Iop", Level: 200}, true},
+		{"serveur trop long", Character{Name: "Kiwi", Class: "Iop", Level: 200, Server: strings.Repeat("a", maxServerLen+1)}, true},
+		{"classe inconnue", Character{Name: "Kiwi", Class: "Paladin", Level: 200}, true},
+		{"niveau invalide", Character{Name: "Kiwi", Class: "Iop", Level: MaxLevel + 1}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.c.Validate(); (err != nil) != tt.wantErr {
+				t.Errorf("Validate(%+v) = %v, erreur attendue : %v", tt.c, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestFind(t *testing.T) {
+	s, err := Open(filepath.Join(t.TempDir(), "profiles.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	now := time.Date(2026, 10, 18, 20, 0, 0, 0, time.UTC)
+	sets := []struct {
+		user, name string
+		c          Character
+	}{
+		{"3", "carol", Character{Name: "Kiwi", Class: "Iop", Level: 200}},
+		{"1", "bob", Character{Name: "Kiwi", Class: "Eniripsa", Level: 180}},
+		{"2", "kiwi", Character{Name: "Pomme", Class: "Sadida", Level: 120}},
+		{"4", "alice", Character{Name: "Brindille", Class: "Sram", Level: 90}},
+	}
+	for _, st := range sets {
+		if _, err := s.Set("g", st.user, st.name, st.c, now); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	tests := []struct {
+		query, want string
+	}{
+		{"Kiwi", "2"},      // Le pseudo l'emporte sur le nom de personnage
+		{"brindille", "4"}, // Nom de personnage
+		{"ALICE", "4"},     // Pseudo, casse ignorée
+		{"Zobal", ""},
+	}
+	for _, tt := range tests {
+		for range 20 { // L'ordre de parcours de la map ne doit pas changer le résultat
+			p, ok := s.Find("g", tt.query)
+			if ok != (tt.want != "") || p.UserID != tt.want {
+				t.Fatalf("Find(%q) = %q, %v, attendu %q", tt.query, p.UserID, ok, tt.want)
+			}
+		}
+	}
+
+	// Deux personnages homonymes : le pseudo le premier dans l'ordre alphabétique
+	if err := s.Remove("g", "2", "Pomme"); err != nil {
+		t.Fatal(err)
+	}
+	for range 20 {
+		if p, _ := s.Find("g", "Kiwi"); p.UserID != "1" {
+			t.Fatalf("Find(Kiwi) = %q, attendu 1 (bob avant carol)", p.UserID)
+		}
+	}
+}
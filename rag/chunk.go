@@ -0,0 +1,175 @@
+// Package rag implémente la base de connaissances du bot : découpage de guides
+// Markdown/HTML en passages, calcul d'embeddings via un endpoint compatible OpenAI,
+// index vectoriel local persisté en JSON et recherche par similarité cosinus.
+package rag
+
+import (
+	"fmt"
+	"strings"
+	"unicode/utf8"
+)
+
+// Chunk est un passage indexé d'un document.
+type Chunk struct {
+	Source  string `json:"source"`  // Chemin du document, relatif au dossier indexé
+	Heading string `json:"heading"` // Titre de la section (ex: "Guide Dofus > Étape 2")
+	Text    string `json:"text"`
+}
+
+// Taille cible des passages (en caractères) et recouvrement entre passages consécutifs
+// d'une même section, pour ne pas couper une information entre deux passages.
+const (
+	chunkSize    = 1000
+	chunkOverlap = 150
+)
+
+// section est un bloc de Markdown sous un même titre.
+type section struct {
+	heading string
+	lines   []string
+}
+
+// Split découpe un document Markdown en passages : par section (titres #), puis par
+// paragraphes regroupés jusqu'à la taille cible, avec recouvrement.
+func Split(source, title, markdown string) []Chunk {
+	var chunks []Chunk
+	for _, sec := range sections(title, markdown) {
+		text := strings.TrimSpace(strings.Join(sec.lines, "\n"))
+		if text == "" {
+			continue
+		}
+		for _, part := range pack(paragraphs(text)) {
+			chunks = append(chunks, Chunk{Source: source, Heading: sec.heading, Text: part})
+		}
+	}
+	return chunks
+}
+
+// sections regroupe les lignes par titre Markdown, en conservant la hiérarchie des titres.
+func sections(title, markdown string) []section {
+	var (
+		out   []section
+		path  []string // Titres englobants, indexés par niveau - 1
+		cur   = section{heading: title}
+		fence bool
+	)
+	for _, line := range strings.Split(markdown, "\n") {
+		if strings.HasPrefix(strings.TrimSpace(line), "```") {
+			fence = !fence
+		}
+		level := headingLevel(line)
+		if fence || level == 0 {
+			cur.lines = append(cur.lines, line)
+			continue
+		}
+
+		out = append(out, cur)
+		if len(path) >= level {
+			path = path[:level-1]
+		}
+		for len(path) < level-1 {
+			path = append(path, "")
+		}
+		path = append(path, strings.TrimSpace(line[level:]))
+		cur = section{heading: joinHeading(title, path)}
+	}
+	return append(out, cur)
+}
+
+// headingLevel retourne le niveau d'un titre Markdown (1 à 6), ou 0.
+func headingLevel(line string) int {
+	level := 0
+	for level < len(line) && line[level] == '#' {
+		level++
+	}
+	if level == 0 || level > 6 || level >= len(line) || line[level] != ' ' {
+		return 0
+	}
+	return level
+}
+
+// joinHeading construit le chemin lisible d'une section.
+func joinHeading(title string, path []string) string {
+	parts := []string{}
+	if title != "" {
+		parts = append(parts, title)
+	}
+	for _, p := range path {
+		if p != "" && p != title {
+			parts = append(parts, p)
+		}
+	}
+	return strings.Join(parts, " > ")
+}
+
+// paragraphs découpe un texte en paragraphes, eux-mêmes coupés s'ils dépassent la taille cible.
+func paragraphs(text string) []string {
+	var out []string
+	for _, p := range strings.Split(text, "\n\n") {
+		p = strings.TrimSpace(p)
+		for len(p) > chunkSize {
+			// Coupe après une fin de phrase ou un espace, sinon à la taille cible (sans casser un caractère UTF-8)
+			cut := strings.LastIndexAny(p[:chunkSize], ".!?\n ") + 1
+			if cut < chunkSize/2 {
+				cut = chunkSize
+				for cut > 0 && !utf8.RuneStart(p[cut]) {
+					cut--
+				}
+			}
+			out = append(out, strings.TrimSpace(p[:cut]))
+			p = strings.TrimSpace(p[cut:])
+		}
+		if p != "" {
+			out = append(out, p)
+		}
+	}
+	return out
+}
+
+// pack regroupe les paragraphes en passages d'environ chunkSize caractères. Chaque passage
+// reprend la fin du précédent (chunkOverlap caractères) pour garder le contexte.
+func pack(paras []string) []string {
+	var (
+		out []string
+		cur strings.Builder
+	)
+	for _, p := range paras {
+		if cur.Len() > 0 && cur.Len()+len(p)+2 > chunkSize {
+			prev := cur.String()
+			out = append(out, prev)
+			cur.Reset()
+			if tail := overlap(prev); tail != "" {
+				cur.WriteString(tail + "\n\n")
+			}
+		}
+		if cur.Len() > 0 && !strings.HasSuffix(cur.String(), "\n\n") {
+			cur.WriteString("\n\n")
+		}
+		cur.WriteString(p)
+	}
+	if cur.Len() > 0 {
+		out = append(out, cur.String())
+	}
+	return out
+}
+
+// overlap retourne la fin d'un passage, coupée sur une frontière de mot.
+func overlap(s string) string {
+	if len(s) <= chunkOverlap {
+		return ""
+	}
+	tail := s[len(s)-chunkOverlap:]
+	if i := strings.IndexByte(tail, ' '); i >= 0 {
+		tail = tail[i+1:]
+	}
+	return "…" + strings.TrimSpace(strings.ToValidUTF8(tail, ""))
+}
+
+// String retourne le passage tel que présenté au LLM, avec sa source.
+func (c Chunk) String() string {
+	heading := c.Heading
+	if heading == "" {
+		heading = c.Source
+	}
+	return fmt.Sprintf("[%s — %s]\n%s", c.Source, heading, c.Text)
+}
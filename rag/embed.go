@@ -0,0 +1,144 @@
+package rag
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"time"
+)
+
+// embedBatch est le nombre de textes envoyés par appel à l'endpoint d'embeddings.
+const embedBatch = 32
+
+// Embedder calcule des embeddings via un endpoint compatible OpenAI (/v1/embeddings),
+// proposé par la plupart des fournisseurs comme par les serveurs de modèles locaux
+// (Ollama, llama.cpp, LocalAI...).
+type Embedder struct {
+	apiKey     string
+	url        string
+	model      string
+	httpClient *http.Client
+	logger     *slog.Logger
+}
+
+// embeddingsRequest est le payload envoyé à l'endpoint d'embeddings.
+type embeddingsRequest struct {
+	Model string   `json:"model"`
+	Input []string `json:"input"`
+}
+
+// embeddingsResponse est la réponse de l'endpoint d'embeddings.
+type embeddingsResponse struct {
+	Data []struct {
+		Index     int       `json:"index"`
+		Embedding []float32 `json:"embedding"`
+	} `json:"data"`
+}
+
+// NewEmbedder crée un client d'embeddings. apiKey peut être vide pour un serveur local.
+func NewEmbedder(apiKey, url, model string) *Embedder {
+	return &Embedder{
+		apiKey: apiKey,
+		url:    url,
+		model:  model,
+		httpClient: &http.Client{
+			Timeout: 30 * time.Second,
+		},
+		logger: slog.New(slog.DiscardHandler),
+	}
+}
+
+// SetLogger définit le logger utilisé pour tracer les appels (niveau Debug).
+func (e *Embedder) SetLogger(logger *slog.Logger) {
+	e.logger = logger
+}
+
+// SetTimeout modifie le timeout HTTP d'un appel.
+func (e *Embedder) SetTimeout(d time.Duration) {
+	e.httpClient.Timeout = d
+}
+
+// SetTransport remplace le transport HTTP du client (ex: enregistrement ou rejeu de cassettes).
+func (e *Embedder) SetTransport(rt http.RoundTripper) {
+	e.httpClient.Transport = rt
+}
+
+// Model retourne le modèle d'embeddings utilisé.
+func (e *Embedder) Model() string {
+	return e.model
+}
+
+// Embed retourne l'embedding de chaque texte, dans l'ordre, par lots de embedBatch.
+func (e *Embedder) Embed(ctx context.Context, texts []string) ([][]float32, error) {
+	vectors := make([][]float32, 0, len(texts))
+	for start := 0; start < len(texts); start += embedBatch {
+		batch := texts[start:min(start+embedBatch, len(texts))]
+		vecs, err := e.call(ctx, batch)
+		if err != nil {
+			return nil, err
+		}
+		vectors = append(vectors, vecs...)
+	}
+	return vectors, nil
+}
+
+// call envoie un lot de textes à l'endpoint d'embeddings.
+func (e *Embedder) call(ctx context.Context, texts []string) ([][]float32, error) {
+	body, err := json.Marshal(embeddingsRequest{Model: e.model, Input: texts})
+	if err != nil {
+		return nil, fmt.Errorf("erreur de sérialisation: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, e.url, bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("erreur de création de requête: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if e.apiKey != "" {
+		req.Header.Set("Authorization", "Bearer "+e.apiKey)
+	}
+
+	start := time.Now()
+	resp, err := e.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("erreur réseau embeddings: %w", err)
+	}
+	defer resp.Body.Close()
+
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("erreur de lecture: %w", err)
+	}
+
+	e.logger.Debug("Appel embeddings",
+		slog.String("model", e.model),
+		slog.Int("inputs", len(texts)),
+		slog.Int("status", resp.StatusCode),
+		slog.Duration("duration", time.Since(start)),
+	)
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("embeddings: statut HTTP %d: %s", resp.StatusCode, respBody)
+	}
+
+	var parsed embeddingsResponse
+	if err := json.Unmarshal(respBody, &parsed); err != nil {
+		return nil, fmt.Errorf("erreur de décodage embeddings: %w", err)
+	}
+	if len(parsed.Data) != len(texts) {
+		return nil, fmt.Errorf("embeddings: %d vecteurs reçus pour %d textes", len(parsed.Data), len(texts))
+	}
+
+	vectors := make([][]float32, len(texts))
+	for _, d := range parsed.Data {
+		if d.Index < 0 || d.Index >= len(texts) {
+			return nil, fmt.Errorf("embeddings: index de vecteur invalide %d", d.Index)
+		}
+		vectors[d.Index] = normalize(d.Embedding)
+	}
+	return vectors, nil
+}
@@ -0,0 +1,140 @@
+package rag
+
+import (
+	"fmt"
+	"math"
+	"otom-ai/storage"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Entry est un passage indexé et son embedding (normalisé).
+type Entry struct {
+	Chunk
+	Vector []float32 `json:"vector"`
+}
+
+// Result est un passage trouvé par une recherche, avec sa similarité (cosinus, 0 à 1).
+type Result struct {
+	Chunk
+	Score float32
+}
+
+// snapshot est le contenu persisté de l'index.
+type snapshot struct {
+	Model     string    `json:"model"` // Modèle ayant produit les embeddings
+	Dir       string    `json:"dir"`   // Dossier indexé
+	IndexedAt time.Time `json:"indexed_at"`
+	Entries   []Entry   `json:"entries"`
+}
+
+// Index est l'index vectoriel local, persisté dans un fichier JSON.
+// La recherche est exhaustive : suffisant pour quelques milliers de passages.
+type Index struct {
+	mu   sync.RWMutex
+	file *storage.JSONFile
+	data snapshot
+}
+
+// OpenIndex charge l'index persisté (vide si le fichier n'existe pas encore).
+func OpenIndex(path string) (*Index, error) {
+	idx := &Index{file: storage.NewJSONFile(path)}
+	if err := idx.file.Load(&idx.data); err != nil {
+		return nil, err
+	}
+	return idx, nil
+}
+
+// Len retourne le nombre de passages indexés.
+func (idx *Index) Len() int {
+	idx.mu.RLock()
+	defer idx.mu.RUnlock()
+	return len(idx.data.Entries)
+}
+
+// Model retourne le modèle d'embeddings ayant construit l'index.
+func (idx *Index) Model() string {
+	idx.mu.RLock()
+	defer idx.mu.RUnlock()
+	return idx.data.Model
+}
+
+// Replace remplace tout le contenu de l'index puis le persiste.
+func (idx *Index) Replace(model, dir string, entries []Entry) error {
+	data := snapshot{Model: model, Dir: dir, IndexedAt: time.Now(), Entries: entries}
+	if err := idx.file.Save(data); err != nil {
+		return err
+	}
+	idx.mu.Lock()
+	idx.data = data
+	idx.mu.Unlock()
+	return nil
+}
+
+// ReplaceUnder remplace les passages des documents du sous-dossier sub (sources relatives au
+// dossier indexé) et conserve les autres, puis persiste l'index. L'index doit avoir été construit
+// avec le même modèle : des embeddings de modèles différents ne sont pas comparables.
+func (idx *Index) ReplaceUnder(model, dir, sub string, entries []Entry) error {
+	idx.mu.RLock()
+	current := idx.data
+	idx.mu.RUnlock()
+	if len(current.Entries) > 0 && (current.Model != model || current.Dir != dir) {
+		return fmt.Errorf("index construit avec %q depuis %q : réindexation complète nécessaire", current.Model, current.Dir)
+	}
+
+	prefix := strings.TrimSuffix(sub, "/") + "/"
+	kept := slices.DeleteFunc(slices.Clone(current.Entries), func(e Entry) bool { return strings.HasPrefix(e.Source, prefix) })
+	return idx.Replace(model, dir, append(kept, entries...))
+}
+
+// Search retourne les k passages les plus proches du vecteur (normalisé) donné.
+func (idx *Index) Search(vector []float32, k int) []Result {
+	idx.mu.RLock()
+	defer idx.mu.RUnlock()
+
+	results := make([]Result, 0, len(idx.data.Entries))
+	for _, e := range idx.data.Entries {
+		if len(e.Vector) != len(vector) {
+			continue
+		}
+		results = append(results, Result{Chunk: e.Chunk, Score: dot(e.Vector, vector)})
+	}
+	slices.SortFunc(results, func(a, b Result) int {
+		switch {
+		case a.Score > b.Score:
+			return -1
+		case a.Score < b.Score:
+			return 1
+		}
+		return 0
+	})
+	return results[:min(k, len(results))]
+}
+
+// dot calcule le produit scalaire (= similarité cosinus pour des vecteurs normalisés).
+func dot(a, b []float32) float32 {
+	var sum float32
+	for i := range a {
+		sum += a[i] * b[i]
+	}
+	return sum
+}
+
+// normalize ramène un vecteur à une norme de 1.
+func normalize(v []float32) []float32 {
+	var sum float64
+	for _, x := range v {
+		sum += float64(x) * float64(x)
+	}
+	if sum == 0 {
+		return v
+	}
+	norm := float32(math.Sqrt(sum))
+	out := make([]float32, len(v))
+	for i, x := range v {
+		out[i] = x / norm
+	}
+	return out
+}
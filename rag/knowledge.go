@@ -0,0 +1,168 @@
+package rag
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"otom-ai/readable"
+	"path/filepath"
+	"strings"
+)
+
+// minScore écarte les passages trop éloignés de la question pour être utiles.
+const minScore = 0.3
+
+// KnowledgeBase associe l'index local au client d'embeddings.
+type KnowledgeBase struct {
+	index    *Index
+	embedder *Embedder
+	topK     int
+}
+
+// Stats résume une indexation.
+type Stats struct {
+	Documents int
+	Chunks    int
+	Skipped   []string // Documents illisibles ou vides
+}
+
+// New crée une base de connaissances retournant au plus topK passages par recherche.
+func New(index *Index, embedder *Embedder, topK int) *KnowledgeBase {
+	return &KnowledgeBase{index: index, embedder: embedder, topK: topK}
+}
+
+// Len retourne le nombre de passages indexés.
+func (kb *KnowledgeBase) Len() int {
+	return kb.index.Len()
+}
+
+// Search retourne les passages les plus pertinents pour la requête, formatés pour le LLM
+// avec leur source.
+func (kb *KnowledgeBase) Search(ctx context.Context, query string) (string, error) {
+	if kb.index.Len() == 0 {
+		return "BASE VIDE: aucun document n'est indexé. Utilise l'outil search_internet.", nil
+	}
+	if model := kb.index.Model(); model != kb.embedder.Model() {
+		return "", fmt.Errorf("index construit avec le modèle %q, %q configuré : réindexation nécessaire", model, kb.embedder.Model())
+	}
+
+	vectors, err := kb.embedder.Embed(ctx, []string{query})
+	if err != nil {
+		return "", err
+	}
+
+	var b strings.Builder
+	for _, r := range kb.index.Search(vectors[0], kb.topK) {
+		if r.Score < minScore {
+			break
+		}
+		fmt.Fprintf(&b, "%s\n\n", r.Chunk)
+	}
+	if b.Len() == 0 {
+		return fmt.Sprintf("AUCUN RÉSULTAT pour %q dans la base de connaissances. Utilise l'outil search_internet.", query), nil
+	}
+	return strings.TrimSpace(b.String()), nil
+}
+
+// Reindex reconstruit l'index à partir des documents du dossier dir (.md, .markdown, .txt, .html,
+// .htm, sous-dossiers compris). Avec sub non vide, seuls les documents du sous-dossier dir/sub
+// sont réindexés et remplacent les leurs ; les autres passages sont conservés.
+// Les sources restent relatives à dir.
+func (kb *KnowledgeBase) Reindex(ctx context.Context, dir, sub string) (Stats, error) {
+	var (
+		stats  Stats
+		chunks []Chunk
+	)
+
+	root := dir
+	if sub != "" {
+		root = filepath.Join(dir, sub)
+	}
+	err := filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() || !supported(path) {
+			return nil
+		}
+		rel, _ := filepath.Rel(dir, path)
+		rel = filepath.ToSlash(rel)
+
+		docChunks, err := loadDocument(path, rel)
+		if err != nil || len(docChunks) == 0 {
+			stats.Skipped = append(stats.Skipped, rel)
+			return nil
+		}
+		stats.Documents++
+		chunks = append(chunks, docChunks...)
+		return nil
+	})
+	if err != nil {
+		return stats, fmt.Errorf("parcours de %s: %w", root, err)
+	}
+	if len(chunks) == 0 {
+		return stats, errors.New("aucun document indexable trouvé")
+	}
+
+	texts := make([]string, len(chunks))
+	for i, c := range chunks {
+		texts[i] = c.Heading + "\n" + c.Text // Le titre de section aide à situer le passage
+	}
+	vectors, err := kb.embedder.Embed(ctx, texts)
+	if err != nil {
+		return stats, err
+	}
+
+	entries := make([]Entry, len(chunks))
+	for i, c := range chunks {
+		entries[i] = Entry{Chunk: c, Vector: vectors[i]}
+	}
+	if sub == "" {
+		err = kb.index.Replace(kb.embedder.Model(), dir, entries)
+	} else {
+		err = kb.index.ReplaceUnder(kb.embedder.Model(), dir, filepath.ToSlash(sub), entries)
+	}
+	if err != nil {
+		return stats, err
+	}
+	stats.Chunks = len(entries)
+	return stats, nil
+}
+
+// supported indique si l'extension du fichier est prise en charge.
+func supported(path string) bool {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".md", ".markdown", ".txt", ".html", ".htm":
+		return true
+	}
+	return false
+}
+
+// loadDocument lit un document et le découpe en passages. Le HTML est d'abord
+// converti en Markdown (navigation, scripts et styles retirés).
+func loadDocument(path, source string) ([]Chunk, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	ext := strings.ToLower(filepath.Ext(path))
+	if ext == ".html" || ext == ".htm" {
+		doc, err := readable.ToMarkdown(f)
+		if err != nil {
+			return nil, err
+		}
+		return Split(source, doc.Title, doc.Markdown), nil
+	}
+
+	data, err := io.ReadAll(f)
+	if err != nil {
+		return nil, err
+	}
+	title := strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+	return Split(source, title, string(data)), nil
+}
@@ -0,0 +1,305 @@
+// Package readable convertit des pages HTML en Markdown lisible, débarrassé de la
+// navigation, des scripts et des styles, pour être injecté dans le contexte du LLM.
+package readable
+
+import (
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+)
+
+// skipped liste les éléments dont le contenu n'est jamais du texte utile.
+var skipped = map[atom.Atom]bool{
+	atom.Script: true, atom.Style: true, atom.Noscript: true, atom.Template: true,
+	atom.Iframe: true, atom.Svg: true, atom.Form: true, atom.Button: true,
+	atom.Select: true, atom.Head: true, atom.Nav: true, atom.Footer: true, atom.Aside: true,
+}
+
+// Document est une page HTML convertie.
+type Document struct {
+	Title    string
+	Markdown string
+}
+
+// Parse analyse une page HTML.
+func Parse(r io.Reader) (*html.Node, error) {
+	root, err := html.Parse(r)
+	if err != nil {
+		return nil, fmt.Errorf("analyse HTML: %w", err)
+	}
+	return root, nil
+}
+
+// ToMarkdown convertit une page HTML complète en Markdown.
+func ToMarkdown(r io.Reader) (*Document, error) {
+	root, err := Parse(r)
+	if err != nil {
+		return nil, err
+	}
+	return &Document{Title: Title(root), Markdown: NodeToMarkdown(root)}, nil
+}
+
+// Extract convertit en Markdown uniquement le contenu principal d'une page
+// (article, guide, patch note), sans menus, barres latérales ni pied de page.
+func Extract(r io.Reader) (*Document, error) {
+	root, err := Parse(r)
+	if err != nil {
+		return nil, err
+	}
+	return &Document{Title: Title(root), Markdown: NodeToMarkdown(MainContent(root))}, nil
+}
+
+// MainContent retourne l'élément portant le contenu principal de la page : <main> ou
+// <article> s'ils existent, sinon le bloc dont les paragraphes contiennent le plus de texte.
+func MainContent(root *html.Node) *html.Node {
+	for _, a := range []atom.Atom{atom.Main, atom.Article} {
+		if n := find(root, a); n != nil && TextLength(n) > 200 {
+			return n
+		}
+	}
+
+	// Score de chaque bloc : texte des paragraphes qu'il contient directement
+	scores := map[*html.Node]int{}
+	var candidates []*html.Node // Blocs notés, dans l'ordre du document
+	score := func(n *html.Node, points int) {
+		if _, ok := scores[n]; !ok {
+			candidates = append(candidates, n)
+		}
+		scores[n] += points
+	}
+	var walk func(*html.Node)
+	walk = func(n *html.Node) {
+		if n.Type == html.ElementNode && skipped[n.DataAtom] {
+			return
+		}
+		if n.Type == html.ElementNode && (n.DataAtom == atom.P || n.DataAtom == atom.Pre || n.DataAtom == atom.Li) && n.Parent != nil {
+			score(n.Parent, TextLength(n))
+			if n.Parent.Parent != nil {
+				score(n.Parent.Parent, TextLength(n)/2) // Un conteneur de sections compte aussi
+			}
+		}
+		for c := n.FirstChild; c != nil; c = c.NextSibling {
+			walk(c)
+		}
+	}
+	walk(root)
+
+	// À score égal, le premier bloc du document l'emporte (parcours de la map non déterministe)
+	var best *html.Node
+	for _, n := range candidates {
+		if best == nil || scores[n] > scores[best] {
+			best = n
+		}
+	}
+	if best == nil || scores[best] < 200 {
+		if body := find(root, atom.Body); body != nil {
+			return body
+		}
+		return root
+	}
+	return best
+}
+
+// Title retourne le titre de la page (<title>, sinon premier <h1>).
+func Title(root *html.Node) string {
+	if n := find(root, atom.Title); n != nil {
+		if t := collapse(textOf(n)); t != "" {
+			return t
+		}
+	}
+	if n := find(root, atom.H1); n != nil {
+		return collapse(textOf(n))
+	}
+	return ""
+}
+
+// NodeToMarkdown convertit un sous-arbre HTML en Markdown.
+func NodeToMarkdown(n *html.Node) string {
+	w := &writer{}
+	w.node(n)
+	return tidy(w.b.String())
+}
+
+// writer accumule le Markdown produit lors du parcours de l'arbre.
+type writer struct {
+	b       strings.Builder
+	inPre   bool
+	listDep int
+}
+
+// block garantit une ligne vide avant un bloc (paragraphe, titre, liste...).
+func (w *writer) block() {
+	w.b.WriteString("\n\n")
+}
+
+func (w *writer) children(n *html.Node) {
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		w.node(c)
+	}
+}
+
+func (w *writer) node(n *html.Node) {
+	switch n.Type {
+	case html.TextNode:
+		if w.inPre {
+			w.b.WriteString(n.Data)
+			return
+		}
+		w.b.WriteString(inlineSpace.ReplaceAllString(n.Data, " "))
+		return
+	case html.ElementNode:
+	default:
+		w.children(n)
+		return
+	}
+
+	if skipped[n.DataAtom] {
+		return
+	}
+
+	switch n.DataAtom {
+	case atom.H1, atom.H2, atom.H3, atom.H4, atom.H5, atom.H6:
+		level := int(n.Data[1] - '0')
+		w.block()
+		w.b.WriteString(strings.Repeat("#", level) + " " + collapse(textOf(n)))
+		w.block()
+	case atom.P, atom.Div, atom.Section, atom.Article, atom.Main, atom.Blockquote, atom.Table, atom.Dl:
+		w.block()
+		w.children(n)
+		w.block()
+	case atom.Ul, atom.Ol:
+		w.listDep++
+		w.block()
+		w.children(n)
+		w.block()
+		w.listDep--
+	case atom.Li:
+		w.b.WriteString("\n" + strings.Repeat("  ", max(w.listDep-1, 0)) + "- ")
+		w.children(n)
+	case atom.Tr:
+		w.b.WriteString("\n|")
+		w.children(n)
+	case atom.Td, atom.Th:
+		w.b.WriteString(" " + collapse(textOf(n)) + " |")
+	case atom.Br:
+		w.b.WriteString("\n")
+	case atom.Hr:
+		w.block()
+		w.b.WriteString("---")
+		w.block()
+	case atom.Pre:
+		w.block()
+		w.b.WriteString("```\n")
+		w.inPre = true
+		w.children(n)
+		w.inPre = false
+		w.b.WriteString("\n```")
+		w.block()
+	case atom.Code:
+		if w.inPre {
+			w.children(n)
+			return
+		}
+		w.b.WriteString("`" + collapse(textOf(n)) + "`")
+	case atom.Strong, atom.B:
+		if t := collapse(textOf(n)); t != "" {
+			w.b.WriteString("**" + t + "**")
+		}
+	case atom.Em, atom.I:
+		if t := collapse(textOf(n)); t != "" {
+			w.b.WriteString("_" + t + "_")
+		}
+	case atom.A:
+		text := collapse(textOf(n))
+		href := attr(n, "href")
+		if text == "" {
+			return
+		}
+		if strings.HasPrefix(href, "http://") || strings.HasPrefix(href, "https://") {
+			w.b.WriteString("[" + text + "](" + href + ")")
+		} else {
+			w.b.WriteString(text)
+		}
+	case atom.Img:
+		if alt := attr(n, "alt"); alt != "" {
+			w.b.WriteString("[image: " + alt + "]")
+		}
+	default:
+		w.children(n)
+	}
+}
+
+var (
+	inlineSpace = regexp.MustCompile(`\s+`)
+	blankLines  = regexp.MustCompile(`\n[ \t]*\n(?:[ \t]*\n)+`)
+	trailing    = regexp.MustCompile(`[ \t]+\n`)
+)
+
+// tidy normalise les espaces et les lignes vides du Markdown produit.
+func tidy(s string) string {
+	s = trailing.ReplaceAllString(s, "\n")
+	s = blankLines.ReplaceAllString(s, "\n\n")
+	lines := strings.Split(s, "\n")
+	for i, l := range lines {
+		if !strings.HasPrefix(strings.TrimLeft(l, " "), "- ") {
+			lines[i] = strings.TrimLeft(l, " ")
+		}
+	}
+	return strings.TrimSpace(strings.Join(lines, "\n"))
+}
+
+// collapse réduit les espaces multiples d'un texte en ligne.
+func collapse(s string) string {
+	return strings.TrimSpace(inlineSpace.ReplaceAllString(s, " "))
+}
+
+// textOf retourne le texte brut d'un sous-arbre (hors éléments ignorés).
+func textOf(n *html.Node) string {
+	var b strings.Builder
+	var walk func(*html.Node)
+	walk = func(n *html.Node) {
+		if n.Type == html.ElementNode && skipped[n.DataAtom] {
+			return
+		}
+		if n.Type == html.TextNode {
+			b.WriteString(n.Data)
+		}
+		for c := n.FirstChild; c != nil; c = c.NextSibling {
+			walk(c)
+		}
+	}
+	walk(n)
+	return b.String()
+}
+
+// TextLength retourne la longueur du texte visible d'un sous-arbre.
+func TextLength(n *html.Node) int {
+	return len(collapse(textOf(n)))
+}
+
+// find retourne le premier élément du type donné (parcours en profondeur).
+func find(n *html.Node, a atom.Atom) *html.Node {
+	if n.Type == html.ElementNode && n.DataAtom == a {
+		return n
+	}
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		if found := find(c, a); found != nil {
+			return found
+		}
+	}
+	return nil
+}
+
+// attr retourne la valeur d'un attribut, ou "".
+func attr(n *html.Node, key string) string {
+	for _, a := range n.Attr {
+		if a.Key == key {
+			return a.Val
+		}
+	}
+	return ""
+}
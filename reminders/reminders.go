@@ -0,0 +1,144 @@
+// Package reminders enregistre les rappels demandés par les joueurs ("rappelle-moi samedi
+// 20h de faire le donjon"), persistés dans un fichier JSON pour survivre aux redémarrages.
+package reminders
+
+import (
+	"errors"
+	"fmt"
+	"otom-ai/storage"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+)
+
+const (
+	// MaxPerUser est le nombre de rappels en attente autorisés par joueur.
+	MaxPerUser = 25
+	// MaxText est la longueur maximale du texte d'un rappel.
+	MaxText = 500
+)
+
+// ErrNotFound est retournée quand un rappel n'existe pas.
+var ErrNotFound = errors.New("rappel introuvable")
+
+// Reminder est un rappel à envoyer à un joueur.
+type Reminder struct {
+	ID        string    `json:"id"`
+	GuildID   string    `json:"guild_id"` // "" pour un rappel demandé en message privé
+	ChannelID string    `json:"channel_id"`
+	UserID    string    `json:"user_id"`
+	Text      string    `json:"text"`
+	At        time.Time `json:"at"`
+	DM        bool      `json:"dm"`                // Envoi en message privé plutôt que dans le salon
+	Channel   bool      `json:"channel,omitempty"` // Mentionne tout le salon (@here) et non le seul joueur
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// Store est la base des rappels en attente, persistée dans un fichier JSON.
+type Store struct {
+	mu        sync.Mutex
+	file      *storage.JSONFile
+	reminders map[string]Reminder
+}
+
+// Open charge les rappels (aucun si le fichier n'existe pas encore).
+func Open(path string) (*Store, error) {
+	s := &Store{file: storage.NewJSONFile(path), reminders: map[string]Reminder{}}
+	if err := s.file.Load(&s.reminders); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// Add enregistre un rappel et lui attribue un identifiant court.
+func (s *Store) Add(r Reminder) (Reminder, error) {
+	r.Text = strings.TrimSpace(r.Text)
+	if r.Text == "" {
+		return Reminder{}, errors.New("texte du rappel manquant")
+	}
+	if utf8.RuneCountInString(r.Text) > MaxText {
+		return Reminder{}, fmt.Errorf("texte du rappel trop long (%d caractères maximum)", MaxText)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	pending := 0
+	for _, o := range s.reminders {
+		if o.UserID == r.UserID {
+			pending++
+		}
+	}
+	if pending >= MaxPerUser {
+		return Reminder{}, fmt.Errorf("%d rappels en attente maximum : annule-en avec /rappels annuler", MaxPerUser)
+	}
+
+	for {
+		r.ID = storage.ShortID()
+		if _, exists := s.reminders[r.ID]; !exists {
+			break
+		}
+	}
+	s.reminders[r.ID] = r
+	if err := s.save(); err != nil {
+		delete(s.reminders, r.ID)
+		return Reminder{}, err
+	}
+	return r, nil
+}
+
+// Get retourne un rappel.
+func (s *Store) Get(id string) (Reminder, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	r, ok := s.reminders[id]
+	return r, ok
+}
+
+// Delete supprime un rappel (envoyé ou annulé).
+func (s *Store) Delete(id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	r, ok := s.reminders[id]
+	if !ok {
+		return ErrNotFound
+	}
+	delete(s.reminders, id)
+	if err := s.save(); err != nil {
+		s.reminders[id] = r
+		return err
+	}
+	return nil
+}
+
+// ForUser retourne les rappels en attente d'un joueur, du plus proche au plus lointain.
+func (s *Store) ForUser(userID string) []Reminder {
+	return s.list(func(r Reminder) bool { return r.UserID == userID })
+}
+
+// All retourne tous les rappels en attente, du plus proche au plus lointain.
+func (s *Store) All() []Reminder {
+	return s.list(func(Reminder) bool { return true })
+}
+
+// list retourne les rappels retenus par keep, triés par date.
+func (s *Store) list(keep func(Reminder) bool) []Reminder {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var out []Reminder
+	for _, r := range s.reminders {
+		if keep(r) {
+			out = append(out, r)
+		}
+	}
+	slices.SortFunc(out, func(a, b Reminder) int { return a.At.Compare(b.At) })
+	return out
+}
+
+// save persiste tous les rappels (appelé verrou pris).
+func (s *Store) save() error {
+	return s.file.Save(s.reminders)
+}
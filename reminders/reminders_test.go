@@ -0,0 +1,32 @@
+package reminders
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestForUser(t *testing.T) {
+	s, err := Open(filepath.Join(t.TempDir(), "reminders.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	now := time.Date(2026, 10, 18, 20, 0, 0, 0, time.UTC)
+	for _, r := range []Reminder{
+		{UserID: "1", Text: "Bworker", At: now.Add(2 * time.Hour)},
+		{UserID: "2", Text: "Almanax", At: now.Add(time.Hour)},
+		{UserID: "1", Text: "Almanax", At: now.Add(time.Hour)},
+	} {
+		if _, err := s.Add(r); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	got := s.ForUser("1")
+	if len(got) != 2 || got[0].Text != "Almanax" || got[1].Text != "Bworker" {
+		t.Errorf("ForUser(1) = %+v, attendu Almanax puis Bworker", got)
+	}
+	if got := s.ForUser("3"); len(got) != 0 {
+		t.Errorf("ForUser(3) = %+v, attendu aucun rappel", got)
+	}
+}
@@ -0,0 +1,175 @@
+// Package scheduler exécute des tâches planifiées du bot : tâches quotidiennes à heure fixe
+// (ex: annonce de l'Almanax) et tâches ponctuelles à une date donnée (ex: rappels).
+// Les tâches sont identifiées par un nom unique, ce qui permet de les remplacer ou de les annuler.
+package scheduler
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Job est la fonction exécutée par une tâche planifiée.
+type Job func(ctx context.Context)
+
+// entry est une tâche programmée. id distingue une tâche de celle qui l'a remplacée.
+type entry struct {
+	id    uint64
+	timer *time.Timer
+}
+
+// Scheduler planifie des tâches en mémoire à l'aide de timers.
+type Scheduler struct {
+	mu     sync.Mutex
+	timers map[string]entry
+	lastID uint64
+	ctx    context.Context
+	cancel context.CancelFunc
+	logger *slog.Logger
+}
+
+// New crée un Scheduler actif jusqu'à l'appel de Stop.
+func New(logger *slog.Logger) *Scheduler {
+	ctx, cancel := context.WithCancel(context.Background())
+	return &Scheduler{
+		timers: make(map[string]entry),
+		ctx:    ctx,
+		cancel: cancel,
+		logger: logger,
+	}
+}
+
+// At planifie une exécution unique de job à l'instant t (immédiatement si t est passé).
+// Une tâche existante du même nom est remplacée.
+func (s *Scheduler) At(name string, t time.Time, job Job) {
+	id := s.newID()
+	s.schedule(name, id, false, time.Until(t), func() {
+		if s.forget(name, id) {
+			s.run(name, job)
+		}
+	})
+}
+
+// Daily planifie job tous les jours à l'heure donnée ("HH:MM") dans le fuseau loc.
+// Une tâche existante du même nom est remplacée.
+func (s *Scheduler) Daily(name, at string, loc *time.Location, job Job) error {
+	hour, minute, err := ParseClock(at)
+	if err != nil {
+		return err
+	}
+
+	id := s.newID()
+	var next func(first bool)
+	next = func(first bool) {
+		d := time.Until(NextDaily(time.Now(), hour, minute, loc))
+		s.schedule(name, id, !first, d, func() {
+			s.run(name, job)
+			next(false) // Replanification pour le lendemain (sauf si annulée entre-temps)
+		})
+	}
+	next(true)
+	return nil
+}
+
+// Cancel annule la tâche du nom donné, si elle existe.
+func (s *Scheduler) Cancel(name string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if e, ok := s.timers[name]; ok {
+		e.timer.Stop()
+		delete(s.timers, name)
+	}
+}
+
+// CancelPrefix annule toutes les tâches dont le nom commence par prefix.
+func (s *Scheduler) CancelPrefix(prefix string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for name, e := range s.timers {
+		if strings.HasPrefix(name, prefix) {
+			e.timer.Stop()
+			delete(s.timers, name)
+		}
+	}
+}
+
+// Stop annule toutes les tâches et interrompt celles en cours d'exécution.
+func (s *Scheduler) Stop() {
+	s.cancel()
+	s.CancelPrefix("")
+}
+
+// newID retourne un identifiant de tâche unique.
+func (s *Scheduler) newID() uint64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.lastID++
+	return s.lastID
+}
+
+// schedule (re)programme le timer d'une tâche. Si onlyIfCurrent est vrai (replanification
+// d'une tâche récurrente), rien n'est fait si la tâche a été annulée ou remplacée entre-temps.
+func (s *Scheduler) schedule(name string, id uint64, onlyIfCurrent bool, d time.Duration, fn func()) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.ctx.Err() != nil {
+		return // Scheduler arrêté
+	}
+	e, ok := s.timers[name]
+	if onlyIfCurrent && (!ok || e.id != id) {
+		return
+	}
+	if ok {
+		e.timer.Stop()
+	}
+	s.timers[name] = entry{id: id, timer: time.AfterFunc(max(d, 0), fn)}
+}
+
+// forget retire une tâche ponctuelle au moment de son exécution.
+// Retourne false si elle a été annulée ou remplacée entre-temps.
+func (s *Scheduler) forget(name string, id uint64) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if e, ok := s.timers[name]; !ok || e.id != id {
+		return false
+	}
+	delete(s.timers, name)
+	return true
+}
+
+// run exécute une tâche en isolant ses paniques pour ne pas faire tomber le bot.
+func (s *Scheduler) run(name string, job Job) {
+	defer func() {
+		if r := recover(); r != nil {
+			s.logger.Error("Tâche planifiée en échec", slog.String("job", name), slog.Any("panic", r))
+		}
+	}()
+	s.logger.Debug("Exécution d'une tâche planifiée", slog.String("job", name))
+	job(s.ctx)
+}
+
+// ParseClock analyse une heure au format "HH:MM".
+func ParseClock(at string) (hour, minute int, err error) {
+	t, err := time.Parse("15:04", at)
+	if err != nil {
+		return 0, 0, fmt.Errorf("heure invalide %q (format attendu HH:MM)", at)
+	}
+	return t.Hour(), t.Minute(), nil
+}
+
+// NextDaily retourne la prochaine occurrence (strictement après now) de l'heure donnée dans loc.
+func NextDaily(now time.Time, hour, minute int, loc *time.Location) time.Time {
+	local := now.In(loc)
+	next := time.Date(local.Year(), local.Month(), local.Day(), hour, minute, 0, 0, loc)
+	if !next.After(local) {
+		next = next.AddDate(0, 0, 1)
+	}
+	return next
+}
@@ -7,6 +7,7 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"log/slog"
 	"net/http"
 	"strings"
 	"time"
@@ -15,7 +16,9 @@ import (
 // Client encapsule la connexion à l'API Tavily.
 type Client struct {
 	apiKey     string
+	maxResults int
 	httpClient *http.Client
+	logger     *slog.Logger
 }
 
 // tavilyRequest représente le payload envoyé à l'API Tavily.
@@ -41,14 +44,36 @@ type tavilyResponse struct {
 // NewClient crée un nouveau client Tavily avec un timeout HTTP de 5 secondes.
 func NewClient(apiKey string) *Client {
 	return &Client{
-		apiKey: apiKey,
+		apiKey:     apiKey,
+		maxResults: 3,
 		httpClient: &http.Client{
 			Timeout: 5 * time.Second,
 		},
+		logger: slog.New(slog.DiscardHandler),
 	}
 }
 
-// Search effectue une recherche web et retourne les 3 premiers résultats concaténés.
+// SetLogger définit le logger utilisé pour tracer les recherches (niveau Debug).
+func (c *Client) SetLogger(logger *slog.Logger) {
+	c.logger = logger
+}
+
+// SetTimeout modifie le timeout HTTP d'une recherche.
+func (c *Client) SetTimeout(d time.Duration) {
+	c.httpClient.Timeout = d
+}
+
+// SetMaxResults modifie le nombre de résultats transmis au LLM.
+func (c *Client) SetMaxResults(n int) {
+	c.maxResults = n
+}
+
+// SetTransport remplace le transport HTTP du client (ex: enregistrement ou rejeu de cassettes).
+func (c *Client) SetTransport(rt http.RoundTripper) {
+	c.httpClient.Transport = rt
+}
+
+// Search effectue une recherche web et retourne les premiers résultats concaténés (3 par défaut).
 // En cas d'erreur, retourne l'erreur pour permettre au bot de la logger.
 func (c *Client) Search(ctx context.Context, query string) (string, error) {
 	reqBody := tavilyRequest{
@@ -84,9 +109,14 @@ func (c *Client) Search(ctx context.Context, query string) (string, error) {
 		return fallbackMessage(), fmt.Errorf("décodage réponse Tavily: %w", err)
 	}
 
-	// Concaténation des 3 premiers snippets
+	c.logger.Debug("Recherche Tavily",
+		slog.String("query", query),
+		slog.Int("results", len(tavilyResp.Results)),
+	)
+
+	// Concaténation des premiers snippets
 	var snippets []string
-	limit := min(3, len(tavilyResp.Results))
+	limit := min(c.maxResults, len(tavilyResp.Results))
 	for i := range limit {
 		r := tavilyResp.Results[i]
 		snippets = append(snippets, fmt.Sprintf("- %s: %s", r.Title, r.Content))
@@ -0,0 +1,15 @@
+package storage
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+)
+
+// ShortID génère un identifiant court (6 caractères hexadécimaux), assez lisible pour être
+// recopié dans une commande (/rappels annuler, /donjon). Les collisions sont possibles : le
+// store appelant vérifie l'unicité et retire un identifiant au besoin.
+func ShortID() string {
+	b := make([]byte, 3)
+	_, _ = rand.Read(b)
+	return hex.EncodeToString(b)
+}
@@ -0,0 +1,100 @@
+package storage
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+// JSONLog est un journal JSON Lines en ajout seul : chaque écriture ajoute une ligne au lieu
+// de réécrire tout un fichier. Il complète un JSONFile (l'instantané), qui intègre
+// périodiquement les entrées du journal avant de le vider (compaction).
+type JSONLog struct {
+	mu   sync.Mutex
+	path string
+}
+
+// NewJSONLog crée un accès au journal path (créé au premier ajout).
+func NewJSONLog(path string) *JSONLog {
+	return &JSONLog{path: path}
+}
+
+// Path retourne le chemin du journal.
+func (l *JSONLog) Path() string {
+	return l.path
+}
+
+// Append ajoute v en fin de journal, sur une ligne.
+func (l *JSONLog) Append(v any) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	data, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Errorf("sérialisation de %s: %w", l.path, err)
+	}
+	if err := os.MkdirAll(filepath.Dir(l.path), 0o755); err != nil {
+		return fmt.Errorf("création du dossier de %s: %w", l.path, err)
+	}
+	f, err := os.OpenFile(l.path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+	if err != nil {
+		return fmt.Errorf("ouverture de %s: %w", l.path, err)
+	}
+	if _, err := f.Write(append(data, '\n')); err != nil {
+		f.Close()
+		return fmt.Errorf("écriture de %s: %w", l.path, err)
+	}
+	if err := f.Close(); err != nil {
+		return fmt.Errorf("écriture de %s: %w", l.path, err)
+	}
+	return nil
+}
+
+// Replay décode chaque ligne du journal, dans l'ordre, et la passe à apply. Il retourne le
+// nombre d'entrées rejouées. Un journal absent n'est pas une erreur ; une dernière ligne
+// illisible (arrêt pendant une écriture) est ignorée.
+func Replay[T any](l *JSONLog, apply func(T)) (int, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	data, err := os.ReadFile(l.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, fmt.Errorf("lecture de %s: %w", l.path, err)
+	}
+
+	lines := bytes.Split(bytes.TrimRight(data, "\n"), []byte("\n"))
+	n := 0
+	for i, line := range lines {
+		if len(bytes.TrimSpace(line)) == 0 {
+			continue
+		}
+		var v T
+		if err := json.Unmarshal(line, &v); err != nil {
+			if i == len(lines)-1 {
+				break
+			}
+			return n, fmt.Errorf("décodage de %s, ligne %d: %w", l.path, i+1, err)
+		}
+		apply(v)
+		n++
+	}
+	return n, nil
+}
+
+// Truncate vide le journal, une fois ses entrées intégrées à l'instantané.
+func (l *JSONLog) Truncate() error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if err := os.Remove(l.path); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("vidage de %s: %w", l.path, err)
+	}
+	return nil
+}
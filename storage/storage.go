@@ -0,0 +1,80 @@
+// Package storage fournit la persistance simple du bot : des fichiers JSON écrits de
+// manière atomique (fichier temporaire puis renommage), suffisants pour les volumes d'un
+// bot de guilde et lisibles à la main en cas de besoin, et des journaux JSON Lines en
+// ajout seul pour les écritures fréquentes.
+package storage
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+// JSONFile est un fichier JSON protégé par un mutex.
+type JSONFile struct {
+	mu   sync.Mutex
+	path string
+}
+
+// NewJSONFile crée un accès au fichier path (créé à la première sauvegarde).
+func NewJSONFile(path string) *JSONFile {
+	return &JSONFile{path: path}
+}
+
+// Path retourne le chemin du fichier.
+func (f *JSONFile) Path() string {
+	return f.path
+}
+
+// Load décode le fichier dans v. Un fichier absent n'est pas une erreur : v reste inchangé.
+func (f *JSONFile) Load(v any) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	data, err := os.ReadFile(f.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("lecture de %s: %w", f.path, err)
+	}
+	if err := json.Unmarshal(data, v); err != nil {
+		return fmt.Errorf("décodage de %s: %w", f.path, err)
+	}
+	return nil
+}
+
+// Save encode v et remplace atomiquement le contenu du fichier.
+func (f *JSONFile) Save(v any) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	data, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return fmt.Errorf("sérialisation de %s: %w", f.path, err)
+	}
+	if err := os.MkdirAll(filepath.Dir(f.path), 0o755); err != nil {
+		return fmt.Errorf("création du dossier de %s: %w", f.path, err)
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(f.path), filepath.Base(f.path)+".*.tmp")
+	if err != nil {
+		return fmt.Errorf("création du fichier temporaire: %w", err)
+	}
+	defer os.Remove(tmp.Name()) // Sans effet après un renommage réussi
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return fmt.Errorf("écriture de %s: %w", f.path, err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("écriture de %s: %w", f.path, err)
+	}
+	if err := os.Rename(tmp.Name(), f.path); err != nil {
+		return fmt.Errorf("remplacement de %s: %w", f.path, err)
+	}
+	return nil
+}